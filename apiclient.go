@@ -0,0 +1,212 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewClient creates a Client that sends requests to paths relative to baseURL, e.g.
+// "https://api.example.com/v1". The options are applied once, and used for every call.
+func NewClient(baseURL string, opts ...Opt) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be absolute", baseURL)
+	}
+	c := &Client{
+		BaseURL:    u,
+		drainer:    NewDrainer(),
+		rateLimits: NewRateLimits(),
+	}
+	// The drainer is the outermost interceptor, so that requests are rejected once the
+	// client is closed, before any other interceptor runs.
+	c.config, err = newConfig(append([]Opt{WithDrainer(c.drainer), WithRateLimits(c.rateLimits)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return c, nil
+}
+
+// Client is a Caller that sends requests to paths relative to a base URL, see NewClient.
+//
+// Unlike HTTPCaller, the options passed to NewClient are applied once, so state such as the
+// tokens cached by WithAuthMiddleware is shared by all calls. Options passed to each call are
+// applied after them, to a copy.
+//
+// The client tracks in-flight requests and the rate limits reported by each host, see Close
+// and RateLimit.
+type Client struct {
+	BaseURL    *url.URL
+	config     *Config
+	drainer    *Drainer
+	rateLimits *RateLimits
+}
+
+var _ Caller = (*Client)(nil)
+
+// URL resolves the path against the base URL, see JoinURL. Absolute URLs are returned unchanged.
+func (c *Client) URL(path string) (string, error) {
+	return resolveURL(c.BaseURL, path)
+}
+
+func (c *Client) Get(ctx context.Context, path string, response any, opts ...Opt) (ok bool, err error) {
+	return found(c.Do(ctx, http.MethodGet, path, nil, response, opts...))
+}
+
+func (c *Client) Post(ctx context.Context, path string, request, response any, opts ...Opt) error {
+	return c.Do(ctx, http.MethodPost, path, request, response, opts...)
+}
+
+func (c *Client) Put(ctx context.Context, path string, request, response any, opts ...Opt) error {
+	return c.Do(ctx, http.MethodPut, path, request, response, opts...)
+}
+
+func (c *Client) Patch(ctx context.Context, path string, request, response any, opts ...Opt) error {
+	return c.Do(ctx, http.MethodPatch, path, request, response, opts...)
+}
+
+func (c *Client) Delete(ctx context.Context, path string, response any, opts ...Opt) error {
+	return c.Do(ctx, http.MethodDelete, path, nil, response, opts...)
+}
+
+// Do sends a request with the method to the path. If request is nil, the request has no body.
+// If response is nil, or the response is a 204 or has an empty body, the body is discarded.
+func (c *Client) Do(ctx context.Context, method, path string, request, response any, opts ...Opt) error {
+	config, err := c.config.with(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	target, err := c.URL(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve URL: %w", err)
+	}
+	return config.call(ctx, method, target, request, response)
+}
+
+// Capabilities sends an OPTIONS request to the path, see Capabilities.
+func (c *Client) Capabilities(ctx context.Context, path string, opts ...Opt) (caps EndpointCapabilities, err error) {
+	config, err := c.config.with(opts...)
+	if err != nil {
+		return caps, fmt.Errorf("failed to create config: %w", err)
+	}
+	target, err := c.URL(path)
+	if err != nil {
+		return caps, fmt.Errorf("failed to resolve URL: %w", err)
+	}
+	return config.capabilities(ctx, target)
+}
+
+// Warmup sends a HEAD request to each of the paths, or to the base URL if no paths are
+// given, see Warmup.
+func (c *Client) Warmup(ctx context.Context, paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{c.BaseURL.String()}
+	}
+	urls, err := c.urls(paths)
+	if err != nil {
+		return err
+	}
+	return c.config.warmup(ctx, urls)
+}
+
+// Prefetch sends a GET request to each of the paths, skipping the cache, so that the
+// responses are stored in the cache configured with WithCache, e.g. to load reference data
+// on startup. Returns an error if the client has no cache. Requests with an Authorization
+// header are only stored if the cache allows it, see Cache.Authorized.
+func (c *Client) Prefetch(ctx context.Context, paths ...string) error {
+	urls, err := c.urls(paths)
+	if err != nil {
+		return err
+	}
+	return c.config.prefetch(ctx, urls)
+}
+
+// PrefetchEvery prefetches the paths, and then again every interval, so that they're
+// refreshed before they expire, until the context is cancelled or the client is closed, and
+// returns the context's error, or ErrClosed. Prefetch errors are passed to onError, if not nil.
+func (c *Client) PrefetchEvery(ctx context.Context, interval time.Duration, onError func(err error), paths ...string) error {
+	if c.config.Cache == nil {
+		return errors.New("no cache configured, see WithCache")
+	}
+	for {
+		if err := c.Prefetch(ctx, paths...); err != nil {
+			if errors.Is(err, ErrClosed) {
+				return ErrClosed
+			}
+			if onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+		if err := c.config.clock().Sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) urls(paths []string) (urls []string, err error) {
+	urls = make([]string, len(paths))
+	for i, path := range paths {
+		if urls[i], err = c.URL(path); err != nil {
+			return nil, fmt.Errorf("failed to resolve URL: %w", err)
+		}
+	}
+	return urls, nil
+}
+
+// RateLimit returns the most recent rate limit budget reported by the host, e.g.
+// c.BaseURL.Host. Returns ok=false if the host hasn't returned rate limit headers.
+func (c *Client) RateLimit(host string) (limit RateLimit, ok bool) {
+	return c.rateLimits.RateLimit(host)
+}
+
+// OnClose registers f to be called by Close once in-flight requests have completed,
+// e.g. to stop background token refreshers or health checks.
+func (c *Client) OnClose(f func()) {
+	c.drainer.OnClose(f)
+}
+
+// Close stops new calls, which return ErrClosed, and waits for in-flight requests to
+// complete, or the context to be done, see Drainer.Close.
+func (c *Client) Close(ctx context.Context) error {
+	return c.drainer.Close(ctx)
+}
+
+// Transport returns a http.RoundTripper that sends requests using the client's options.
+// See NewTransport.
+func (c *Client) Transport() http.RoundTripper {
+	return NewTransport(func(config *Config) error {
+		*config = *c.config.clone()
+		return nil
+	})
+}
+
+// with returns a copy of the config with the options applied.
+func (config *Config) with(opts ...Opt) (*Config, error) {
+	c := config.clone()
+	for _, o := range opts {
+		if err := o(c); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// clone returns a copy of the config that options can be applied to without modifying the
+// original. Middleware, such as *AuthMiddleware, is shared.
+func (config *Config) clone() *Config {
+	c := *config
+	c.Middleware = c.Middleware[:len(c.Middleware):len(c.Middleware)]
+	c.CallMiddleware = c.CallMiddleware[:len(c.CallMiddleware):len(c.CallMiddleware)]
+	c.Interceptors = c.Interceptors[:len(c.Interceptors):len(c.Interceptors)]
+	c.TypeHooks = c.TypeHooks[:len(c.TypeHooks):len(c.TypeHooks)]
+	c.transportGuards = c.transportGuards[:len(c.transportGuards):len(c.transportGuards)]
+	c.UnwrapMeta = maps.Clone(c.UnwrapMeta)
+	return &c
+}
@@ -0,0 +1,154 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewClient(t *testing.T) {
+	ctx := context.Background()
+	var received []string
+	routes := createTestRoutes()
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.String())
+		routes.ServeHTTP(w, r)
+	})
+	client, err := jsonapi.NewClient("https://example.com/items", jsonapi.WithClient(testClient{Handler: h}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var _ jsonapi.Caller = client
+
+	t.Run("paths are relative to the base URL", func(t *testing.T) {
+		received = nil
+		var resp itemsGetResponse
+		ok, err := client.Get(ctx, "/get/ok?page=2", &resp)
+		if err != nil || !ok {
+			t.Fatalf("expected ok, got %v, %v", ok, err)
+		}
+		if diff := cmp.Diff(expectedItemsGetResponse, resp); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"https://example.com/items/get/ok?page=2"}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("Get returns ok=false for 404", func(t *testing.T) {
+		ok, err := client.Get(ctx, "get/404", &itemsGetResponse{})
+		if err != nil || ok {
+			t.Errorf("expected not ok, got %v, %v", ok, err)
+		}
+	})
+	t.Run("requests are encoded", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+			var resp map[string]any
+			if err := client.Do(ctx, method, "/"+strings.ToLower(method)+"/ok", map[string]any{"name": "Item 1"}, &resp); err != nil {
+				t.Fatalf("%s: expected no error, got %v", method, err)
+			}
+			if diff := cmp.Diff(map[string]any{"name": "Item 1"}, resp); diff != "" {
+				t.Errorf("%s: %s", method, diff)
+			}
+		}
+	})
+	t.Run("empty responses are not decoded", func(t *testing.T) {
+		var resp map[string]any
+		if err := client.Delete(ctx, "/delete/ok", &resp); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+	t.Run("invalid paths are rejected", func(t *testing.T) {
+		var ipe jsonapi.InvalidPathError
+		if _, err := client.Get(ctx, "/../admin", nil); !errors.As(err, &ipe) {
+			t.Errorf("expected InvalidPathError, got %v", err)
+		}
+	})
+	t.Run("relative base URLs are rejected", func(t *testing.T) {
+		if _, err := jsonapi.NewClient("/items"); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("auth tokens are shared between calls", func(t *testing.T) {
+		claims, _ := json.Marshal(map[string]any{"exp": time.Now().Add(time.Hour).Unix()})
+		var fetches int
+		client, err := jsonapi.NewClient("https://example.com", jsonapi.WithClient(testClient{Handler: routes}),
+			jsonapi.WithAuthMiddleware(func() (string, error) {
+				fetches++
+				return "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature", nil
+			}))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		for range 3 {
+			if _, err := client.Get(ctx, "/items/get/ok", &itemsGetResponse{}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if fetches != 1 {
+			t.Errorf("expected 1 token fetch, got %d", fetches)
+		}
+	})
+}
+
+func TestClientMethods(t *testing.T) {
+	ctx := context.Background()
+	var methods []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Header().Set("Allow", "GET, PATCH")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client, err := jsonapi.NewClient("https://example.com/items", jsonapi.WithClient(testClient{Handler: h}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	t.Run("Capabilities resolves the path", func(t *testing.T) {
+		caps, err := client.Capabilities(ctx, "/1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !caps.Allows(http.MethodPatch) {
+			t.Errorf("expected PATCH to be allowed, got %v", caps.Allow)
+		}
+	})
+	t.Run("Warmup sends HEAD requests to the paths", func(t *testing.T) {
+		methods = nil
+		if err := client.Warmup(ctx, "/1"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]string{"HEAD /items/1"}, methods); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("RateLimit returns the host's budget", func(t *testing.T) {
+		limit, ok := client.RateLimit("example.com")
+		if !ok || limit.Limit != 100 || limit.Remaining != 99 {
+			t.Errorf("unexpected rate limit %+v, ok=%v", limit, ok)
+		}
+	})
+	t.Run("Close stops new calls", func(t *testing.T) {
+		var closed bool
+		client.OnClose(func() { closed = true })
+		if err := client.Close(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !closed {
+			t.Error("expected the OnClose function to be called")
+		}
+		if err := client.Delete(ctx, "/1", nil); !errors.Is(err, jsonapi.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	})
+}
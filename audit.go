@@ -0,0 +1,136 @@
+package jsonapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuditEvent records a mutating request.
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	// Actor identifies who made the request, see Auditor.Actor.
+	Actor string `json:"actor,omitempty"`
+	// Operation is the logical name of the call, see WithOperationName.
+	Operation string `json:"operation,omitempty"`
+	Method    string `json:"method"`
+	URL       string `json:"url"`
+	// RequestHash is the hex encoded SHA-256 hash of the request body.
+	RequestHash string        `json:"requestHash"`
+	Status      int           `json:"status,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// AuditSink stores audit events.
+type AuditSink interface {
+	Audit(ctx context.Context, e AuditEvent) error
+}
+
+// AuditSinkFunc is an adapter to allow the use of ordinary functions as an AuditSink.
+type AuditSinkFunc func(ctx context.Context, e AuditEvent) error
+
+func (f AuditSinkFunc) Audit(ctx context.Context, e AuditEvent) error {
+	return f(ctx, e)
+}
+
+// NewAuditor creates an Auditor that writes events to the sink.
+func NewAuditor(sink AuditSink) *Auditor {
+	return &Auditor{
+		Sink: sink,
+		now:  time.Now,
+	}
+}
+
+// Auditor emits an AuditEvent for each POST, PUT, PATCH and DELETE request.
+type Auditor struct {
+	Sink AuditSink
+	// Actor returns the identity of the caller, e.g. a user ID stored in the request context.
+	Actor func(req *http.Request) string
+	now   func() time.Time
+}
+
+// WithAuditor emits an audit event for each mutating request. If the event can't be written
+// to the sink, the response is discarded and the error is returned.
+//
+// The URL in the event is redacted using the config's Redactor, or NewRedactor if none is set.
+func WithAuditor(a *Auditor) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			r := c.Redactor
+			if r == nil {
+				r = NewRedactor()
+			}
+			return a.intercept(next, r)
+		})
+		return nil
+	}
+}
+
+func (a *Auditor) intercept(next Doer, r *Redactor) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if !isMutatingMethod(req.Method) && req.Method != http.MethodDelete {
+			return next.Do(req)
+		}
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(body)
+		e := AuditEvent{
+			Time:        a.now(),
+			Operation:   OperationName(req.Context()),
+			Method:      req.Method,
+			URL:         r.URL(req.URL),
+			RequestHash: hex.EncodeToString(hash[:]),
+		}
+		if a.Actor != nil {
+			if err = safely(func() error { e.Actor = a.Actor(req); return nil }); err != nil {
+				return nil, err
+			}
+		}
+		res, err := next.Do(req)
+		e.Duration = a.now().Sub(e.Time)
+		if res != nil {
+			e.Status = res.StatusCode
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+		if aerr := safely(func() error { return a.Sink.Audit(context.WithoutCancel(req.Context()), e) }); aerr != nil {
+			if res != nil {
+				res.Body.Close()
+			}
+			return nil, fmt.Errorf("failed to write audit event: %w", aerr)
+		}
+		return res, err
+	})
+}
+
+// NewJSONAuditSink creates an AuditSink that writes each event to w as a line of JSON.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{w: w, m: &sync.Mutex{}}
+}
+
+// JSONAuditSink writes each event as a line of JSON.
+type JSONAuditSink struct {
+	w io.Writer
+	m *sync.Mutex
+}
+
+func (s *JSONAuditSink) Audit(ctx context.Context, e AuditEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,85 @@
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithAuditor(t *testing.T) {
+	var buf bytes.Buffer
+	auditor := jsonapi.NewAuditor(jsonapi.NewJSONAuditSink(&buf))
+	auditor.Actor = func(req *http.Request) string { return "user-1" }
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuditor(auditor),
+	}
+	ctx := context.Background()
+
+	if _, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok", opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/items/post/ok", map[string]any{"name": "Item 1"}, opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var events []jsonapi.AuditEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e jsonapi.AuditEvent
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event for the POST, got %d", len(events))
+	}
+	e := events[0]
+	if e.Actor != "user-1" || e.Method != http.MethodPost || e.URL != "/items/post/ok" || e.Status != http.StatusCreated {
+		t.Errorf("unexpected event %+v", e)
+	}
+	// SHA-256 of {"name":"Item 1"}.
+	if expected := "49836ac17c29e6413044ee7d54b2a219b30cf302f8551b285fa23e8e3049b48d"; e.RequestHash != expected {
+		t.Errorf("expected request hash %q, got %q", expected, e.RequestHash)
+	}
+}
+
+func TestWithAuditorDelete(t *testing.T) {
+	var events []jsonapi.AuditEvent
+	auditor := jsonapi.NewAuditor(jsonapi.AuditSinkFunc(func(ctx context.Context, e jsonapi.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}))
+	_, err := jsonapi.Delete[map[string]any](context.Background(), "/items/delete/ok?id=1&token=secret",
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuditor(auditor))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event for the DELETE, got %d", len(events))
+	}
+	e := events[0]
+	if e.Method != http.MethodDelete || e.URL != "/items/delete/ok?id=1&token=%5BREDACTED%5D" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+func TestWithAuditorSinkError(t *testing.T) {
+	sinkErr := errors.New("sink unavailable")
+	auditor := jsonapi.NewAuditor(jsonapi.AuditSinkFunc(func(ctx context.Context, e jsonapi.AuditEvent) error {
+		return sinkErr
+	}))
+	_, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "/items/post/ok", map[string]any{},
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuditor(auditor))
+	if !errors.Is(err, sinkErr) {
+		t.Errorf("expected the sink error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package jsonapi
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -12,9 +13,12 @@ import (
 
 // WithAuthMiddleware returns an Opt that adds authentication middleware to the client.
 // The tokenFetcher should return the access token to be used in the Authorization header.
+// Token expiry is checked using the clock set by WithClock.
 func WithAuthMiddleware(tokenFetcher func() (string, error)) Opt {
 	return func(c *Config) error {
-		c.Middleware = append(c.Middleware, newAuthMiddleware(tokenFetcher))
+		m := newAuthMiddleware(tokenFetcher)
+		m.now = func() time.Time { return c.clock().Now() }
+		c.Middleware = append(c.Middleware, m)
 		return nil
 	}
 }
@@ -35,6 +39,11 @@ type AuthMiddleware struct {
 	// "header.payload.signature".
 	TokenFetcher func() (string, error)
 	MinRemaining time.Duration
+	// Validate, if set, is called with each fetched token before it's cached, see WithTokenValidator.
+	Validate func(ctx context.Context, token string) error
+	// OnTokenEvent, if set, is called when a token is fetched, refreshed, or fails to be
+	// fetched, see WithTokenEvents.
+	OnTokenEvent func(e TokenEvent)
 	token        string
 	expires      time.Time
 	now          func() time.Time
@@ -48,24 +57,58 @@ func (m *AuthMiddleware) Request(req *http.Request) (err error) {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.token == "" || m.expires.IsZero() || m.expires.Before(m.now().Add(-m.MinRemaining)) {
-		m.token, err = m.TokenFetcher()
+		e := TokenEvent{Type: TokenFetched}
+		if !m.expires.IsZero() {
+			e.Type = TokenRefreshed
+		}
+		start := m.now()
+		err = m.fetch(req)
+		e.Latency = m.now().Sub(start)
+		e.Expires = m.expires
 		if err != nil {
-			m.token = ""
-			return fmt.Errorf("failed to fetch token: %w", err)
+			e.Type = TokenFetchFailed
+			e.Err = err
 		}
-		if strings.HasPrefix(m.token, "Bearer ") {
-			m.token = strings.TrimPrefix(m.token, "Bearer ")
+		if m.OnTokenEvent != nil {
+			if nerr := safely(func() error { m.OnTokenEvent(e); return nil }); nerr != nil && err == nil {
+				err = nerr
+			}
 		}
-		m.expires, err = getExpiry(m.token)
 		if err != nil {
-			m.expires = time.Time{}
-			return fmt.Errorf("failed to get expiry: %w", err)
+			return err
 		}
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", m.token))
 	return nil
 }
 
+func (m *AuthMiddleware) fetch(req *http.Request) (err error) {
+	m.token, err = m.TokenFetcher()
+	if err != nil {
+		m.token = ""
+		return fmt.Errorf("failed to fetch token: %w", err)
+	}
+	if strings.HasPrefix(m.token, "Bearer ") {
+		m.token = strings.TrimPrefix(m.token, "Bearer ")
+	}
+	if m.Validate != nil {
+		ctx := context.Background()
+		if req != nil {
+			ctx = req.Context()
+		}
+		if err = m.Validate(ctx, m.token); err != nil {
+			m.token = ""
+			return fmt.Errorf("failed to validate token: %w", err)
+		}
+	}
+	m.expires, err = getExpiry(m.token)
+	if err != nil {
+		m.expires = time.Time{}
+		return fmt.Errorf("failed to get expiry: %w", err)
+	}
+	return nil
+}
+
 func (m *AuthMiddleware) Response(res *http.Response) error {
 	return nil
 }
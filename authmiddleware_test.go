@@ -1,6 +1,7 @@
 package jsonapi
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"net/http"
@@ -209,3 +210,57 @@ func TestWithAuthMiddleware(t *testing.T) {
 		t.Error("expected the auth middleware to be added to the config, but it wasn't")
 	}
 }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func (c fixedClock) Sleep(ctx context.Context, d time.Duration) error {
+	return nil
+}
+
+func TestWithAuthMiddlewareClock(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := &Config{}
+	for _, opt := range []Opt{WithAuthMiddleware(func() (string, error) { return "", nil }), WithClock(fixedClock(now))} {
+		if err := opt(config); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	amw, ok := config.Middleware[0].(*AuthMiddleware)
+	if !ok {
+		t.Fatalf("expected *AuthMiddleware, got %T", config.Middleware[0])
+	}
+	if actual := amw.now(); !actual.Equal(now) {
+		t.Errorf("expected token expiry to use the clock time %v, got %v", now, actual)
+	}
+}
+
+func TestAuthMiddlewareTokenEvents(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	claims, err := json.Marshal(map[string]any{"exp": now.Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("failed to marshal claims: %v", err)
+	}
+	m := newAuthMiddleware(func() (string, error) {
+		return "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature", nil
+	})
+	m.now = func() time.Time { return now }
+	var events []TokenEventType
+	m.OnTokenEvent = func(e TokenEvent) { events = append(events, e.Type) }
+
+	for range 2 {
+		if err := m.Request(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if err := m.Request(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 2 || events[0] != TokenFetched || events[1] != TokenRefreshed {
+		t.Errorf("expected fetched then refreshed events, got %v", events)
+	}
+}
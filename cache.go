@@ -0,0 +1,283 @@
+package jsonapi
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewCache creates an in-memory cache of up to maxEntries GET responses, see Cache. When the
+// cache is full, the least recently used response is evicted. A maxEntries of zero or less
+// means that the number of responses isn't limited.
+func NewCache(maxEntries int) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		vary:       map[string]cacheVary{},
+		lru:        list.New(),
+		m:          &sync.Mutex{},
+	}
+}
+
+// Cache stores 200 OK responses to GET requests, and serves them until they expire. The
+// expiry is taken from the Cache-Control max-age directive, or the Expires header, or TTL
+// if neither is set. Responses with a Cache-Control no-store or no-cache directive, or a
+// Vary header of "*", aren't stored. Requests with a Cache-Control no-cache directive skip
+// the cache, but store the response, see Client.Prefetch.
+//
+// Responses are keyed by URL, the Accept and Accept-Language request headers, and the request
+// headers listed in the response's Vary header.
+type Cache struct {
+	// TTL is how long responses without a max-age or Expires header are stored for. If zero,
+	// they aren't stored.
+	TTL time.Duration
+	// Authorized stores responses to requests with an Authorization header, keyed by a hash of
+	// the header, so that callers with different credentials don't share responses. By default,
+	// requests with an Authorization header skip the cache.
+	Authorized bool
+	maxEntries int
+	entries    map[string]*list.Element
+	vary       map[string]cacheVary
+	lru        *list.List
+	m          *sync.Mutex
+}
+
+type cacheEntry struct {
+	key     string
+	url     string
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// cacheVary is the Vary header of the most recent response for a URL, and the number of
+// entries stored for the URL.
+type cacheVary struct {
+	names   []string
+	entries int
+}
+
+// WithCache serves GET requests from the cache, using the config's Clock for expiry.
+// Create the cache once and share it between calls.
+func WithCache(cache *Cache) Opt {
+	return func(c *Config) error {
+		c.Cache = cache
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return cache.intercept(next, c.clock())
+		})
+		return nil
+	}
+}
+
+// Len returns the number of cached responses, including those that have expired but haven't
+// been requested or evicted since.
+func (cache *Cache) Len() int {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	return cache.lru.Len()
+}
+
+func (cache *Cache) get(req *http.Request, now time.Time) (e *cacheEntry, ok bool) {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	url := req.URL.String()
+	v, ok := cache.vary[url]
+	if !ok {
+		return nil, false
+	}
+	elem, ok := cache.entries[cache.key(req, url, v.names)]
+	if !ok {
+		return nil, false
+	}
+	e = elem.Value.(*cacheEntry)
+	if !now.Before(e.expires) {
+		cache.remove(elem)
+		return nil, false
+	}
+	cache.lru.MoveToFront(elem)
+	return e, true
+}
+
+func (cache *Cache) set(req *http.Request, names []string, e *cacheEntry) {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	e.url = req.URL.String()
+	e.key = cache.key(req, e.url, names)
+	if elem, ok := cache.entries[e.key]; ok {
+		cache.remove(elem)
+	}
+	v := cache.vary[e.url]
+	cache.vary[e.url] = cacheVary{names: names, entries: v.entries + 1}
+	cache.entries[e.key] = cache.lru.PushFront(e)
+	for cache.maxEntries > 0 && cache.lru.Len() > cache.maxEntries {
+		cache.remove(cache.lru.Back())
+	}
+}
+
+func (cache *Cache) remove(elem *list.Element) {
+	e := elem.Value.(*cacheEntry)
+	cache.lru.Remove(elem)
+	delete(cache.entries, e.key)
+	v := cache.vary[e.url]
+	if v.entries--; v.entries <= 0 {
+		delete(cache.vary, e.url)
+		return
+	}
+	cache.vary[e.url] = v
+}
+
+// key returns the key of the request, made from the URL, and the values of the request headers
+// that select the response.
+func (cache *Cache) key(req *http.Request, url string, names []string) string {
+	var sb strings.Builder
+	sb.WriteString(url)
+	for _, name := range append([]string{"Accept", "Accept-Language"}, names...) {
+		sb.WriteString("\n" + name + ": " + strings.Join(req.Header.Values(name), ", "))
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		hash := sha256.Sum256([]byte(auth))
+		sb.WriteString("\nAuthorization: " + hex.EncodeToString(hash[:]))
+	}
+	return sb.String()
+}
+
+// varyNames returns the request header names listed in the Vary header, or ok=false if the
+// response varies on something other than request headers.
+func varyNames(h http.Header) (names []string, ok bool) {
+	for _, name := range headerList(h, "Vary") {
+		if name == "*" {
+			return nil, false
+		}
+		name = http.CanonicalHeaderKey(name)
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, true
+}
+
+// ttl returns how long the response can be stored for.
+func (cache *Cache) ttl(h http.Header, now time.Time) (ttl time.Duration, ok bool) {
+	for _, directive := range headerList(h, "Cache-Control") {
+		name, value, _ := strings.Cut(strings.ToLower(directive), "=")
+		switch name {
+		case "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			return time.Duration(seconds) * time.Second, err == nil && seconds > 0
+		}
+	}
+	if expires := h.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		return t.Sub(now), err == nil && t.After(now)
+	}
+	return cache.TTL, cache.TTL > 0
+}
+
+func (cache *Cache) intercept(next Doer, clock Clock) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet || (req.Header.Get("Authorization") != "" && !cache.Authorized) {
+			return next.Do(req)
+		}
+		if !hasNoCache(req.Header) {
+			if e, ok := cache.get(req, clock.Now()); ok {
+				return e.response(req), nil
+			}
+		}
+		res, err := next.Do(req)
+		if err != nil || res.StatusCode != http.StatusOK {
+			return res, err
+		}
+		now := clock.Now()
+		ttl, ok := cache.ttl(res.Header, now)
+		if !ok {
+			return res, nil
+		}
+		names, ok := varyNames(res.Header)
+		if !ok {
+			return res, nil
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		cache.set(req, names, &cacheEntry{header: res.Header.Clone(), body: body, expires: now.Add(ttl)})
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		return res, nil
+	})
+}
+
+func hasNoCache(h http.Header) bool {
+	for _, directive := range headerList(h, "Cache-Control") {
+		if strings.EqualFold(directive, "no-cache") {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// prefetch requests each of the URLs, skipping the cache, so that the responses are stored.
+func (config *Config) prefetch(ctx context.Context, urls []string) error {
+	if config.Cache == nil {
+		return errors.New("no cache configured, see WithCache")
+	}
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := prefetchURL(ctx, config, url); err != nil {
+				errs[i] = fmt.Errorf("failed to prefetch %q: %w", url, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func prefetchURL(ctx context.Context, config *Config, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+	res, err := config.raw(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return config.newStatusError(res.StatusCode, res.Header, config.readErrorBody(res.Body))
+	}
+	_, err = io.Copy(io.Discard, res.Body)
+	return err
+}
@@ -0,0 +1,303 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	handler := func(calls *int, header http.Header) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"items":["1"]}`))
+		})
+	}
+	get := func(h http.Handler, opts ...jsonapi.Opt) error {
+		opts = append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h})}, opts...)
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "https://example.com/items", opts...)
+		return err
+	}
+	tests := []struct {
+		name          string
+		header        http.Header
+		ttl           time.Duration
+		expectedCalls int
+	}{
+		{name: "max-age responses are cached", header: http.Header{"Cache-Control": {"public, max-age=60"}}, expectedCalls: 1},
+		{name: "Expires responses are cached", header: http.Header{"Expires": {"Wed, 01 Jan 2020 00:01:00 GMT"}}, expectedCalls: 1},
+		{name: "past Expires responses are not cached", header: http.Header{"Expires": {"Tue, 31 Dec 2019 00:00:00 GMT"}}, expectedCalls: 2},
+		{name: "no-store responses are not cached", header: http.Header{"Cache-Control": {"no-store"}}, ttl: time.Minute, expectedCalls: 2},
+		{name: "no-cache responses are not cached", header: http.Header{"Cache-Control": {"no-cache"}}, ttl: time.Minute, expectedCalls: 2},
+		{name: "responses without freshness information use the TTL", ttl: time.Minute, expectedCalls: 1},
+		{name: "responses without freshness information are not cached by default", expectedCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			cache := jsonapi.NewCache(100)
+			cache.TTL = tt.ttl
+			clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+			h := handler(&calls, tt.header)
+			for range 2 {
+				if err := get(h, jsonapi.WithCache(cache), jsonapi.WithClock(clock)); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+	t.Run("cached responses expire", func(t *testing.T) {
+		var calls int
+		cache := jsonapi.NewCache(100)
+		clock := &fakeClock{now: time.Now()}
+		h := handler(&calls, http.Header{"Cache-Control": {"max-age=60"}})
+		for _, wait := range []time.Duration{0, 59 * time.Second, time.Second} {
+			clock.Sleep(ctx, wait)
+			if err := get(h, jsonapi.WithCache(cache), jsonapi.WithClock(clock)); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if calls != 2 {
+			t.Errorf("expected the expired response to be requested again, got %d calls", calls)
+		}
+	})
+	t.Run("no-cache requests skip the cache, and store the response", func(t *testing.T) {
+		var calls int
+		cache := jsonapi.NewCache(100)
+		h := handler(&calls, http.Header{"Cache-Control": {"max-age=60"}})
+		if err := get(h, jsonapi.WithCache(cache)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := get(h, jsonapi.WithCache(cache), jsonapi.WithRequestHeader("Cache-Control", "no-cache")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if calls != 2 || cache.Len() != 1 {
+			t.Errorf("expected 2 calls and 1 cached response, got %d calls and %d responses", calls, cache.Len())
+		}
+	})
+	t.Run("responses are keyed by the request headers they vary on", func(t *testing.T) {
+		var calls int
+		cache := jsonapi.NewCache(100)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "X-Tenant")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"items":["` + r.Header.Get("Accept-Language") + r.Header.Get("X-Tenant") + `"]}`))
+		})
+		for _, tt := range []struct {
+			opts     []jsonapi.Opt
+			expected string
+		}{
+			{opts: []jsonapi.Opt{jsonapi.WithLanguage("en")}, expected: "en"},
+			{opts: []jsonapi.Opt{jsonapi.WithLanguage("fr")}, expected: "fr"},
+			{opts: []jsonapi.Opt{jsonapi.WithLanguage("en")}, expected: "en"},
+			{opts: []jsonapi.Opt{jsonapi.WithRequestHeader("X-Tenant", "a")}, expected: "a"},
+			{opts: []jsonapi.Opt{jsonapi.WithRequestHeader("X-Tenant", "b")}, expected: "b"},
+			{opts: []jsonapi.Opt{jsonapi.WithRequestHeader("X-Tenant", "a")}, expected: "a"},
+		} {
+			opts := append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithCache(cache)}, tt.opts...)
+			resp, _, err := jsonapi.Get[itemsGetResponse](ctx, "https://example.com/items", opts...)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(resp.Items) != 1 || resp.Items[0] != tt.expected {
+				t.Errorf("expected %q, got %v", tt.expected, resp.Items)
+			}
+		}
+		if calls != 4 {
+			t.Errorf("expected 4 calls, got %d", calls)
+		}
+	})
+	t.Run("requests with credentials skip the cache unless authorized", func(t *testing.T) {
+		var calls int
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"items":["` + r.Header.Get("Authorization") + `"]}`))
+		})
+		for _, authorized := range []bool{false, true} {
+			calls = 0
+			cache := jsonapi.NewCache(100)
+			cache.Authorized = authorized
+			for _, auth := range []string{"Bearer a", "Bearer b", "Bearer a"} {
+				resp, _, err := jsonapi.Get[itemsGetResponse](ctx, "https://example.com/items",
+					jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithCache(cache), jsonapi.WithAuthorization(auth))
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(resp.Items) != 1 || resp.Items[0] != auth {
+					t.Errorf("expected the response for %q, got %v", auth, resp.Items)
+				}
+			}
+			expectedCalls := 3
+			if authorized {
+				expectedCalls = 2
+			}
+			if calls != expectedCalls {
+				t.Errorf("authorized=%v: expected %d calls, got %d", authorized, expectedCalls, calls)
+			}
+		}
+	})
+	t.Run("Vary * responses are not cached", func(t *testing.T) {
+		var calls int
+		cache := jsonapi.NewCache(100)
+		h := handler(&calls, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}})
+		for range 2 {
+			if err := get(h, jsonapi.WithCache(cache)); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if calls != 2 {
+			t.Errorf("expected 2 calls, got %d", calls)
+		}
+	})
+	t.Run("the least recently used responses are evicted", func(t *testing.T) {
+		var calls []string
+		cache := jsonapi.NewCache(2)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.URL.Path)
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"items":["1"]}`))
+		})
+		for _, path := range []string{"/a", "/b", "/a", "/c", "/a", "/b"} {
+			if _, _, err := jsonapi.Get[itemsGetResponse](ctx, "https://example.com"+path,
+				jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithCache(cache)); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if diff := cmp.Diff([]string{"/a", "/b", "/c", "/b"}, calls); diff != "" {
+			t.Error(diff)
+		}
+		if cache.Len() != 2 {
+			t.Errorf("expected 2 cached responses, got %d", cache.Len())
+		}
+	})
+	t.Run("only successful GET requests are cached", func(t *testing.T) {
+		var calls int
+		cache := jsonapi.NewCache(100)
+		cache.TTL = time.Minute
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			http.Error(w, "error", http.StatusInternalServerError)
+		})
+		for range 2 {
+			if err := get(h, jsonapi.WithCache(cache)); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, err := jsonapi.Post[itemsGetResponse, itemsGetResponse](ctx, "https://example.com/items", itemsGetResponse{},
+				jsonapi.WithClient(testClient{Handler: handler(&calls, nil)}), jsonapi.WithCache(cache)); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if calls != 4 || cache.Len() != 0 {
+			t.Errorf("expected 4 calls and no cached responses, got %d calls and %d responses", calls, cache.Len())
+		}
+	})
+}
+
+func TestClientPrefetch(t *testing.T) {
+	ctx := context.Background()
+	var received []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		if r.URL.Path == "/items/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":["1"]}`))
+	})
+	newClient := func(opts ...jsonapi.Opt) *jsonapi.Client {
+		client, err := jsonapi.NewClient("https://example.com/items", append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h})}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("prefetched responses are served from the cache", func(t *testing.T) {
+		received = nil
+		client := newClient(jsonapi.WithCache(jsonapi.NewCache(100)))
+		if err := client.Prefetch(ctx, "/1"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var resp itemsGetResponse
+		if ok, err := client.Get(ctx, "/1", &resp); !ok || err != nil {
+			t.Fatalf("expected ok, got %v, %v", ok, err)
+		}
+		if len(received) != 1 || len(resp.Items) != 1 {
+			t.Errorf("expected a single request, and a decoded response, got %v and %+v", received, resp)
+		}
+	})
+	t.Run("prefetch errors are returned", func(t *testing.T) {
+		client := newClient(jsonapi.WithCache(jsonapi.NewCache(100)))
+		err := client.Prefetch(ctx, "/1", "/missing")
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusNotFound || !strings.Contains(err.Error(), "/items/missing") {
+			t.Errorf("expected a 404 error for the missing path, got %v", err)
+		}
+	})
+	t.Run("a cache is required", func(t *testing.T) {
+		client := newClient()
+		if err := client.Prefetch(ctx, "/1"); err == nil {
+			t.Error("expected an error")
+		}
+		if err := client.PrefetchEvery(ctx, time.Minute, nil, "/1"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("PrefetchEvery refreshes the paths until the context is cancelled", func(t *testing.T) {
+		received = nil
+		ctx, cancel := context.WithCancel(ctx)
+		clock := &cancellingClock{fakeClock: fakeClock{now: time.Now()}, after: 3, cancel: cancel}
+		client := newClient(jsonapi.WithCache(jsonapi.NewCache(100)), jsonapi.WithClock(clock))
+		var errs []error
+		err := client.PrefetchEvery(ctx, 30*time.Second, func(err error) { errs = append(errs, err) }, "/1", "/missing")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if len(received) != 6 || len(errs) != 3 {
+			t.Errorf("expected 3 refreshes of 2 paths, with 3 errors, got %v, and %d errors", received, len(errs))
+		}
+	})
+	t.Run("PrefetchEvery stops when the client is closed", func(t *testing.T) {
+		client := newClient(jsonapi.WithCache(jsonapi.NewCache(100)))
+		client.Close(ctx)
+		if err := client.PrefetchEvery(ctx, time.Minute, nil, "/1"); !errors.Is(err, jsonapi.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	})
+}
+
+// cancellingClock cancels the context after the given number of sleeps.
+type cancellingClock struct {
+	fakeClock
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.fakeClock.Sleep(ctx, d)
+	if len(c.sleeps) >= c.after {
+		c.cancel()
+	}
+	return ctx.Err()
+}
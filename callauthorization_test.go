@@ -0,0 +1,33 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithCallAuthorization(t *testing.T) {
+	var fetched int
+	clientOpts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuthMiddleware(func() (string, error) {
+			fetched++
+			return "service.token.signature", nil
+		}),
+	}
+
+	_, ok, err := jsonapi.Get[itemsGetResponse](context.Background(), "/auth/items/get/ok", append(clientOpts, jsonapi.WithCallAuthorization("Bearer abc"))...)
+	if err != nil || !ok {
+		t.Fatalf("expected ok, got %v, %v", ok, err)
+	}
+	if fetched != 0 {
+		t.Errorf("expected the auth middleware to be skipped, but a token was fetched %d times", fetched)
+	}
+
+	_, _, err = jsonapi.Get[itemsGetResponse](context.Background(), "/auth/items/get/ok", jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuthorization("Bearer service"), jsonapi.WithCallAuthorization("Bearer abc"))
+	if err != nil {
+		t.Fatalf("expected the call authorization to override the Authorization header, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Caller makes JSON API calls with untyped request and response values.
+// Application code can accept a Caller, so that tests can substitute a mock
+// such as jsonapitest.Mock without making HTTP requests.
+type Caller interface {
+	// Get decodes the response into response, which must be a pointer.
+	// Returns ok=false if the response was a 404.
+	Get(ctx context.Context, url string, response any, opts ...Opt) (ok bool, err error)
+	Post(ctx context.Context, url string, request, response any, opts ...Opt) error
+	Put(ctx context.Context, url string, request, response any, opts ...Opt) error
+	// Delete leaves response unchanged if the response is a 204, or has an empty body.
+	Delete(ctx context.Context, url string, response any, opts ...Opt) error
+}
+
+// NewCaller creates a Caller that makes HTTP requests. The options are applied to every call,
+// followed by the options passed to the call.
+func NewCaller(opts ...Opt) *HTTPCaller {
+	return &HTTPCaller{
+		Opts: opts,
+	}
+}
+
+// HTTPCaller is a Caller that makes HTTP requests.
+type HTTPCaller struct {
+	Opts []Opt
+}
+
+var _ Caller = (*HTTPCaller)(nil)
+
+func (c *HTTPCaller) Get(ctx context.Context, url string, response any, opts ...Opt) (ok bool, err error) {
+	return found(c.call(ctx, http.MethodGet, url, nil, response, opts))
+}
+
+func (c *HTTPCaller) Post(ctx context.Context, url string, request, response any, opts ...Opt) error {
+	return c.call(ctx, http.MethodPost, url, request, response, opts)
+}
+
+func (c *HTTPCaller) Put(ctx context.Context, url string, request, response any, opts ...Opt) error {
+	return c.call(ctx, http.MethodPut, url, request, response, opts)
+}
+
+func (c *HTTPCaller) Delete(ctx context.Context, url string, response any, opts ...Opt) error {
+	return c.call(ctx, http.MethodDelete, url, nil, response, opts)
+}
+
+func (c *HTTPCaller) call(ctx context.Context, method, url string, request, response any, opts []Opt) error {
+	config, err := newConfig(append(c.Opts[:len(c.Opts):len(c.Opts)], opts...)...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	return config.call(ctx, method, url, request, response)
+}
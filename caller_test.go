@@ -0,0 +1,74 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCaller(t *testing.T) {
+	var caller jsonapi.Caller = jsonapi.NewCaller(jsonapi.WithClient(testClient{Handler: createTestRoutes()}))
+	ctx := context.Background()
+
+	t.Run("Get decodes the response", func(t *testing.T) {
+		var resp itemsGetResponse
+		ok, err := caller.Get(ctx, "/items/get/ok", &resp)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !ok {
+			t.Error("expected ok")
+		}
+		if diff := cmp.Diff(expectedItemsGetResponse, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("Get returns ok=false for 404", func(t *testing.T) {
+		var resp itemsGetResponse
+		ok, err := caller.Get(ctx, "/items/get/404", &resp)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if ok {
+			t.Error("expected not ok")
+		}
+	})
+	t.Run("Post encodes the request", func(t *testing.T) {
+		var resp map[string]any
+		err := caller.Post(ctx, "/items/post/ok", map[string]any{"name": "Item 1"}, &resp)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "Item 1"}, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("Delete accepts responses without a body", func(t *testing.T) {
+		for _, url := range []string{"/items/delete/ok", "/items/delete/chunked"} {
+			var resp map[string]any
+			if err := caller.Delete(ctx, url, &resp); err != nil {
+				t.Errorf("%s: expected no error, got %v", url, err)
+			}
+			if resp != nil {
+				t.Errorf("%s: expected a nil response, got %v", url, resp)
+			}
+		}
+	})
+	t.Run("Delete decodes the response", func(t *testing.T) {
+		var resp map[string]any
+		if err := caller.Delete(ctx, "/items/delete/body", &resp); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"deleted": true}, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("Post returns status errors", func(t *testing.T) {
+		err := caller.Post(ctx, "/items/post/500", map[string]any{}, nil)
+		if _, ok := err.(jsonapi.InvalidStatusError); !ok {
+			t.Errorf("expected InvalidStatusError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+type signingMiddleware string
+
+func (m signingMiddleware) Request(req *http.Request) error {
+	req.Header.Set("Signature", string(m)+":"+req.Header.Get("X-Nonce"))
+	return nil
+}
+
+func (m signingMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+func TestWithCallMiddleware(t *testing.T) {
+	var signature string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("Signature")
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	standing := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithMiddleware(signingMiddleware("key1")),
+	}
+	// The call middleware is passed before the nonce, but runs after it.
+	opts := append([]jsonapi.Opt{jsonapi.WithCallMiddleware(signingMiddleware("key2"))}, standing...)
+	opts = append(opts, jsonapi.WithRequestHeader("X-Nonce", "abc"))
+
+	if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if signature != "key2:abc" {
+		t.Errorf("expected the call middleware to sign the request last, got %q", signature)
+	}
+
+	if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", standing...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if signature != "key1:" {
+		t.Errorf("expected the standing middleware to sign the request, got %q", signature)
+	}
+}
@@ -0,0 +1,155 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DependencyStats are the calls made to a dependency while handling a request.
+type DependencyStats struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Errors is the number of calls that failed, or received a 5xx response.
+	Errors   int           `json:"errors"`
+	Duration time.Duration `json:"duration"`
+}
+
+// CallStats accumulates the calls made to dependencies, e.g. while handling a server request.
+type CallStats struct {
+	m    sync.Mutex
+	deps map[string]*DependencyStats
+}
+
+type callStatsContextKey struct{}
+
+// ContextWithCallStats returns a context that accumulates the stats of calls made with
+// WithCallStats, and the stats, which can be read once the calls are complete, e.g. for a
+// summary log line. See also CallStatsHandler.
+func ContextWithCallStats(ctx context.Context) (context.Context, *CallStats) {
+	s := &CallStats{deps: map[string]*DependencyStats{}}
+	return context.WithValue(ctx, callStatsContextKey{}, s), s
+}
+
+// CallStatsFromContext returns the stats added by ContextWithCallStats, or nil.
+func CallStatsFromContext(ctx context.Context) *CallStats {
+	s, _ := ctx.Value(callStatsContextKey{}).(*CallStats)
+	return s
+}
+
+// WithCallStats records calls in the CallStats of the request context, if present, under the
+// dependency name. If the name is empty, the host of the request URL is used. The duration of a
+// call includes retries.
+func WithCallStats(dependency string) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				s := CallStatsFromContext(req.Context())
+				if s == nil {
+					return next.Do(req)
+				}
+				name := dependency
+				if name == "" {
+					name = req.URL.Host
+				}
+				start := time.Now()
+				res, err := next.Do(req)
+				s.add(name, time.Since(start), err != nil || res.StatusCode >= 500)
+				return res, err
+			})
+		})
+		return nil
+	}
+}
+
+func (s *CallStats) add(name string, d time.Duration, failed bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	dep, ok := s.deps[name]
+	if !ok {
+		dep = &DependencyStats{Name: name}
+		s.deps[name] = dep
+	}
+	dep.Count++
+	dep.Duration += d
+	if failed {
+		dep.Errors++
+	}
+}
+
+// Dependencies returns the stats of each dependency, ordered by name.
+func (s *CallStats) Dependencies() []DependencyStats {
+	s.m.Lock()
+	defer s.m.Unlock()
+	deps := make([]DependencyStats, 0, len(s.deps))
+	for _, dep := range s.deps {
+		deps = append(deps, *dep)
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps
+}
+
+// ServerTiming returns the stats as a Server-Timing header value, with a metric for each
+// dependency, e.g. `orders;dur=12.5;desc="2 calls"`.
+func (s *CallStats) ServerTiming() string {
+	deps := s.Dependencies()
+	metrics := make([]string, len(deps))
+	for i, dep := range deps {
+		calls := "calls"
+		if dep.Count == 1 {
+			calls = "call"
+		}
+		metrics[i] = fmt.Sprintf(`%s;dur=%.1f;desc="%d %s"`, serverTimingName(dep.Name), float64(dep.Duration.Microseconds())/1000, dep.Count, calls)
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// serverTimingName replaces characters that aren't valid in a Server-Timing metric name.
+func serverTimingName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// CallStatsHandler adds CallStats to the context of each request, and adds a Server-Timing
+// header with the stats to the response when the handler writes the response headers.
+func CallStatsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, s := ContextWithCallStats(r.Context())
+		next.ServeHTTP(&serverTimingWriter{ResponseWriter: w, stats: s}, r.WithContext(ctx))
+	})
+}
+
+type serverTimingWriter struct {
+	http.ResponseWriter
+	stats       *CallStats
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if timing := w.stats.ServerTiming(); timing != "" {
+			w.Header().Add("Server-Timing", timing)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
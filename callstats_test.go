@@ -0,0 +1,51 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestCallStats(t *testing.T) {
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithCallStats("items"),
+	}
+
+	t.Run("calls are accumulated in the context", func(t *testing.T) {
+		ctx, stats := jsonapi.ContextWithCallStats(context.Background())
+		jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok", opts...)
+		jsonapi.Get[itemsGetResponse](ctx, "/items/get/500", opts...)
+		jsonapi.Get[itemsGetResponse](ctx, "http://other.example.com/items/get/ok", jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithCallStats(""))
+		deps := stats.Dependencies()
+		if len(deps) != 2 {
+			t.Fatalf("expected 2 dependencies, got %+v", deps)
+		}
+		if deps[0].Name != "items" || deps[0].Count != 2 || deps[0].Errors != 1 {
+			t.Errorf("unexpected items stats %+v", deps[0])
+		}
+		if deps[1].Name != "other.example.com" || deps[1].Count != 1 || deps[1].Errors != 0 {
+			t.Errorf("unexpected other stats %+v", deps[1])
+		}
+	})
+	t.Run("calls without stats in the context are not recorded", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+	t.Run("the handler adds a Server-Timing header", func(t *testing.T) {
+		h := jsonapi.CallStatsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			jsonapi.Get[itemsGetResponse](r.Context(), "/items/get/ok", opts...)
+			w.Write([]byte("ok"))
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if timing := w.Header().Get("Server-Timing"); !regexp.MustCompile(`^items;dur=\d+\.\d;desc="1 call"$`).MatchString(timing) {
+			t.Errorf("unexpected Server-Timing header %q", timing)
+		}
+	})
+}
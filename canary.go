@@ -0,0 +1,74 @@
+package jsonapi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+)
+
+// Canary routes a percentage of calls to an alternate base URL or API version, for gradual
+// upstream migrations.
+type Canary struct {
+	// Percent is the percentage of calls routed to the canary, between 0 and 100.
+	Percent float64
+	// BaseURL, if set, replaces the scheme and host of canary requests, and its path, if any,
+	// is prepended to the request path.
+	BaseURL string
+	// Header and Value, if set, are set on canary requests, e.g. to select an API version.
+	Header string
+	Value  string
+	// Key returns a key used to route requests consistently, e.g. a user or tenant ID from the
+	// request context, so that a caller always sees the same version. Requests with an empty
+	// key, or all requests if Key is nil, are routed randomly.
+	Key func(req *http.Request) string
+}
+
+// WithCanary routes the configured percentage of requests to the canary. Routing happens after
+// middleware has run, so the canary header replaces any header of the same name.
+func WithCanary(canary Canary) Opt {
+	return func(c *Config) error {
+		if canary.Percent < 0 || canary.Percent > 100 {
+			return fmt.Errorf("canary percentage must be between 0 and 100, got %v", canary.Percent)
+		}
+		var base *url.URL
+		if canary.BaseURL != "" {
+			var err error
+			if base, err = url.Parse(canary.BaseURL); err != nil {
+				return fmt.Errorf("failed to parse canary base URL: %w", err)
+			}
+		}
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if !canary.selects(req) {
+					return next.Do(req)
+				}
+				req = req.Clone(req.Context())
+				if base != nil {
+					req.URL = rebaseURL(base, req.URL)
+					req.Host = ""
+				}
+				if canary.Header != "" {
+					req.Header.Set(canary.Header, canary.Value)
+				}
+				return next.Do(req)
+			})
+		})
+		return nil
+	}
+}
+
+// selects returns true if the request should be routed to the canary.
+func (c Canary) selects(req *http.Request) bool {
+	var key string
+	if c.Key != nil {
+		key = c.Key(req)
+	}
+	if key == "" {
+		return rand.Float64()*100 < c.Percent
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return float64(h.Sum32()%10000) < c.Percent*100
+}
@@ -0,0 +1,65 @@
+package jsonapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+type userKey struct{}
+
+func TestWithCanary(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, map[string]string{"host": r.URL.Host, "path": r.URL.Path, "version": r.Header.Get("API-Version")}, http.StatusOK)
+	})
+	client := jsonapi.WithClient(testClient{Handler: h})
+	get := func(ctx context.Context, opts ...jsonapi.Opt) map[string]string {
+		t.Helper()
+		resp, _, err := jsonapi.Get[map[string]string](ctx, "http://api.example.com/orders", append([]jsonapi.Opt{client, jsonapi.WithRequestHeader("API-Version", "1")}, opts...)...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return resp
+	}
+
+	t.Run("all requests are routed to a 100% canary", func(t *testing.T) {
+		resp := get(context.Background(), jsonapi.WithCanary(jsonapi.Canary{Percent: 100, BaseURL: "http://canary.example.com/v2", Header: "API-Version", Value: "2"}))
+		if resp["host"] != "canary.example.com" || resp["path"] != "/v2/orders" || resp["version"] != "2" {
+			t.Errorf("expected the canary, got %v", resp)
+		}
+	})
+	t.Run("no requests are routed to a 0% canary", func(t *testing.T) {
+		resp := get(context.Background(), jsonapi.WithCanary(jsonapi.Canary{Percent: 0, Header: "API-Version", Value: "2"}))
+		if resp["version"] != "1" {
+			t.Errorf("expected the primary, got %v", resp)
+		}
+	})
+	t.Run("routing is sticky by key", func(t *testing.T) {
+		canary := jsonapi.WithCanary(jsonapi.Canary{
+			Percent: 50,
+			Header:  "API-Version",
+			Value:   "2",
+			Key:     func(req *http.Request) string { s, _ := req.Context().Value(userKey{}).(string); return s },
+		})
+		var canaryUsers int
+		for i := range 100 {
+			ctx := context.WithValue(context.Background(), userKey{}, fmt.Sprintf("user-%d", i))
+			first := get(ctx, canary)["version"]
+			for range 3 {
+				if version := get(ctx, canary)["version"]; version != first {
+					t.Fatalf("user-%d: expected version %s, got %s", i, first, version)
+				}
+			}
+			if first == "2" {
+				canaryUsers++
+			}
+		}
+		if canaryUsers < 25 || canaryUsers > 75 {
+			t.Errorf("expected about half of users to be routed to the canary, got %d", canaryUsers)
+		}
+	})
+}
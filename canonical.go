@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithCanonicalJSON encodes request bodies as canonical JSON, so that equal values always
+// produce identical bytes. This is required when request bodies are signed or hashed.
+//
+// Canonical JSON has no insignificant whitespace, object keys sorted by code point, strings
+// without HTML escaping, and numbers in plain decimal notation without exponents, trailing
+// fractional zeros or negative zero.
+func WithCanonicalJSON() Opt {
+	return func(c *Config) error {
+		c.CanonicalJSON = true
+		return nil
+	}
+}
+
+func canonicalJSON(data []byte) ([]byte, error) {
+	tree, err := decodeJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = writeCanonicalJSON(&buf, tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprint(buf, v)
+	case json.Number:
+		n, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(n)
+	case string:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1)
+	case []any:
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", v)
+	}
+	return nil
+}
+
+// canonicalNumber formats a JSON number exactly, in plain decimal notation.
+func canonicalNumber(n json.Number) (string, error) {
+	d, err := ParseDecimal(string(n))
+	if err != nil {
+		return "", err
+	}
+	r := d.Rat()
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	s := strings.TrimRight(r.FloatString(d.Scale()), "0")
+	return strings.TrimSuffix(s, "."), nil
+}
@@ -0,0 +1,26 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithCanonicalJSON(t *testing.T) {
+	req := map[string]any{
+		"z":      1,
+		"a":      []any{"<b>", 1.50, -0.0},
+		"m":      map[string]any{"y": true, "x": nil},
+		"amount": jsonapi.MustParseDecimal("1.2300e2"),
+	}
+	var received string
+	_, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "/items", req, jsonapi.WithClient(echoClient(&received)), jsonapi.WithCanonicalJSON())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := `{"a":["<b>",1.5,0],"amount":123,"m":{"x":null,"y":true},"z":1}`
+	if received != expected {
+		t.Errorf("expected %s, got %s", expected, received)
+	}
+}
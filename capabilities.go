@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EndpointCapabilities are the features of an endpoint advertised in response to an OPTIONS request.
+type EndpointCapabilities struct {
+	// Allow lists the supported methods, from the Allow header.
+	Allow []string `json:"allow"`
+	// AcceptPatch lists the media types accepted by PATCH, from the Accept-Patch header.
+	AcceptPatch []string `json:"acceptPatch"`
+	CORS        CORS     `json:"cors"`
+}
+
+// CORS is the cross-origin resource sharing policy of an endpoint. It's only populated if
+// the server returned Access-Control-* headers, which usually requires the request to include
+// Origin and Access-Control-Request-Method headers, e.g. using WithRequestHeader.
+type CORS struct {
+	AllowOrigin      string        `json:"allowOrigin"`
+	AllowMethods     []string      `json:"allowMethods"`
+	AllowHeaders     []string      `json:"allowHeaders"`
+	ExposeHeaders    []string      `json:"exposeHeaders"`
+	AllowCredentials bool          `json:"allowCredentials"`
+	MaxAge           time.Duration `json:"maxAge"`
+}
+
+// Allows returns true if the endpoint supports the method.
+func (c EndpointCapabilities) Allows(method string) bool {
+	return slices.ContainsFunc(c.Allow, func(m string) bool { return strings.EqualFold(m, method) })
+}
+
+// AcceptsPatch returns true if the endpoint accepts PATCH requests with the media type,
+// e.g. "application/merge-patch+json".
+func (c EndpointCapabilities) AcceptsPatch(mediaType string) bool {
+	for _, accepted := range c.AcceptPatch {
+		if t, _, err := mime.ParseMediaType(accepted); err == nil && strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities sends an OPTIONS request to the URL and returns the capabilities advertised
+// in the response headers, so that callers can detect features at runtime, e.g. whether
+// PATCH is supported. Returns an InvalidStatusError if the response is not successful.
+func Capabilities(ctx context.Context, url string, opts ...Opt) (c EndpointCapabilities, err error) {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return c, fmt.Errorf("failed to create config: %w", err)
+	}
+	return config.capabilities(ctx, url)
+}
+
+func (config *Config) capabilities(ctx context.Context, url string) (c EndpointCapabilities, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, url, nil)
+	if err != nil {
+		return c, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return c, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return c, config.newStatusError(res.StatusCode, res.Header, config.readErrorBody(res.Body))
+	}
+	io.Copy(io.Discard, res.Body)
+	return parseCapabilities(res.Header), nil
+}
+
+func parseCapabilities(h http.Header) (c EndpointCapabilities) {
+	c.Allow = headerList(h, "Allow")
+	c.AcceptPatch = headerList(h, "Accept-Patch")
+	c.CORS.AllowOrigin = h.Get("Access-Control-Allow-Origin")
+	c.CORS.AllowMethods = headerList(h, "Access-Control-Allow-Methods")
+	c.CORS.AllowHeaders = headerList(h, "Access-Control-Allow-Headers")
+	c.CORS.ExposeHeaders = headerList(h, "Access-Control-Expose-Headers")
+	c.CORS.AllowCredentials = strings.EqualFold(h.Get("Access-Control-Allow-Credentials"), "true")
+	if seconds, err := strconv.Atoi(h.Get("Access-Control-Max-Age")); err == nil {
+		c.CORS.MaxAge = time.Duration(seconds) * time.Second
+	}
+	return c
+}
+
+// headerList returns the comma separated values of all of the named headers.
+func headerList(h http.Header, name string) (values []string) {
+	for _, v := range h.Values(name) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
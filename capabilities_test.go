@@ -0,0 +1,64 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCapabilities(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("OPTIONS /items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET, POST")
+		w.Header().Add("Allow", "PATCH")
+		w.Header().Set("Accept-Patch", "application/merge-patch+json, application/json-patch+json")
+		if r.Header.Get("Origin") != "" {
+			w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+			w.Header().Set("Access-Control-Allow-Methods", "GET, PATCH")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Set("Access-Control-Max-Age", "600")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := jsonapi.WithClient(testClient{Handler: mux})
+
+	t.Run("Allow, Accept-Patch and CORS headers are parsed", func(t *testing.T) {
+		c, err := jsonapi.Capabilities(context.Background(), "/items", client, jsonapi.WithRequestHeader("Origin", "https://example.com"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		expected := jsonapi.EndpointCapabilities{
+			Allow:       []string{"GET", "POST", "PATCH"},
+			AcceptPatch: []string{"application/merge-patch+json", "application/json-patch+json"},
+			CORS: jsonapi.CORS{
+				AllowOrigin:      "https://example.com",
+				AllowMethods:     []string{"GET", "PATCH"},
+				AllowHeaders:     []string{"Authorization", "Content-Type"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			},
+		}
+		if diff := cmp.Diff(expected, c); diff != "" {
+			t.Error(diff)
+		}
+		if !c.Allows("patch") || c.Allows(http.MethodDelete) {
+			t.Errorf("unexpected Allows result for %v", c.Allow)
+		}
+		if !c.AcceptsPatch("application/merge-patch+json") || c.AcceptsPatch("application/json") {
+			t.Errorf("unexpected AcceptsPatch result for %v", c.AcceptPatch)
+		}
+	})
+	t.Run("unsupported OPTIONS requests return an InvalidStatusError", func(t *testing.T) {
+		_, err := jsonapi.Capabilities(context.Background(), "/other", client)
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusNotFound {
+			t.Errorf("expected a 404 InvalidStatusError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Result is a decoded item, or the error that stopped decoding.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// GetChan gets a JSON array or NDJSON response from the given URL, and sends each item to the
+// returned channel as it is decoded, so that items can be processed while the body is downloading.
+// An error that occurs before the response body is read, e.g. a non-success status, is returned
+// by GetChan. Later errors are sent as the final Result. The channel is closed when the body has
+// been read. To stop early, cancel the context. The WithUnwrap options are not supported.
+func GetChan[T any](ctx context.Context, url string, buffer int, opts ...Opt) (<-chan Result[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/x-ndjson, application/json")
+	config, err := newConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return nil, err
+	}
+	contentType := res.Header.Get("Content-Type")
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		body := config.readErrorBody(res.Body)
+		return nil, config.newStatusError(res.StatusCode, res.Header, body)
+	}
+	if config.StrictContentType && !isJSONContentType(contentType) && !isNDJSONContentType(contentType) {
+		defer res.Body.Close()
+		body := config.readErrorBody(res.Body)
+		return nil, InvalidContentTypeError{
+			Status:      res.StatusCode,
+			ContentType: contentType,
+			Body:        body,
+		}
+	}
+	results := make(chan Result[T], buffer)
+	go func() {
+		defer close(results)
+		defer res.Body.Close()
+		send := func(r Result[T]) error {
+			select {
+			case results <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		err := decodeItems(res.Body, contentType, func(data json.RawMessage) error {
+			var v T
+			if err := config.unmarshalJSON(data, &v); err != nil {
+				return err
+			}
+			return send(Result[T]{Value: v})
+		})
+		if err != nil && ctx.Err() == nil {
+			send(Result[T]{Err: fmt.Errorf("failed to decode item: %w", err)})
+		}
+	}()
+	return results, nil
+}
+
+// decodeItems reads a JSON array, or NDJSON, from r and calls f with each item as it is read.
+func decodeItems(r io.Reader, contentType string, f func(data json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	if isNDJSONContentType(contentType) {
+		for {
+			var item json.RawMessage
+			err := dec.Decode(&item)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err = f(item); err != nil {
+				return err
+			}
+		}
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected a JSON array, got %v", tok)
+	}
+	for dec.More() {
+		var item json.RawMessage
+		if err = dec.Decode(&item); err != nil {
+			return err
+		}
+		if err = f(item); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
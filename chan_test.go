@@ -0,0 +1,81 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type chanItem struct {
+	ID int `json:"id"`
+}
+
+func TestGetChan(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "JSON array",
+			contentType: "application/json",
+			body:        `[{"id":1},{"id":2},{"id":3}]`,
+		},
+		{
+			name:        "NDJSON",
+			contentType: "application/x-ndjson",
+			body:        "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			})
+			results, err := jsonapi.GetChan[chanItem](context.Background(), "/items", 1, jsonapi.WithClient(testClient{Handler: h}))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			var actual []chanItem
+			for r := range results {
+				if r.Err != nil {
+					t.Fatalf("expected no error, got %v", r.Err)
+				}
+				actual = append(actual, r.Value)
+			}
+			if diff := cmp.Diff([]chanItem{{ID: 1}, {ID: 2}, {ID: 3}}, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("decode errors are sent as the final result", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"id":1},{"id":"a"}]`))
+		})
+		results, err := jsonapi.GetChan[chanItem](context.Background(), "/items", 0, jsonapi.WithClient(testClient{Handler: h}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var values, errs int
+		for r := range results {
+			if r.Err != nil {
+				errs++
+				continue
+			}
+			values++
+		}
+		if values != 1 || errs != 1 {
+			t.Errorf("expected 1 value and 1 error, got %d and %d", values, errs)
+		}
+	})
+	t.Run("status errors are returned", func(t *testing.T) {
+		_, err := jsonapi.GetChan[chanItem](context.Background(), "/items/get/500", 0, jsonapi.WithClient(testClient{Handler: createTestRoutes()}))
+		if _, ok := err.(jsonapi.InvalidStatusError); !ok {
+			t.Errorf("expected InvalidStatusError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrorClass is a broad classification of an error returned by the client, so that retry
+// policies and alerting can be driven by the class of failure rather than string matching.
+type ErrorClass int
+
+const (
+	ErrorClassUnknown ErrorClass = iota
+	// ErrorClassNetwork is a failure to connect, or a connection failure during the request.
+	ErrorClassNetwork
+	// ErrorClassTimeout is a request that exceeded its deadline, or received a 408 Request Timeout
+	// or 504 Gateway Timeout.
+	ErrorClassTimeout
+	// ErrorClassCanceled is a request whose context was canceled.
+	ErrorClassCanceled
+	// ErrorClassClientStatus is a 4xx response, other than 408 Request Timeout and 429 Too Many Requests.
+	ErrorClassClientStatus
+	// ErrorClassServerStatus is a 5xx response, other than 502 Bad Gateway, 503 Service Unavailable
+	// and 504 Gateway Timeout, e.g. 500 Internal Server Error or 501 Not Implemented.
+	ErrorClassServerStatus
+	// ErrorClassDecode is a 2xx response that could not be decoded, or had an unexpected Content-Type.
+	ErrorClassDecode
+	// ErrorClassRateLimited is a 429 Too Many Requests response.
+	ErrorClassRateLimited
+	// ErrorClassRedirectStatus is a 3xx response that wasn't followed, e.g. 304 Not Modified.
+	ErrorClassRedirectStatus
+	// ErrorClassUnavailable is a 502 Bad Gateway or 503 Service Unavailable response.
+	ErrorClassUnavailable
+	// ErrorClassPolicy is a request rejected by the client, e.g. by WithAllowedHosts, see
+	// TransportErrorPolicy.
+	ErrorClassPolicy
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassNetwork:
+		return "network"
+	case ErrorClassTimeout:
+		return "timeout"
+	case ErrorClassCanceled:
+		return "canceled"
+	case ErrorClassClientStatus:
+		return "client_status"
+	case ErrorClassServerStatus:
+		return "server_status"
+	case ErrorClassDecode:
+		return "decode"
+	case ErrorClassRateLimited:
+		return "rate_limited"
+	case ErrorClassRedirectStatus:
+		return "redirect_status"
+	case ErrorClassUnavailable:
+		return "unavailable"
+	case ErrorClassPolicy:
+		return "policy"
+	}
+	return "unknown"
+}
+
+// Temporary returns true if errors of this class may succeed if the request is repeated. The
+// temporary statuses, 408, 429, 502, 503 and 504, are those retried by WithRetry.
+func (c ErrorClass) Temporary() bool {
+	return c == ErrorClassNetwork || c == ErrorClassTimeout || c == ErrorClassUnavailable || c == ErrorClassRateLimited
+}
+
+// Timeout returns true if errors of this class are caused by a deadline being exceeded.
+func (c ErrorClass) Timeout() bool {
+	return c == ErrorClassTimeout
+}
+
+// Classify returns the class of an error returned by the client.
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassUnknown
+	}
+	var ise InvalidStatusError
+	if errors.As(err, &ise) {
+		switch {
+		case ise.Status == http.StatusRequestTimeout, ise.Status == http.StatusGatewayTimeout:
+			return ErrorClassTimeout
+		case ise.Status == http.StatusBadGateway, ise.Status == http.StatusServiceUnavailable:
+			return ErrorClassUnavailable
+		case ise.Status >= 500:
+			return ErrorClassServerStatus
+		case ise.Status == http.StatusTooManyRequests:
+			return ErrorClassRateLimited
+		case ise.Status >= 400:
+			return ErrorClassClientStatus
+		case ise.Status >= 300:
+			return ErrorClassRedirectStatus
+		}
+		return ErrorClassUnknown
+	}
+	var ije InvalidJSONError
+	if errors.As(err, &ije) {
+		return ErrorClassDecode
+	}
+	var icte InvalidContentTypeError
+	if errors.As(err, &icte) {
+		return ErrorClassDecode
+	}
+	if isPolicyError(err) {
+		return ErrorClassPolicy
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+	return ErrorClassUnknown
+}
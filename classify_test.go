@@ -0,0 +1,129 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected jsonapi.ErrorClass
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: jsonapi.ErrorClassUnknown,
+		},
+		{
+			name:     "network",
+			err:      fmt.Errorf("failed to perform HTTP request: %w", &url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}),
+			expected: jsonapi.ErrorClassNetwork,
+		},
+		{
+			name:     "timeout",
+			err:      &url.Error{Op: "Get", URL: "/", Err: timeoutError{}},
+			expected: jsonapi.ErrorClassTimeout,
+		},
+		{
+			name:     "deadline exceeded",
+			err:      fmt.Errorf("failed: %w", context.DeadlineExceeded),
+			expected: jsonapi.ErrorClassTimeout,
+		},
+		{
+			name:     "canceled",
+			err:      &url.Error{Op: "Get", URL: "/", Err: context.Canceled},
+			expected: jsonapi.ErrorClassCanceled,
+		},
+		{
+			name:     "client status",
+			err:      jsonapi.InvalidStatusError{Status: 404},
+			expected: jsonapi.ErrorClassClientStatus,
+		},
+		{
+			name:     "request timeout status",
+			err:      jsonapi.InvalidStatusError{Status: 408},
+			expected: jsonapi.ErrorClassTimeout,
+		},
+		{
+			name:     "rate limited",
+			err:      jsonapi.TooManyRequestsError{InvalidStatusError: jsonapi.InvalidStatusError{Status: 429}},
+			expected: jsonapi.ErrorClassRateLimited,
+		},
+		{
+			name:     "redirect status",
+			err:      jsonapi.NotModifiedError{InvalidStatusError: jsonapi.InvalidStatusError{Status: 304}},
+			expected: jsonapi.ErrorClassRedirectStatus,
+		},
+		{
+			name:     "server status",
+			err:      jsonapi.InvalidStatusError{Status: 500},
+			expected: jsonapi.ErrorClassServerStatus,
+		},
+		{
+			name:     "unavailable status",
+			err:      jsonapi.InvalidStatusError{Status: 503},
+			expected: jsonapi.ErrorClassUnavailable,
+		},
+		{
+			name:     "gateway timeout status",
+			err:      jsonapi.InvalidStatusError{Status: 504},
+			expected: jsonapi.ErrorClassTimeout,
+		},
+		{
+			name:     "policy",
+			err:      &url.Error{Op: "Get", URL: "/", Err: jsonapi.ForbiddenHostError{Host: "localhost"}},
+			expected: jsonapi.ErrorClassPolicy,
+		},
+		{
+			name:     "decode",
+			err:      jsonapi.InvalidJSONError{Status: 200},
+			expected: jsonapi.ErrorClassDecode,
+		},
+		{
+			name:     "unknown",
+			err:      errors.New("unknown"),
+			expected: jsonapi.ErrorClassUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := jsonapi.Classify(tt.err); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestErrorClassTemporary(t *testing.T) {
+	for _, status := range []int{408, 429, 502, 503, 504} {
+		if class := jsonapi.Classify(jsonapi.InvalidStatusError{Status: status}); !class.Temporary() {
+			t.Errorf("expected %d (%v) to be temporary", status, class)
+		}
+	}
+	for _, status := range []int{304, 400, 404, 500, 501} {
+		if class := jsonapi.Classify(jsonapi.InvalidStatusError{Status: status}); class.Temporary() {
+			t.Errorf("expected %d (%v) not to be temporary", status, class)
+		}
+	}
+}
+
+func TestStatusErrorsAreNotNetErrors(t *testing.T) {
+	var ne net.Error
+	if errors.As(jsonapi.InvalidStatusError{Status: 503}, &ne) {
+		t.Error("expected InvalidStatusError not to be a net.Error")
+	}
+}
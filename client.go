@@ -1,12 +1,16 @@
 package jsonapi
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"time"
 )
 
@@ -14,30 +18,129 @@ type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// DoerFunc is an adapter to allow the use of ordinary functions as a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 type Config struct {
 	Client     Doer
 	Middleware []Middleware
+	// CallMiddleware runs after Middleware, see WithCallMiddleware.
+	CallMiddleware []Middleware
+	Interceptors   []Interceptor
+	Retry          RetryPolicy
+	// StrictContentType rejects 2xx responses that don't have a JSON Content-Type.
+	StrictContentType bool
+	// Unwrap selects the value within a response envelope to decode, see WithUnwrap.
+	Unwrap string
+	// UnwrapMeta decodes other values within a response envelope, see WithUnwrapMeta.
+	UnwrapMeta map[string]any
+	// TypeHooks rewrite the JSON values of specific Go types during encoding and decoding.
+	TypeHooks []TypeHook
+	// FieldNaming converts the names of struct fields without json tags to JSON object keys.
+	FieldNaming FieldNaming
+	// ZeroValues controls how zero value struct fields are encoded in request bodies.
+	ZeroValues ZeroValues
+	// CanonicalJSON encodes request bodies as canonical JSON, see WithCanonicalJSON.
+	CanonicalJSON bool
+	// Redactor redacts secrets from errors, see WithRedactor.
+	Redactor *Redactor
+	// MaxErrorBodyBytes limits the size of response bodies included in errors, see WithMaxErrorBodyBytes.
+	MaxErrorBodyBytes int
+	// StreamingDecode decodes JSON array and NDJSON responses item by item, see WithStreamingDecode.
+	StreamingDecode bool
+	// OperationName is the logical name of the call, see WithOperationName.
+	OperationName string
+	// Route is the route template of the call, see WithRoute.
+	Route string
+	// Clock is used for retry backoff, injected latency and token expiry, see WithClock.
+	Clock Clock
+	// MirrorComparison compares primary and mirrored responses, see WithMirrorComparison.
+	MirrorComparison *MirrorComparison
+	// CallAuthorization overrides the Authorization header and auth middleware, see WithCallAuthorization.
+	CallAuthorization string
+	// BaseURL is used to resolve relative request URLs, see WithBaseURL.
+	BaseURL *url.URL
+	// StatusErrorDecoder replaces the errors returned for non-success statuses, see WithStatusErrorDecoder.
+	StatusErrorDecoder func(err error) error
+	// Cache stores GET responses, see WithCache.
+	Cache *Cache
+	// transport is the transport configured by options such as WithProxy, see configureTransport.
+	transport http.RoundTripper
+	// guardedTransport is the *http.Transport that transportGuards were installed on.
+	guardedTransport *http.Transport
+	// transportGuards are security options that require transport to be used, see checkTransport.
+	transportGuards []transportGuard
+	// onItem is called with each item of a streamed response, see WithEachItem.
+	onItem func(config *Config, data json.RawMessage) error
 }
 
+// Interceptor wraps the Doer used to send a request, after request middleware has run.
+// Unlike Middleware, an Interceptor can delay, repeat or short-circuit the HTTP request.
+// Interceptors are applied in order, so the first Interceptor is the outermost.
+type Interceptor func(next Doer) Doer
+
 type Middleware interface {
 	Request(req *http.Request) error
 	Response(res *http.Response) error
 }
 
+// defaultClient is used when no Doer is provided. It is owned by this package, so that
+// options and other libraries modifying http.DefaultClient don't affect each other.
+var defaultClient = newDefaultClient()
+
+// newDefaultClient creates a client with a pooled transport, proxy settings from the
+// environment, and timeouts for connecting and the TLS handshake. There's no overall timeout,
+// since that would break long-running streams, and no response header timeout, since that
+// would break slow endpoints. Use WithTimeout, WithResponseHeaderTimeout or a context deadline.
+func newDefaultClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
 // WithTimeout attempts to set the timeout on the HTTP client.
 // It is a no-op if the underlying Doer is not an *http.Client.
+//
+// The *http.Client is copied before the timeout is set, so the client passed to WithClient
+// is not modified.
 func WithTimeout(timeout time.Duration) Opt {
 	return func(c *Config) error {
 		if c.Client == nil {
-			c.Client = http.DefaultClient
+			c.Client = defaultClient
 		}
 		if httpc, ok := c.Client.(*http.Client); ok {
-			httpc.Timeout = timeout
+			clone := *httpc
+			clone.Timeout = timeout
+			c.Client = &clone
 		}
 		return nil
 	}
 }
 
+// WithResponseHeaderTimeout limits the time to wait for the response headers after the
+// request is sent. Unlike WithTimeout, reading the response body isn't limited, so that
+// streams aren't cut off.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithResponseHeaderTimeout(timeout time.Duration) Opt {
+	return withTransport(func(t *http.Transport) error {
+		t.ResponseHeaderTimeout = timeout
+		return nil
+	})
+}
+
 // WithClient uses a custom Doer for the HTTP requests.
 // Typically, this is a *http.Client.
 func WithClient(client Doer) Opt {
@@ -56,17 +159,39 @@ func WithMiddleware(middleware ...Middleware) Opt {
 	}
 }
 
+// WithCallMiddleware adds middleware that runs after the middleware added by WithMiddleware
+// and other options, regardless of the order of the options. Use it to add middleware to a
+// single call on top of a shared set of options, e.g. to sign a request with a different key.
+func WithCallMiddleware(middleware ...Middleware) Opt {
+	return func(c *Config) error {
+		c.CallMiddleware = append(c.CallMiddleware, middleware...)
+		return nil
+	}
+}
+
+// WithInterceptor adds interceptors that wrap the HTTP request.
+func WithInterceptor(interceptors ...Interceptor) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+		return nil
+	}
+}
+
 // Opt is an option for the JSON API client.
 // See WithTimeout, WithClient, and WithMiddleware.
 type Opt func(*Config) (err error)
 
 func newConfig(opts ...Opt) (*Config, error) {
-	c := &Config{
-		Client: http.DefaultClient,
+	return configure(&Config{
+		Client: defaultClient,
 		Middleware: []Middleware{
 			&requestHeaderMiddleware{"Content-Type", "application/json"},
 		},
-	}
+	}, opts...)
+}
+
+// configure applies the options to c.
+func configure(c *Config, opts ...Opt) (*Config, error) {
 	for _, o := range opts {
 		if err := o(c); err != nil {
 			return nil, fmt.Errorf("failed to apply option: %w", err)
@@ -85,30 +210,149 @@ func Post[TReq, TResp any](ctx context.Context, url string, request TReq, opts .
 	return doRequestResponse[TReq, TResp](ctx, http.MethodPost, url, request, opts...)
 }
 
+// Patch a HTTP request to the given URL with the given request body.
+func Patch[TReq, TResp any](ctx context.Context, url string, request TReq, opts ...Opt) (response TResp, err error) {
+	return doRequestResponse[TReq, TResp](ctx, http.MethodPatch, url, request, opts...)
+}
+
+// Delete the resource at the given URL.
+// If the response is a 204, or has an empty body, the zero value of TResp is returned.
+func Delete[TResp any](ctx context.Context, url string, opts ...Opt) (response TResp, err error) {
+	return Do[struct{}, TResp](ctx, http.MethodDelete, url, nil, opts...)
+}
+
+// Do sends a HTTP request with the given method, e.g. for APIs that use methods such as
+// REPORT or PROPFIND. If request is nil, the request has no body.
+// If the response is a 204, or has an empty body, the zero value of TResp is returned.
+func Do[TReq, TResp any](ctx context.Context, method, url string, request *TReq, opts ...Opt) (response TResp, err error) {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to create config: %w", err)
+	}
+	var body io.Reader
+	if request != nil {
+		buf, err := config.marshal(*request)
+		if err != nil {
+			return response, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return response, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return response, err
+	}
+	err = decodeOptionalResponseInto(config, res, &response)
+	return response, err
+}
+
+// decodeOptionalResponseInto decodes the response body into v, unless the response is a 204,
+// or has an empty body, in which case v is left unchanged.
+func decodeOptionalResponseInto(config *Config, res *http.Response, v any) error {
+	if res.StatusCode >= 200 && res.StatusCode <= 299 && isEmptyBody(res) {
+		v = nil
+	}
+	return decodeResponseInto(config, res, v)
+}
+
+// isEmptyBody returns true if the response is a 204, or has an empty body. If the length of
+// the body is unknown, e.g. because it's chunked, the body is read ahead to find out.
+func isEmptyBody(res *http.Response) bool {
+	if res.StatusCode == http.StatusNoContent || res.ContentLength == 0 {
+		return true
+	}
+	if res.ContentLength > 0 {
+		return false
+	}
+	br := bufio.NewReader(res.Body)
+	if _, err := br.Peek(1); err == io.EOF {
+		return true
+	}
+	res.Body = teeReadCloser{Reader: br, Closer: res.Body}
+	return false
+}
+
 func Raw(req *http.Request, opts ...Opt) (res *http.Response, err error) {
 	config, err := newConfig(opts...)
 	if err != nil {
 		return res, fmt.Errorf("failed to create config: %w", err)
 	}
-	for _, m := range config.Middleware {
-		if err := m.Request(req); err != nil {
+	return config.raw(req)
+}
+
+func (config *Config) raw(req *http.Request) (res *http.Response, err error) {
+	if config.BaseURL != nil && !req.URL.IsAbs() {
+		resolved, err := resolveURL(config.BaseURL, req.URL.String())
+		if err != nil {
+			return res, fmt.Errorf("failed to resolve URL: %w", err)
+		}
+		if req.URL, err = url.Parse(resolved); err != nil {
+			return res, fmt.Errorf("failed to resolve URL: %w", err)
+		}
+		req.Host = req.URL.Host
+	}
+	if err := config.checkTransport(req); err != nil {
+		return res, err
+	}
+	if m := config.callMetadata(req.Method); m != nil {
+		req = req.WithContext(WithCallMetadata(req.Context(), m))
+	}
+	for _, m := range config.middleware() {
+		if err := safely(func() error { return m.Request(req) }); err != nil {
 			return res, fmt.Errorf("middleware failed to modify request: %w", err)
 		}
 	}
-	res, err = config.Client.Do(req)
+	res, err = config.doer().Do(req)
 	if err != nil {
 		return res, fmt.Errorf("failed to perform HTTP request: %w", err)
 	}
-	for _, m := range config.Middleware {
-		if err := m.Response(res); err != nil {
-			return res, fmt.Errorf("middleware failed to modify response: %w", err)
+	for _, m := range config.middleware() {
+		if err := safely(func() error { return m.Response(res) }); err != nil {
+			// The response isn't returned, so close it to release its connection, and any
+			// in-flight slot or drainer count held until the body is closed.
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+			return nil, fmt.Errorf("middleware failed to modify response: %w", err)
 		}
 	}
 	return res, nil
 }
 
+func (config *Config) middleware() []Middleware {
+	mw := config.Middleware
+	if config.CallAuthorization != "" {
+		mw = slices.DeleteFunc(slices.Clone(mw), func(m Middleware) bool {
+			_, isAuth := m.(*AuthMiddleware)
+			return isAuth
+		})
+		mw = append(mw, &requestHeaderMiddleware{key: "Authorization", value: config.CallAuthorization})
+	}
+	if len(config.CallMiddleware) == 0 {
+		return mw
+	}
+	return append(mw[:len(mw):len(mw)], config.CallMiddleware...)
+}
+
+func (config *Config) doer() (d Doer) {
+	d = config.Client
+	if config.Retry.Attempts > 1 {
+		d = config.retry(d)
+	}
+	for i := len(config.Interceptors) - 1; i >= 0; i-- {
+		d = config.Interceptors[i](d)
+	}
+	return d
+}
+
 func doRequestResponse[TReq, TResp any](ctx context.Context, method, url string, request TReq, opts ...Opt) (response TResp, err error) {
-	buf, err := json.Marshal(request)
+	config, err := newConfig(opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to create config: %w", err)
+	}
+	buf, err := config.marshal(request)
 	if err != nil {
 		return response, fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -116,11 +360,11 @@ func doRequestResponse[TReq, TResp any](ctx context.Context, method, url string,
 	if err != nil {
 		return response, fmt.Errorf("failed to create request: %w", err)
 	}
-	resp, err := Raw(req, opts...)
+	resp, err := config.raw(req)
 	if err != nil {
 		return response, err
 	}
-	return decodeResponse[TResp](resp)
+	return decodeResponse[TResp](config, resp)
 }
 
 // Get a HTTP response from the given URL.
@@ -130,41 +374,135 @@ func Get[TResp any](ctx context.Context, url string, opts ...Opt) (response TRes
 	if err != nil {
 		return response, false, fmt.Errorf("failed to create request: %w", err)
 	}
-	res, err := Raw(req, opts...)
+	config, err := newConfig(opts...)
+	if err != nil {
+		return response, false, fmt.Errorf("failed to create config: %w", err)
+	}
+	res, err := config.raw(req)
 	if err != nil {
 		return response, false, err
 	}
 	if res.StatusCode == http.StatusNotFound {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
 		return response, false, nil
 	}
-	response, err = decodeResponse[TResp](res)
+	response, err = decodeResponse[TResp](config, res)
 	if err != nil {
 		return response, false, err
 	}
 	return response, true, err
 }
 
-func decodeResponse[TResp any](res *http.Response) (response TResp, err error) {
+// GetWithBody sends a GET request with a JSON body, e.g. for search APIs that accept a query
+// in the body of a GET request.
+// Returns ok=false if the response was a 404.
+func GetWithBody[TReq, TResp any](ctx context.Context, url string, request TReq, opts ...Opt) (response TResp, ok bool, err error) {
+	response, err = Do[TReq, TResp](ctx, http.MethodGet, url, &request, opts...)
+	ok, err = found(err)
+	return response, ok, err
+}
+
+// found returns ok=false and no error if err is a 404 status error, for Get methods that
+// report a missing resource with ok.
+func found(err error) (ok bool, _ error) {
+	var ise InvalidStatusError
+	if errors.As(err, &ise) && ise.Status == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// call sends a request with the method to the URL, and decodes the response into response,
+// for the untyped Caller implementations. If request is nil, the request has no body.
+func (config *Config) call(ctx context.Context, method, url string, request, response any) error {
+	var body io.Reader
+	if request != nil {
+		buf, err := config.marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return err
+	}
+	return decodeOptionalResponseInto(config, res, response)
+}
+
+func decodeResponse[TResp any](config *Config, res *http.Response) (response TResp, err error) {
+	err = decodeResponseInto(config, res, &response)
+	return response, err
+}
+
+// decodeResponseInto decodes the response body into v. If v is nil, the body is discarded.
+func decodeResponseInto(config *Config, res *http.Response, v any) (err error) {
 	defer res.Body.Close()
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		body, _ := io.ReadAll(res.Body)
-		return response, InvalidStatusError{
-			Status: res.StatusCode,
-			Body:   string(body),
+		body := config.readErrorBody(res.Body)
+		if err := config.trailer(res); err != nil {
+			return err
 		}
+		return config.newStatusError(res.StatusCode, res.Header, body)
+	}
+	contentType := res.Header.Get("Content-Type")
+	if config.isStreamingDecode(v) && (isJSONContentType(contentType) || isNDJSONContentType(contentType) || !config.StrictContentType) {
+		return config.decodeStream(res, contentType, v)
 	}
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return response, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := config.trailer(res); err != nil {
+		return err
 	}
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		return response, InvalidJSONError{
+	// If there's nothing to decode, the content type doesn't matter.
+	if config.StrictContentType && v != nil && len(bodyBytes) > 0 && !isJSONContentType(contentType) && !isNDJSONContentType(contentType) {
+		return InvalidContentTypeError{
+			Status:      res.StatusCode,
+			ContentType: contentType,
+			Body:        config.errorBody(bodyBytes),
+		}
+	}
+	if v == nil {
+		return nil
+	}
+	if err := config.unmarshal(contentType, bodyBytes, v); err != nil {
+		return InvalidJSONError{
 			Status: res.StatusCode,
-			Body:   string(bodyBytes),
+			Body:   config.errorBody(bodyBytes),
 			Err:    err,
 		}
 	}
-	return response, nil
+	return nil
+}
+
+func (config *Config) unmarshal(contentType string, body []byte, v any) error {
+	if isNDJSONContentType(contentType) && isSlicePointer(v) {
+		return unmarshalNDJSON(body, v, config.unmarshalJSON)
+	}
+	for selector, meta := range config.UnwrapMeta {
+		value, err := selectJSON(body, selector)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(value, meta); err != nil {
+			return err
+		}
+	}
+	if config.Unwrap != "" {
+		value, err := selectJSON(body, config.Unwrap)
+		if err != nil {
+			return err
+		}
+		body = value
+	}
+	return config.unmarshalJSON(body, v)
 }
 
 type InvalidStatusError struct {
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/a-h/jsonapi"
 	"github.com/a-h/respond"
@@ -64,6 +66,61 @@ func createTestRoutes() *http.ServeMux {
 		}
 		respond.WithJSON(w, m, http.StatusCreated)
 	})
+	routes.HandleFunc("/items/patch/ok", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			respond.WithError(w, "Expected PATCH method", http.StatusBadRequest)
+			return
+		}
+		var m map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			respond.WithError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		respond.WithJSON(w, m, http.StatusOK)
+	})
+	routes.HandleFunc("/items/delete/ok", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			respond.WithError(w, "Expected DELETE method", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	routes.HandleFunc("/items/delete/chunked", func(w http.ResponseWriter, r *http.Request) {
+		// No Content-Length is set, so the length of the body is unknown.
+		w.WriteHeader(http.StatusOK)
+	})
+	routes.HandleFunc("/items/delete/body", func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, map[string]any{"deleted": true}, http.StatusOK)
+	})
+	routes.HandleFunc("/items/delete/404", func(w http.ResponseWriter, r *http.Request) {
+		respond.WithError(w, "Not found", http.StatusNotFound)
+	})
+	routes.HandleFunc("/items/report", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "REPORT" {
+			respond.WithError(w, "Expected REPORT method", http.StatusBadRequest)
+			return
+		}
+		m := map[string]any{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+				respond.WithError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		respond.WithJSON(w, m, http.StatusMultiStatus)
+	})
+	routes.HandleFunc("/items/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			respond.WithError(w, "Expected GET method", http.StatusBadRequest)
+			return
+		}
+		var m map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			respond.WithError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		respond.WithJSON(w, m, http.StatusOK)
+	})
 	routes.HandleFunc("/items/post/404", func(w http.ResponseWriter, r *http.Request) {
 		respond.WithError(w, "Not found", http.StatusNotFound)
 	})
@@ -161,6 +218,91 @@ func TestClient(t *testing.T) {
 			t.Error(diff)
 		}
 	})
+	t.Run("/items/patch/ok", func(t *testing.T) {
+		m := map[string]any{"key": "value"}
+		resp, err := jsonapi.Patch[map[string]any, map[string]any](ctx, "/items/patch/ok", m, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if diff := cmp.Diff(m, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("/items/delete/ok", func(t *testing.T) {
+		resp, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/ok", opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if resp != nil {
+			t.Errorf("expected a nil response, got %v", resp)
+		}
+	})
+	t.Run("/items/delete/chunked", func(t *testing.T) {
+		resp, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/chunked", opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if resp != nil {
+			t.Errorf("expected a nil response, got %v", resp)
+		}
+	})
+	t.Run("/items/delete/body", func(t *testing.T) {
+		resp, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/body", opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if diff := cmp.Diff(map[string]any{"deleted": true}, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("/items/delete/404", func(t *testing.T) {
+		_, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/404", opts...)
+		ise, isISE := err.(jsonapi.InvalidStatusError)
+		if !isISE || ise.Status != http.StatusNotFound {
+			t.Errorf("expected a 404 InvalidStatusError, got %v", err)
+		}
+	})
+	t.Run("/items/report", func(t *testing.T) {
+		m := map[string]any{"key": "value"}
+		resp, err := jsonapi.Do[map[string]any, map[string]any](ctx, "REPORT", "/items/report", &m, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if diff := cmp.Diff(m, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("/items/report without a body", func(t *testing.T) {
+		resp, err := jsonapi.Do[struct{}, map[string]any](ctx, "REPORT", "/items/report", nil, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if diff := cmp.Diff(map[string]any{}, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("/items/search", func(t *testing.T) {
+		m := map[string]any{"query": map[string]any{"match": "item"}}
+		resp, ok, err := jsonapi.GetWithBody[map[string]any, map[string]any](ctx, "/items/search", m, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if !ok {
+			t.Error("expected ok")
+		}
+		if diff := cmp.Diff(m, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("/items/search/404", func(t *testing.T) {
+		_, ok, err := jsonapi.GetWithBody[map[string]any, map[string]any](ctx, "/items/get/404", map[string]any{}, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		if ok {
+			t.Error("expected not ok")
+		}
+	})
 	t.Run("/items/post/ok", func(t *testing.T) {
 		m := map[string]any{"key": "value"}
 		resp, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/items/post/ok", m, opts...)
@@ -251,3 +393,40 @@ func TestClient(t *testing.T) {
 		}
 	})
 }
+
+func TestWithTimeout(t *testing.T) {
+	s := httptest.NewServer(createTestRoutes())
+	defer s.Close()
+
+	client := &http.Client{}
+	_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL+"/items/get/ok", jsonapi.WithClient(client), jsonapi.WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.Timeout != 0 {
+		t.Errorf("expected the client not to be modified, got timeout %v", client.Timeout)
+	}
+	_, _, err = jsonapi.Get[itemsGetResponse](context.Background(), s.URL+"/items/get/ok", jsonapi.WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if http.DefaultClient.Timeout != 0 {
+		t.Errorf("expected http.DefaultClient not to be modified, got timeout %v", http.DefaultClient.Timeout)
+	}
+}
+
+func TestWithResponseHeaderTimeout(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+
+	if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL); err != nil {
+		t.Errorf("expected slow responses to succeed by default, got %v", err)
+	}
+	_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithResponseHeaderTimeout(10*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+		t.Errorf("expected a response header timeout, got %v", err)
+	}
+}
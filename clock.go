@@ -0,0 +1,42 @@
+package jsonapi
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time and waits, so that tests of retries, backoff and token
+// expiry can control time instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for the duration, or until the context is done, in which case it returns
+	// the context's error.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is a Clock that uses the system time.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) Sleep(ctx context.Context, d time.Duration) error {
+	return sleep(ctx, d)
+}
+
+// WithClock sets the clock used for retry backoff, injected latency and token expiry.
+// Defaults to SystemClock.
+func WithClock(clock Clock) Opt {
+	return func(c *Config) error {
+		c.Clock = clock
+		return nil
+	}
+}
+
+func (config *Config) clock() Clock {
+	if config.Clock == nil {
+		return SystemClock{}
+	}
+	return config.Clock
+}
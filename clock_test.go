@@ -0,0 +1,48 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClock returns a fixed time, which advances when Sleep is called.
+type fakeClock struct {
+	m      sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func TestWithClock(t *testing.T) {
+	t.Run("retry backoff uses the clock", func(t *testing.T) {
+		h, _ := failingHandler(2, http.StatusServiceUnavailable)
+		clock := &fakeClock{now: time.Now()}
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Second), jsonapi.WithClock(clock))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]time.Duration{time.Second, 2 * time.Second}, clock.sleeps); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
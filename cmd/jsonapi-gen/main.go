@@ -0,0 +1,53 @@
+// Command jsonapi-gen generates a Go interface and client for a JSON API from a JSON spec.
+//
+// Usage:
+//
+//	jsonapi-gen -spec orders.json -out orders_api.go
+//
+// The generated interface can be mocked with moq or gomock, e.g.:
+//
+//	//go:generate jsonapi-gen -spec orders.json -out orders_api.go
+//	//go:generate moq -out orders_api_mock.go . OrdersAPI
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/a-h/jsonapi/gen"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "path to the JSON spec")
+	outPath := flag.String("out", "", "path to write the generated code, defaults to stdout")
+	flag.Parse()
+	if err := run(*specPath, *outPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, outPath string) error {
+	if specPath == "" {
+		return fmt.Errorf("the -spec flag is required")
+	}
+	f, err := os.Open(specPath)
+	if err != nil {
+		return fmt.Errorf("failed to open spec: %w", err)
+	}
+	defer f.Close()
+	spec, err := gen.ReadSpec(f)
+	if err != nil {
+		return err
+	}
+	src, err := gen.Generate(spec)
+	if err != nil {
+		return err
+	}
+	if outPath == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(outPath, src, 0o644)
+}
@@ -0,0 +1,167 @@
+// Command jsonapi makes JSON API calls using the same client, middleware and retry behaviour
+// as services that use the github.com/a-h/jsonapi package.
+//
+// Usage:
+//
+//	jsonapi [flags] <url>
+//
+// Examples:
+//
+//	jsonapi https://example.com/items
+//	jsonapi -X POST -d '{"name":"Item 1"}' https://example.com/items
+//	jsonapi -X PUT -d @item.json -bearer-env TOKEN https://example.com/items/1
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+type headerFlags []string
+
+func (h *headerFlags) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headerFlags) Set(value string) error {
+	*h = append(*h, value)
+	return nil
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("jsonapi", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	method := flags.String("X", "", "HTTP method, defaults to GET, or POST if a body is provided")
+	data := flags.String("d", "", "request body, or @filename to read the body from a file, or @- to read from stdin")
+	var headers headerFlags
+	flags.Var(&headers, "H", "request header in the form 'Name: value', can be repeated")
+	authorization := flags.String("authorization", "", "Authorization header value")
+	bearerEnv := flags.String("bearer-env", "", "name of an environment variable containing a bearer token")
+	timeout := flags.Duration("timeout", 30*time.Second, "request timeout")
+	retries := flags.Int("retries", 0, "number of times to retry failed requests")
+	raw := flags.Bool("raw", false, "print the response body without formatting")
+	verbose := flags.Bool("v", false, "print the response status and headers to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jsonapi [flags] <url>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single URL argument")
+	}
+	url := flags.Arg(0)
+
+	body, err := readBody(*data)
+	if err != nil {
+		return err
+	}
+	if *method == "" {
+		*method = http.MethodGet
+		if body != nil {
+			*method = http.MethodPost
+		}
+	}
+
+	opts := []jsonapi.Opt{
+		jsonapi.WithTimeout(*timeout),
+	}
+	if *retries > 0 {
+		opts = append(opts, jsonapi.WithRetry(*retries+1, 500*time.Millisecond))
+	}
+	if *authorization != "" {
+		opts = append(opts, jsonapi.WithAuthorization(*authorization))
+	}
+	if *bearerEnv != "" {
+		token := os.Getenv(*bearerEnv)
+		if token == "" {
+			return fmt.Errorf("environment variable %q is empty", *bearerEnv)
+		}
+		opts = append(opts, jsonapi.WithAuthorization("Bearer "+token))
+	}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return fmt.Errorf("invalid header %q, expected 'Name: value'", h)
+		}
+		opts = append(opts, jsonapi.WithRequestHeader(strings.TrimSpace(name), strings.TrimSpace(value)))
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, *method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := jsonapi.Raw(req, opts...)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if *verbose {
+		fmt.Fprintln(stderr, res.Proto, res.Status)
+		jsonapi.NewRedactor().Header(res.Header).Write(stderr)
+		fmt.Fprintln(stderr)
+	}
+	if err = printBody(stdout, resBody, *raw); err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("api responded with non-success status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func readBody(data string) ([]byte, error) {
+	switch {
+	case data == "":
+		return nil, nil
+	case data == "@-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(data, "@"):
+		return os.ReadFile(data[1:])
+	}
+	return []byte(data), nil
+}
+
+func printBody(w io.Writer, body []byte, raw bool) error {
+	if len(body) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if raw || json.Indent(&buf, body, "", "  ") != nil {
+		_, err := w.Write(body)
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type received struct {
+	Method        string
+	Path          string
+	Authorization string
+	Tenant        string
+	Body          string
+}
+
+func TestRun(t *testing.T) {
+	var r received
+	var calls int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		r = received{
+			Method:        req.Method,
+			Path:          req.URL.Path,
+			Authorization: req.Header.Get("Authorization"),
+			Tenant:        req.Header.Get("X-Tenant"),
+			Body:          string(body),
+		}
+		switch req.URL.Path {
+		case "/items/404":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+		case "/items/503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"1"}`))
+		}
+	}))
+	defer s.Close()
+
+	bodyFile := filepath.Join(t.TempDir(), "item.json")
+	if err := os.WriteFile(bodyFile, []byte(`{"name":"Item 2"}`), 0o600); err != nil {
+		t.Fatalf("failed to write body file: %v", err)
+	}
+	t.Setenv("TEST_JSONAPI_TOKEN", "abc")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expected       received
+		expectedCalls  int
+		expectedStdout string
+		expectedStderr string
+		expectedErr    string
+	}{
+		{
+			name:           "GET is the default method, and the response is formatted",
+			args:           []string{s.URL + "/items/1"},
+			expected:       received{Method: http.MethodGet, Path: "/items/1"},
+			expectedCalls:  1,
+			expectedStdout: "{\n  \"id\": \"1\"\n}\n",
+		},
+		{
+			name:           "POST is the default method if a body is provided",
+			args:           []string{"-d", `{"name":"Item 1"}`, s.URL + "/items"},
+			expected:       received{Method: http.MethodPost, Path: "/items", Body: `{"name":"Item 1"}`},
+			expectedCalls:  1,
+			expectedStdout: "{\n  \"id\": \"1\"\n}\n",
+		},
+		{
+			name:           "the body is read from a file, and the method can be set",
+			args:           []string{"-X", "PUT", "-d", "@" + bodyFile, "-raw", s.URL + "/items/2"},
+			expected:       received{Method: http.MethodPut, Path: "/items/2", Body: `{"name":"Item 2"}`},
+			expectedCalls:  1,
+			expectedStdout: `{"id":"1"}`,
+		},
+		{
+			name:          "headers and bearer tokens are sent",
+			args:          []string{"-H", "X-Tenant: a", "-bearer-env", "TEST_JSONAPI_TOKEN", s.URL + "/items/1"},
+			expected:      received{Method: http.MethodGet, Path: "/items/1", Authorization: "Bearer abc", Tenant: "a"},
+			expectedCalls: 1,
+		},
+		{
+			name:          "the authorization header is sent",
+			args:          []string{"-authorization", "Basic xyz", s.URL + "/items/1"},
+			expected:      received{Method: http.MethodGet, Path: "/items/1", Authorization: "Basic xyz"},
+			expectedCalls: 1,
+		},
+		{
+			name:           "verbose output includes the status",
+			args:           []string{"-v", s.URL + "/items/1"},
+			expected:       received{Method: http.MethodGet, Path: "/items/1"},
+			expectedCalls:  1,
+			expectedStderr: "HTTP/1.1 200 OK",
+		},
+		{
+			name:           "non-success statuses print the body and return an error",
+			args:           []string{s.URL + "/items/404"},
+			expected:       received{Method: http.MethodGet, Path: "/items/404"},
+			expectedCalls:  1,
+			expectedStdout: "{\n  \"error\": \"not found\"\n}\n",
+			expectedErr:    "non-success status 404",
+		},
+		{
+			name:          "failed requests are retried",
+			args:          []string{"-retries", "2", s.URL + "/items/503"},
+			expected:      received{Method: http.MethodGet, Path: "/items/503"},
+			expectedCalls: 3,
+			expectedErr:   "non-success status 503",
+		},
+		{
+			name:        "a URL is required",
+			args:        []string{},
+			expectedErr: "expected a single URL argument",
+		},
+		{
+			name:        "unknown flags return an error",
+			args:        []string{"-unknown", s.URL},
+			expectedErr: "flag provided but not defined",
+		},
+		{
+			name:        "invalid headers return an error",
+			args:        []string{"-H", "X-Tenant", s.URL},
+			expectedErr: "invalid header",
+		},
+		{
+			name:        "missing bearer tokens return an error",
+			args:        []string{"-bearer-env", "TEST_JSONAPI_MISSING", s.URL},
+			expectedErr: "TEST_JSONAPI_MISSING",
+		},
+		{
+			name:        "missing body files return an error",
+			args:        []string{"-d", "@" + filepath.Join(t.TempDir(), "missing.json"), s.URL},
+			expectedErr: "no such file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, calls = received{}, 0
+			var stdout, stderr bytes.Buffer
+			err := run(context.Background(), tt.args, &stdout, &stderr)
+			if tt.expectedErr == "" && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)) {
+				t.Fatalf("expected an error containing %q, got %v", tt.expectedErr, err)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+			if diff := cmp.Diff(tt.expected, r); diff != "" {
+				t.Error(diff)
+			}
+			if tt.expectedStdout != "" {
+				if diff := cmp.Diff(tt.expectedStdout, stdout.String()); diff != "" {
+					t.Error(diff)
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
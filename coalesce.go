@@ -0,0 +1,129 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithCoalescing batches identical GET requests that arrive within the window into a single
+// upstream request. The first request waits for the window to elapse before it is sent, and
+// every request that arrives before the response is received shares the response.
+// Only requests with the same URL and headers are coalesced, so that callers with different
+// credentials, cookies or tenant headers never share a response. The shared request is not
+// cancelled when the first caller's context is cancelled, but keeps its deadline.
+// Create the Opt once and pass it to each call that should share the coalescing window.
+func WithCoalescing(window time.Duration) Opt {
+	co := &coalescer{
+		window: window,
+		calls:  map[string]*coalescedCall{},
+		m:      &sync.Mutex{},
+	}
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, co.intercept)
+		return nil
+	}
+}
+
+type coalescer struct {
+	window time.Duration
+	calls  map[string]*coalescedCall
+	m      *sync.Mutex
+}
+
+type coalescedCall struct {
+	done chan struct{}
+	res  *http.Response
+	body []byte
+	err  error
+}
+
+func (co *coalescer) intercept(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			return next.Do(req)
+		}
+		key := coalescingKey(req)
+
+		co.m.Lock()
+		call, joined := co.calls[key]
+		if !joined {
+			call = &coalescedCall{done: make(chan struct{})}
+			co.calls[key] = call
+		}
+		co.m.Unlock()
+
+		if !joined {
+			// The shared request must not fail for the other callers if this caller gives up.
+			ctx := context.WithoutCancel(req.Context())
+			cancel := func() {}
+			if deadline, ok := req.Context().Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			go func() {
+				defer cancel()
+				co.do(next, req.WithContext(ctx), key, call)
+			}()
+		}
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		res := *call.res
+		res.Header = call.res.Header.Clone()
+		res.Body = io.NopCloser(bytes.NewReader(call.body))
+		res.Request = req
+		return &res, nil
+	})
+}
+
+// coalescingKey returns a key that is the same for requests that can share a response,
+// i.e. those with the same URL and headers, including credentials.
+func coalescingKey(req *http.Request) string {
+	var sb strings.Builder
+	sb.WriteString(req.URL.String())
+	names := slices.Sorted(maps.Keys(req.Header))
+	for _, name := range names {
+		for _, v := range req.Header[name] {
+			fmt.Fprintf(&sb, "\n%s: %s", name, v)
+		}
+	}
+	return sb.String()
+}
+
+func (co *coalescer) do(next Doer, req *http.Request, key string, call *coalescedCall) {
+	defer func() {
+		co.m.Lock()
+		delete(co.calls, key)
+		co.m.Unlock()
+		close(call.done)
+	}()
+	timer := time.NewTimer(co.window)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-req.Context().Done():
+		call.err = req.Context().Err()
+		return
+	}
+	call.res, call.err = next.Do(req)
+	if call.err != nil {
+		return
+	}
+	defer call.res.Body.Close()
+	call.body, call.err = io.ReadAll(call.res.Body)
+	if call.err != nil {
+		call.err = fmt.Errorf("failed to read coalesced response body: %w", call.err)
+	}
+}
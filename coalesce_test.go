@@ -0,0 +1,102 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithCoalescing(t *testing.T) {
+	var upstream atomic.Int64
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream.Add(1)
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithCoalescing(50 * time.Millisecond),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(expectedItemsGetResponse, resp); diff != "" {
+				t.Error(diff)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if upstream.Load() != 1 {
+		t.Errorf("expected 1 upstream request, got %d", upstream.Load())
+	}
+}
+
+func TestWithCoalescingHeaders(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, itemsGetResponse{Items: []string{r.Header.Get("X-Tenant")}}, http.StatusOK)
+	})
+	coalescing := jsonapi.WithCoalescing(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for _, tenant := range []string{"a", "b", "a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+				jsonapi.WithClient(testClient{Handler: h}), coalescing, jsonapi.WithRequestHeader("X-Tenant", tenant))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if diff := cmp.Diff([]string{tenant}, resp.Items); diff != "" {
+				t.Errorf("tenant %s received another tenant's response: %s", tenant, diff)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithCoalescingLeaderCancelled(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithCoalescing(50 * time.Millisecond),
+	}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, _, err := jsonapi.Get[itemsGetResponse](leaderCtx, "/items", opts...)
+		leaderDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	followerDone := make(chan error)
+	go func() {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		followerDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderDone; err == nil {
+		t.Error("expected the cancelled leader to return an error")
+	}
+	if err := <-followerDone; err != nil {
+		t.Errorf("expected the follower to receive the response, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// TypeHook rewrites the JSON values of a Go type during encoding and decoding, without
+// requiring the type to implement json.Marshaler or json.Unmarshaler.
+//
+// JSON values are represented as map[string]any, []any, string, json.Number, bool or nil.
+type TypeHook struct {
+	Type reflect.Type
+	// Decode rewrites a JSON value before it is unmarshaled into Type.
+	Decode func(v any) (any, error)
+	// Encode rewrites a JSON value after Type has been marshaled.
+	Encode func(v any) (any, error)
+}
+
+func (config *Config) marshal(v any) ([]byte, error) {
+	buf, err := config.marshalJSON(v)
+	if err != nil || !config.CanonicalJSON {
+		return buf, err
+	}
+	return canonicalJSON(buf)
+}
+
+func (config *Config) marshalJSON(v any) ([]byte, error) {
+	buf, err := json.Marshal(v)
+	if err != nil || !config.usesCodec() {
+		return buf, err
+	}
+	tree, err := decodeJSONTree(buf)
+	if err != nil {
+		return nil, err
+	}
+	if config.ZeroValues != ZeroValuesDefault {
+		if tree, err = applyZeroValues(reflect.ValueOf(v), tree, config.ZeroValues); err != nil {
+			return nil, err
+		}
+	}
+	w := jsonWalker{
+		visit: func(t reflect.Type, v any) (any, bool, error) {
+			for _, h := range config.TypeHooks {
+				if h.Type == t && h.Encode != nil {
+					v, err := h.Encode(v)
+					return v, true, err
+				}
+			}
+			return v, false, nil
+		},
+	}
+	if config.FieldNaming != nil {
+		w.rename = func(f jsonField) (from, to string, ok bool) {
+			return f.GoName, config.FieldNaming(f.GoName), !f.Tagged
+		}
+	}
+	if tree, err = w.walk(reflect.TypeOf(v), tree); err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+func (config *Config) unmarshalJSON(data []byte, v any) error {
+	if !config.usesCodec() {
+		return json.Unmarshal(data, v)
+	}
+	tree, err := decodeJSONTree(data)
+	if err != nil {
+		return err
+	}
+	w := jsonWalker{
+		visit: func(t reflect.Type, v any) (any, bool, error) {
+			for _, h := range config.TypeHooks {
+				if h.Type == t && h.Decode != nil {
+					v, err := h.Decode(v)
+					return v, true, err
+				}
+			}
+			return v, false, nil
+		},
+	}
+	if config.FieldNaming != nil {
+		w.rename = func(f jsonField) (from, to string, ok bool) {
+			return config.FieldNaming(f.GoName), f.GoName, !f.Tagged
+		}
+	}
+	if tree, err = w.walk(reflect.TypeOf(v), tree); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(tree); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (config *Config) usesCodec() bool {
+	return len(config.TypeHooks) > 0 || config.FieldNaming != nil || config.ZeroValues != ZeroValuesDefault
+}
+
+func decodeJSONTree(data []byte) (tree any, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&tree)
+	return tree, err
+}
+
+// jsonWalker walks a JSON value alongside the Go type that it is encoded from, or will
+// be decoded into.
+type jsonWalker struct {
+	// visit is called for each value, and can replace it. If visit returns handled=true,
+	// the children of the value are not walked.
+	visit func(t reflect.Type, v any) (replacement any, handled bool, err error)
+	// rename is called for each struct field, and can move the value of the field from one
+	// object key to another.
+	rename func(f jsonField) (from, to string, ok bool)
+}
+
+func (w jsonWalker) walk(t reflect.Type, v any) (any, error) {
+	if t == nil || v == nil {
+		return v, nil
+	}
+	replacement, handled, err := w.visit(t, v)
+	if err != nil || handled {
+		return replacement, err
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return w.walk(t.Elem(), v)
+	case reflect.Slice, reflect.Array:
+		array, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+		for i := range array {
+			if array[i], err = w.walk(t.Elem(), array[i]); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		object, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for k := range object {
+			if object[k], err = w.walk(t.Elem(), object[k]); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Struct:
+		object, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for _, f := range jsonFields(t) {
+			name := f.Name
+			if w.rename != nil {
+				if from, to, ok := w.rename(f); ok {
+					if value, exists := object[from]; exists {
+						delete(object, from)
+						object[to] = value
+					}
+					name = to
+				}
+			}
+			key, ok := findKey(object, name)
+			if !ok {
+				continue
+			}
+			if object[key], err = w.walk(f.Type, object[key]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// findKey finds the key in the object, using the same case-insensitive fallback as encoding/json.
+func findKey(object map[string]any, name string) (key string, ok bool) {
+	if _, ok = object[name]; ok {
+		return name, true
+	}
+	for k := range object {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+type jsonField struct {
+	// Name is the JSON object key of the field.
+	Name string
+	// GoName is the name of the Go struct field.
+	GoName string
+	// Tagged is true if the name was set by a json struct tag.
+	Tagged bool
+	Type   reflect.Type
+	// Index is the index sequence of the field, for use with reflect.Value.FieldByIndexErr.
+	Index []int
+}
+
+// jsonFields returns the fields of a struct type that are encoded by encoding/json,
+// including the fields of embedded structs.
+func jsonFields(t reflect.Type) (fields []jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, embedded := range jsonFields(ft) {
+					embedded.Index = append([]int{i}, embedded.Index...)
+					fields = append(fields, embedded)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		field := jsonField{Name: name, GoName: f.Name, Tagged: name != "", Type: f.Type, Index: []int{i}}
+		if !field.Tagged {
+			field.Name = f.Name
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
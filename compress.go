@@ -0,0 +1,170 @@
+package jsonapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// NewRequestCompression creates a RequestCompression with a minimum body size of 1KiB.
+func NewRequestCompression() *RequestCompression {
+	return &RequestCompression{
+		MinSize: 1024,
+		hosts:   map[string]bool{},
+	}
+}
+
+// RequestCompression gzip compresses request bodies for hosts that accept compressed requests.
+// Support is detected per host, so that compression doesn't need to be configured for each API:
+//
+//   - A host that responds to a compressed request with 415 Unsupported Media Type is sent
+//     uncompressed requests. For a host with unknown support, a 400 Bad Request to an idempotent
+//     request, such as a PUT, is also treated as a rejection if resending the request
+//     uncompressed gets a different status. A POST or PATCH that gets a 400 is not resent, since
+//     the server may have processed it.
+//   - A host that responds with an Accept-Encoding header (RFC 7694) is sent compressed requests
+//     only if the header includes gzip. Use Probe to check the header with an OPTIONS request.
+//
+// The original request is resent without compression when it's rejected.
+type RequestCompression struct {
+	// MinSize is the size of the smallest request body that is compressed.
+	MinSize int
+
+	m     sync.Mutex
+	hosts map[string]bool
+}
+
+// WithRequestCompression compresses request bodies using rc, which tracks which hosts
+// support compressed requests. Share rc between calls to remember the results.
+func WithRequestCompression(rc *RequestCompression) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, rc.intercept)
+		return nil
+	}
+}
+
+// Supported returns whether the host accepts gzip compressed requests, and whether that is known.
+func (rc *RequestCompression) Supported(host string) (supported, known bool) {
+	rc.m.Lock()
+	defer rc.m.Unlock()
+	supported, known = rc.hosts[host]
+	return supported, known
+}
+
+func (rc *RequestCompression) set(host string, supported bool) {
+	rc.m.Lock()
+	defer rc.m.Unlock()
+	rc.hosts[host] = supported
+}
+
+// Probe sends an OPTIONS request to the URL, and records whether the host supports compressed
+// requests, if the response has an Accept-Encoding header.
+func (rc *RequestCompression) Probe(ctx context.Context, url string, opts ...Opt) error {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	rc.observe(req.URL.Host, res)
+	return nil
+}
+
+// observe records support from the Accept-Encoding header of a response, returning true if found.
+func (rc *RequestCompression) observe(host string, res *http.Response) bool {
+	values := headerList(res.Header, "Accept-Encoding")
+	if len(values) == 0 {
+		return false
+	}
+	var supported bool
+	for _, v := range values {
+		coding, _, _ := strings.Cut(v, ";")
+		supported = supported || strings.EqualFold(strings.TrimSpace(coding), "gzip")
+	}
+	rc.set(host, supported)
+	return true
+}
+
+func (rc *RequestCompression) intercept(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		host := req.URL.Host
+		supported, known := rc.Supported(host)
+		if (known && !supported) || req.Header.Get("Content-Encoding") != "" {
+			return next.Do(req)
+		}
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) < rc.MinSize {
+			return next.Do(req)
+		}
+		compressed, err := gzipBytes(body)
+		if err != nil {
+			return nil, err
+		}
+		creq := req.Clone(req.Context())
+		setRequestBody(creq, compressed)
+		creq.Header.Set("Content-Encoding", "gzip")
+		res, err := next.Do(creq)
+		if err != nil {
+			return res, err
+		}
+		rejected := res.StatusCode == http.StatusUnsupportedMediaType || (!known && res.StatusCode == http.StatusBadRequest && isIdempotent(req.Method))
+		if !rejected {
+			if !rc.observe(host, res) && res.StatusCode >= 200 && res.StatusCode <= 299 {
+				rc.set(host, true)
+			}
+			return res, nil
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		setRequestBody(req, body)
+		plain, err := next.Do(req)
+		if err != nil {
+			return plain, err
+		}
+		if !rc.observe(host, plain) {
+			rc.set(host, plain.StatusCode == res.StatusCode)
+		}
+		return plain, nil
+	})
+}
+
+// isIdempotent returns whether a request with the method can be safely resent (RFC 9110 9.2.2).
+func isIdempotent(method string) bool {
+	return method != http.MethodPost && method != http.MethodPatch && method != http.MethodConnect
+}
+
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func setRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
@@ -0,0 +1,130 @@
+package jsonapi_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+// compressionHandler echoes JSON request bodies, and records the Content-Encoding of each request.
+func compressionHandler(acceptsGzip bool, encodings *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		*encodings = append(*encodings, encoding)
+		if r.Method == http.MethodOptions {
+			if acceptsGzip {
+				w.Header().Set("Accept-Encoding", "gzip")
+			} else {
+				w.Header().Set("Accept-Encoding", "identity")
+			}
+			return
+		}
+		body := io.Reader(r.Body)
+		if encoding == "gzip" {
+			if !acceptsGzip {
+				respond.WithError(w, "Unsupported Content-Encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+			gr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				respond.WithError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			body = gr
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.Copy(w, body)
+	})
+}
+
+func TestWithRequestCompression(t *testing.T) {
+	large := map[string]string{"data": strings.Repeat("a", 2048)}
+
+	t.Run("requests are compressed for hosts that accept gzip", func(t *testing.T) {
+		var encodings []string
+		rc := jsonapi.NewRequestCompression()
+		opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: compressionHandler(true, &encodings)}), jsonapi.WithRequestCompression(rc)}
+		resp, err := jsonapi.Post[map[string]string, map[string]string](context.Background(), "http://api.example.com/items", large, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp["data"] != large["data"] {
+			t.Error("expected the body to be echoed")
+		}
+		if len(encodings) != 1 || encodings[0] != "gzip" {
+			t.Errorf("expected a single gzip request, got %q", encodings)
+		}
+		if supported, known := rc.Supported("api.example.com"); !supported || !known {
+			t.Errorf("expected gzip to be supported, got %v, %v", supported, known)
+		}
+	})
+	t.Run("rejected requests are resent uncompressed, and later requests aren't compressed", func(t *testing.T) {
+		var encodings []string
+		rc := jsonapi.NewRequestCompression()
+		opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: compressionHandler(false, &encodings)}), jsonapi.WithRequestCompression(rc)}
+		for range 2 {
+			if _, err := jsonapi.Post[map[string]string, map[string]string](context.Background(), "http://api.example.com/items", large, opts...); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if strings.Join(encodings, ",") != "gzip,," {
+			t.Errorf("expected a gzip request followed by two plain requests, got %q", encodings)
+		}
+		if supported, known := rc.Supported("api.example.com"); supported || !known {
+			t.Errorf("expected gzip to be unsupported, got %v, %v", supported, known)
+		}
+	})
+	t.Run("support can be probed with OPTIONS", func(t *testing.T) {
+		var encodings []string
+		rc := jsonapi.NewRequestCompression()
+		client := jsonapi.WithClient(testClient{Handler: compressionHandler(false, &encodings)})
+		if err := rc.Probe(context.Background(), "http://api.example.com/items", client); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if supported, known := rc.Supported("api.example.com"); supported || !known {
+			t.Errorf("expected gzip to be unsupported, got %v, %v", supported, known)
+		}
+	})
+	t.Run("small requests are not compressed", func(t *testing.T) {
+		var encodings []string
+		opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: compressionHandler(true, &encodings)}), jsonapi.WithRequestCompression(jsonapi.NewRequestCompression())}
+		if _, err := jsonapi.Post[map[string]string, map[string]string](context.Background(), "http://api.example.com/items", map[string]string{"a": "b"}, opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(encodings) != 1 || encodings[0] != "" {
+			t.Errorf("expected a plain request, got %q", encodings)
+		}
+	})
+	t.Run("a 400 only triggers a resend for idempotent requests", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodPut} {
+			var encodings []string
+			rc := jsonapi.NewRequestCompression()
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encodings = append(encodings, r.Header.Get("Content-Encoding"))
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					respond.WithError(w, "Bad Request", http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				io.Copy(w, r.Body)
+			})
+			opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRequestCompression(rc)}
+			_, err := jsonapi.Do[map[string]string, map[string]string](context.Background(), method, "http://api.example.com/items", &large, opts...)
+			if method == http.MethodPost {
+				if err == nil || len(encodings) != 1 {
+					t.Errorf("%s: expected the 400 to be returned without a resend, got %v, %q", method, err, encodings)
+				}
+				continue
+			}
+			if err != nil || strings.Join(encodings, ",") != "gzip," {
+				t.Errorf("%s: expected a resend without compression, got %v, %q", method, err, encodings)
+			}
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package jsonapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// NotModifiedError is returned when the server responds with 304 Not Modified to a
+// conditional request, e.g. one made with WithIfModifiedSince.
+type NotModifiedError struct {
+	InvalidStatusError
+}
+
+func (e NotModifiedError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// PreconditionFailedError is returned when the server responds with 412 Precondition Failed,
+// e.g. because the resource was modified after the time given to WithIfUnmodifiedSince.
+type PreconditionFailedError struct {
+	InvalidStatusError
+}
+
+func (e PreconditionFailedError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// WithIfModifiedSince sets the If-Modified-Since header, so that the server responds with
+// 304 Not Modified, returned as a NotModifiedError, if the resource hasn't changed since t.
+func WithIfModifiedSince(t time.Time) Opt {
+	return WithRequestHeader("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// WithIfUnmodifiedSince sets the If-Unmodified-Since header, so that the server rejects
+// the request with 412 Precondition Failed, returned as a PreconditionFailedError, if the
+// resource has changed since t. Use it to avoid overwriting changes made by other clients.
+func WithIfUnmodifiedSince(t time.Time) Opt {
+	return WithRequestHeader("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,55 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/items/1", func(w http.ResponseWriter, r *http.Request) {
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil && modified.After(since) {
+			respond.WithError(w, "Modified", http.StatusPreconditionFailed)
+			return
+		}
+		respond.WithJSON(w, map[string]string{"id": "1"}, http.StatusOK)
+	})
+	client := jsonapi.WithClient(testClient{Handler: mux})
+	ctx := context.Background()
+
+	t.Run("If-Modified-Since returns NotModifiedError for unchanged resources", func(t *testing.T) {
+		_, _, err := jsonapi.Get[map[string]string](ctx, "/items/1", client, jsonapi.WithIfModifiedSince(modified.Add(time.Hour)))
+		var nme jsonapi.NotModifiedError
+		if !errors.As(err, &nme) {
+			t.Fatalf("expected NotModifiedError, got %v", err)
+		}
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusNotModified {
+			t.Errorf("expected the error to wrap a 304 InvalidStatusError, got %v", err)
+		}
+	})
+	t.Run("If-Modified-Since returns changed resources", func(t *testing.T) {
+		resp, ok, err := jsonapi.Get[map[string]string](ctx, "/items/1", client, jsonapi.WithIfModifiedSince(modified.Add(-time.Hour)))
+		if err != nil || !ok || resp["id"] != "1" {
+			t.Errorf("expected the resource, got %v, %v, %v", resp, ok, err)
+		}
+	})
+	t.Run("If-Unmodified-Since returns PreconditionFailedError for changed resources", func(t *testing.T) {
+		_, err := jsonapi.Put[map[string]string, map[string]string](ctx, "/items/1", map[string]string{"id": "1"}, client, jsonapi.WithIfUnmodifiedSince(modified.Add(-time.Hour)))
+		var pfe jsonapi.PreconditionFailedError
+		if !errors.As(err, &pfe) || pfe.Status != http.StatusPreconditionFailed {
+			t.Fatalf("expected PreconditionFailedError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package jsonapi
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// WithStrictContentType rejects 2xx responses whose Content-Type is not JSON with an
+// InvalidContentTypeError, instead of attempting to decode them. This produces clearer errors
+// when a proxy or misconfigured server returns an HTML page.
+func WithStrictContentType() Opt {
+	return func(c *Config) error {
+		c.StrictContentType = true
+		return nil
+	}
+}
+
+type InvalidContentTypeError struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+func (e InvalidContentTypeError) Error() string {
+	body := e.Body
+	if len(body) > 256 {
+		body = body[:256] + "..."
+	}
+	return fmt.Sprintf("api responded with 2xx status code %d, but the Content-Type %q is not JSON: %q", e.Status, e.ContentType, body)
+}
+
+// isJSONContentType returns true for application/json and structured syntax suffixes
+// such as application/problem+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
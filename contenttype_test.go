@@ -0,0 +1,53 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithStrictContentType(t *testing.T) {
+	routes := createTestRoutes()
+	routes.HandleFunc("/items/get/html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Gateway</body></html>"))
+	})
+	routes.HandleFunc("/items/get/vnd", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json; charset=utf-8")
+		w.Write([]byte(`{"items":["item1","item2"]}`))
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: routes}),
+		jsonapi.WithStrictContentType(),
+	}
+	ctx := context.Background()
+
+	t.Run("JSON content types are accepted", func(t *testing.T) {
+		for _, url := range []string{"/items/get/ok", "/items/get/vnd"} {
+			if _, _, err := jsonapi.Get[itemsGetResponse](ctx, url, opts...); err != nil {
+				t.Errorf("%s: expected no error, got %v", url, err)
+			}
+		}
+	})
+	t.Run("responses without a body are accepted", func(t *testing.T) {
+		if _, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/ok", opts...); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if _, err := jsonapi.Delete[map[string]any](ctx, "/items/delete/chunked", opts...); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+	t.Run("other content types are rejected", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/html", opts...)
+		var icte jsonapi.InvalidContentTypeError
+		if !errors.As(err, &icte) {
+			t.Fatalf("expected InvalidContentTypeError, got %v", err)
+		}
+		if icte.ContentType != "text/html" {
+			t.Errorf("expected content type text/html, got %q", icte.ContentType)
+		}
+	})
+}
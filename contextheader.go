@@ -0,0 +1,38 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// WithContextHeaders sets request headers from values in the request context, e.g. a tenant ID
+// or user ID. The mapping is from header name to context key. Values are formatted with
+// fmt.Sprint, and headers with no value in the context are not set.
+//
+//	jsonapi.WithContextHeaders(map[string]any{
+//		"X-Tenant-ID": tenantIDKey{},
+//		"X-User-ID":   userIDKey{},
+//	})
+func WithContextHeaders(mapping map[string]any) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, contextHeaderMiddleware(mapping))
+		return nil
+	}
+}
+
+type contextHeaderMiddleware map[string]any
+
+func (m contextHeaderMiddleware) Request(req *http.Request) error {
+	for header, key := range m {
+		v := req.Context().Value(key)
+		if v == nil {
+			continue
+		}
+		req.Header.Set(header, fmt.Sprint(v))
+	}
+	return nil
+}
+
+func (m contextHeaderMiddleware) Response(res *http.Response) error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+type tenantIDKey struct{}
+type userIDKey struct{}
+
+func TestWithContextHeaders(t *testing.T) {
+	var header http.Header
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	ctx := context.WithValue(context.Background(), tenantIDKey{}, "tenant-1")
+	_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items",
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithContextHeaders(map[string]any{
+			"X-Tenant-ID": tenantIDKey{},
+			"X-User-ID":   userIDKey{},
+		}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if header.Get("X-Tenant-ID") != "tenant-1" {
+		t.Errorf("expected the tenant ID header, got %q", header.Get("X-Tenant-ID"))
+	}
+	if _, ok := header["X-User-Id"]; ok {
+		t.Error("expected no user ID header")
+	}
+}
@@ -0,0 +1,110 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number. It is encoded as a JSON number using
+// the exact digits it was decoded or parsed from, so monetary values round-trip without float
+// artifacts. Decimal can also be decoded from a JSON string containing a number, e.g. "12.30".
+//
+// The zero value is 0.
+type Decimal struct {
+	literal string
+}
+
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// ParseDecimal parses a decimal number using JSON number syntax.
+func ParseDecimal(s string) (d Decimal, err error) {
+	if !decimalPattern.MatchString(s) {
+		return d, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{literal: s}, nil
+}
+
+// MustParseDecimal is like ParseDecimal, but panics if s is invalid.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) String() string {
+	if d.literal == "" {
+		return "0"
+	}
+	return d.literal
+}
+
+// Rat returns the decimal as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	s := strings.ToLower(d.String())
+	mantissa, exponent, _ := strings.Cut(s, "e")
+	var scale int
+	if _, fraction, ok := strings.Cut(mantissa, "."); ok {
+		scale = len(fraction)
+	}
+	if exponent != "" {
+		e, _ := strconv.Atoi(exponent)
+		scale -= e
+	}
+	return max(scale, 0)
+}
+
+// Cmp compares d and o, returning -1, 0 or +1.
+func (d Decimal) Cmp(o Decimal) int {
+	return d.Rat().Cmp(o.Rat())
+}
+
+// Add returns d + o, with the larger scale of d and o.
+func (d Decimal) Add(o Decimal) Decimal {
+	return newDecimalFromRat(new(big.Rat).Add(d.Rat(), o.Rat()), max(d.Scale(), o.Scale()))
+}
+
+// Sub returns d - o, with the larger scale of d and o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return newDecimalFromRat(new(big.Rat).Sub(d.Rat(), o.Rat()), max(d.Scale(), o.Scale()))
+}
+
+// Mul returns d * o, with the sum of the scales of d and o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return newDecimalFromRat(new(big.Rat).Mul(d.Rat(), o.Rat()), d.Scale()+o.Scale())
+}
+
+func newDecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal{literal: r.FloatString(scale)}
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) (err error) {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err = json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	*d, err = ParseDecimal(s)
+	return err
+}
@@ -0,0 +1,50 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type invoice struct {
+	Total jsonapi.Decimal `json:"total"`
+}
+
+func TestDecimal(t *testing.T) {
+	t.Run("values round-trip without float artifacts", func(t *testing.T) {
+		var received string
+		req := invoice{Total: jsonapi.MustParseDecimal("12345678901234567890.10")}
+		resp, err := jsonapi.Post[invoice, invoice](context.Background(), "/invoices", req, jsonapi.WithClient(echoClient(&received)))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if received != `{"total":12345678901234567890.10}` {
+			t.Errorf("unexpected request body %s", received)
+		}
+		if resp.Total.String() != "12345678901234567890.10" {
+			t.Errorf("unexpected total %s", resp.Total)
+		}
+	})
+	t.Run("arithmetic keeps the scale", func(t *testing.T) {
+		a, b := jsonapi.MustParseDecimal("0.1"), jsonapi.MustParseDecimal("0.20")
+		if sum := a.Add(b); sum.String() != "0.30" {
+			t.Errorf("expected 0.30, got %s", sum)
+		}
+		if product := a.Mul(b); product.String() != "0.020" {
+			t.Errorf("expected 0.020, got %s", product)
+		}
+		if a.Cmp(b) != -1 {
+			t.Errorf("expected 0.1 < 0.20")
+		}
+	})
+	t.Run("strings and invalid values", func(t *testing.T) {
+		var inv invoice
+		if err := inv.Total.UnmarshalJSON([]byte(`"9.99"`)); err != nil || inv.Total.String() != "9.99" {
+			t.Errorf("expected 9.99, got %s, %v", inv.Total, err)
+		}
+		if err := inv.Total.UnmarshalJSON([]byte(`"1/3"`)); err == nil {
+			t.Error("expected an error for a fraction")
+		}
+	})
+}
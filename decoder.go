@@ -0,0 +1,46 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// WithDecoder registers a function that decodes JSON values of type T in response bodies,
+// wherever T appears in the response type, without modifying the types, e.g. to decode
+// time.Duration values from strings such as "1m30s":
+//
+//	jsonapi.WithDecoder(func(data []byte) (d time.Duration, err error) {
+//		var s string
+//		if err = json.Unmarshal(data, &s); err != nil {
+//			return d, err
+//		}
+//		return time.ParseDuration(s)
+//	})
+//
+// The decoded value is marshaled with encoding/json and unmarshaled into the target, so T
+// must round-trip through encoding/json, e.g. a type with exported fields, or a named string
+// or number. null values are not passed to decode.
+func WithDecoder[T any](decode func(data []byte) (T, error)) Opt {
+	t := reflect.TypeFor[T]()
+	return func(c *Config) error {
+		c.TypeHooks = append(c.TypeHooks, TypeHook{
+			Type: t,
+			Decode: func(v any) (any, error) {
+				data, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				value, err := decode(data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode %v: %w", t, err)
+				}
+				if data, err = json.Marshal(value); err != nil {
+					return nil, fmt.Errorf("failed to decode %v: %w", t, err)
+				}
+				return decodeJSONTree(data)
+			},
+		})
+		return nil
+	}
+}
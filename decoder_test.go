@@ -0,0 +1,60 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type job struct {
+	Name     string          `json:"name"`
+	Timeout  time.Duration   `json:"timeout"`
+	Retries  []time.Duration `json:"retries"`
+	Deadline *time.Duration  `json:"deadline"`
+}
+
+func parseDuration(data []byte) (d time.Duration, err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return d, err
+	}
+	return time.ParseDuration(s)
+}
+
+func TestWithDecoder(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/jobs/1":
+			w.Write([]byte(`{"name":"backup","timeout":"1m30s","retries":["1s","2s"],"deadline":null}`))
+		default:
+			w.Write([]byte(`{"name":"backup","timeout":"soon"}`))
+		}
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithDecoder(parseDuration),
+	}
+
+	t.Run("values of the type are decoded with the function", func(t *testing.T) {
+		resp, _, err := jsonapi.Get[job](context.Background(), "/jobs/1", opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		expected := job{Name: "backup", Timeout: 90 * time.Second, Retries: []time.Duration{time.Second, 2 * time.Second}}
+		if diff := cmp.Diff(expected, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("decode errors are returned", func(t *testing.T) {
+		_, _, err := jsonapi.Get[job](context.Background(), "/jobs/2", opts...)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package jsonapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// WithDedupKey sets the header on each POST, PUT and PATCH request to a key derived from the
+// method, URL and request body, so that downstream services can discard duplicate mutations,
+// e.g. retries and replays. JSON bodies are canonicalized before hashing, so equal values
+// produce the same key regardless of formatting or key order. The header isn't modified if
+// it is already set.
+func WithDedupKey(header string) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, dedupKeyMiddleware(header))
+		return nil
+	}
+}
+
+type dedupKeyMiddleware string
+
+func (m dedupKeyMiddleware) Request(req *http.Request) error {
+	header := string(m)
+	if !isMutatingMethod(req.Method) || req.Header.Get(header) != "" {
+		return nil
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(header, dedupKey(req.Method, req.URL.String(), body))
+	return nil
+}
+
+func (m dedupKeyMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+func dedupKey(method, url string, body []byte) string {
+	if canonical, err := canonicalJSON(body); err == nil {
+		body = canonical
+	}
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithDedupKey(t *testing.T) {
+	var keys []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		respond.WithJSON(w, map[string]any{}, http.StatusOK)
+	})
+	send := func(body string) {
+		req, err := http.NewRequest(http.MethodPost, "/orders", bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		res, err := jsonapi.Raw(req, jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithDedupKey("Idempotency-Key"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		res.Body.Close()
+	}
+	send(`{"a":1,"b":2}`)
+	send(`{ "b": 2.0, "a": 1 }`)
+	send(`{"a":1,"b":3}`)
+
+	if len(keys) != 3 || keys[0] == "" {
+		t.Fatalf("expected 3 keys, got %v", keys)
+	}
+	if keys[0] != keys[1] {
+		t.Errorf("expected equal bodies to have the same key, got %v", keys)
+	}
+	if keys[0] == keys[2] {
+		t.Errorf("expected different bodies to have different keys, got %v", keys)
+	}
+
+	_, _, err := jsonapi.Get[map[string]any](context.Background(), "/orders", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithDedupKey("Idempotency-Key"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys[3] != "" {
+		t.Errorf("expected no key for GET requests, got %q", keys[3])
+	}
+}
@@ -0,0 +1,113 @@
+package jsonapi
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationNotice is reported when a response has Deprecation, Sunset or Warning headers.
+type DeprecationNotice struct {
+	// Operation is the logical name of the call, see WithOperationName.
+	Operation string `json:"operation,omitempty"`
+	Method    string `json:"method"`
+	URL       string `json:"url"`
+	// Deprecated is true if the response has a Deprecation header.
+	Deprecated bool `json:"deprecated"`
+	// Deprecation is the time that the resource was, or will be, deprecated, if known.
+	Deprecation time.Time `json:"deprecation,omitempty"`
+	// Sunset is the time that the resource will stop responding, if known.
+	Sunset time.Time `json:"sunset,omitempty"`
+	// Links are the URLs of Link headers with a "deprecation" or "sunset" relation,
+	// which typically document the migration.
+	Links []string `json:"links,omitempty"`
+	// Warnings are the values of Warning headers.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// WithDeprecationNotice calls f when a response has a Deprecation, Sunset or Warning header,
+// so that upcoming removals of upstream APIs can be logged or counted. See LogDeprecationNotice.
+func WithDeprecationNotice(f func(n DeprecationNotice)) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				res, err := next.Do(req)
+				if err != nil {
+					return res, err
+				}
+				if n, ok := parseDeprecationNotice(req, res.Header); ok {
+					if err = safely(func() error { f(n); return nil }); err != nil {
+						res.Body.Close()
+						return nil, err
+					}
+				}
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
+
+// LogDeprecationNotice logs the notice as a warning using the default slog logger.
+func LogDeprecationNotice(n DeprecationNotice) {
+	attrs := []any{slog.String("method", n.Method), slog.String("url", n.URL)}
+	if n.Operation != "" {
+		attrs = append(attrs, slog.String("operation", n.Operation))
+	}
+	if !n.Deprecation.IsZero() {
+		attrs = append(attrs, slog.Time("deprecation", n.Deprecation))
+	}
+	if !n.Sunset.IsZero() {
+		attrs = append(attrs, slog.Time("sunset", n.Sunset))
+	}
+	if len(n.Links) > 0 {
+		attrs = append(attrs, slog.Any("links", n.Links))
+	}
+	if len(n.Warnings) > 0 {
+		attrs = append(attrs, slog.Any("warnings", n.Warnings))
+	}
+	slog.Warn("jsonapi: upstream API is deprecated", attrs...)
+}
+
+func parseDeprecationNotice(req *http.Request, h http.Header) (n DeprecationNotice, ok bool) {
+	deprecation, sunset, warnings := h.Get("Deprecation"), h.Get("Sunset"), h.Values("Warning")
+	if deprecation == "" && sunset == "" && len(warnings) == 0 {
+		return n, false
+	}
+	n.Operation = OperationName(req.Context())
+	n.Method = req.Method
+	n.URL = req.URL.String()
+	if deprecation != "" {
+		n.Deprecated = true
+		n.Deprecation = parseDeprecationDate(deprecation)
+	}
+	if sunset != "" {
+		n.Sunset, _ = http.ParseTime(sunset)
+	}
+	n.Warnings = warnings
+	for _, link := range h.Values("Link") {
+		for _, l := range strings.Split(link, ",") {
+			target, params, _ := strings.Cut(l, ";")
+			if !strings.Contains(params, `rel="deprecation"`) && !strings.Contains(params, `rel="sunset"`) &&
+				!strings.Contains(params, "rel=deprecation") && !strings.Contains(params, "rel=sunset") {
+				continue
+			}
+			n.Links = append(n.Links, strings.Trim(strings.TrimSpace(target), "<>"))
+		}
+	}
+	return n, true
+}
+
+// parseDeprecationDate parses the Deprecation header, which is a structured field date,
+// e.g. "@1688169599", or in earlier drafts, an HTTP date or "true".
+func parseDeprecationDate(v string) time.Time {
+	if strings.HasPrefix(v, "@") {
+		if seconds, err := strconv.ParseInt(v[1:], 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+	t, _ := http.ParseTime(v)
+	return t
+}
@@ -0,0 +1,79 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithDeprecationNotice(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected []jsonapi.DeprecationNotice
+	}{
+		{
+			name:     "no headers",
+			header:   http.Header{},
+			expected: nil,
+		},
+		{
+			name: "deprecation and sunset",
+			header: http.Header{
+				"Deprecation": []string{"@1688169599"},
+				"Sunset":      []string{"Wed, 11 Nov 2026 23:59:59 GMT"},
+				"Link":        []string{`<https://example.com/docs/migrate>; rel="deprecation", <https://example.com>; rel="home"`},
+			},
+			expected: []jsonapi.DeprecationNotice{
+				{
+					Method:      http.MethodGet,
+					URL:         "/items",
+					Deprecated:  true,
+					Deprecation: time.Unix(1688169599, 0).UTC(),
+					Sunset:      time.Date(2026, time.November, 11, 23, 59, 59, 0, time.UTC),
+					Links:       []string{"https://example.com/docs/migrate"},
+				},
+			},
+		},
+		{
+			name: "warning",
+			header: http.Header{
+				"Warning": []string{`299 - "Deprecated API"`},
+			},
+			expected: []jsonapi.DeprecationNotice{
+				{
+					Method:   http.MethodGet,
+					URL:      "/items",
+					Warnings: []string{`299 - "Deprecated API"`},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+			})
+			var notices []jsonapi.DeprecationNotice
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+				jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithDeprecationNotice(func(n jsonapi.DeprecationNotice) {
+					notices = append(notices, n)
+				}))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, notices); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package jsonapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AddressFamily selects the IP versions used to connect to servers.
+type AddressFamily int
+
+const (
+	// AddressFamilyAuto connects using IPv4 or IPv6, preferring IPv6 if both are available.
+	AddressFamilyAuto AddressFamily = iota
+	// IPv4Only connects using IPv4 only.
+	IPv4Only
+	// IPv6Only connects using IPv6 only.
+	IPv6Only
+)
+
+// WithAddressFamily restricts connections to the address family, e.g. for networks that
+// black-hole IPv6 traffic, where dialing stalls until the IPv6 attempt times out.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithAddressFamily(family AddressFamily) Opt {
+	if family == AddressFamilyAuto {
+		return func(c *Config) error { return nil }
+	}
+	suffix := "4"
+	if family == IPv6Only {
+		suffix = "6"
+	}
+	return withTransport(func(t *http.Transport) error {
+		dial := t.DialContext
+		if dial == nil {
+			dial = dialContext
+		}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if strings.HasPrefix(network, "tcp") {
+				network = "tcp" + suffix
+			}
+			return dial(ctx, network, addr)
+		}
+		return nil
+	})
+}
+
+// WithFallbackDelay sets how long to wait for an IPv6 connection before trying IPv4 in parallel,
+// when a host has both addresses. The default is 300ms. A negative delay disables the fallback.
+//
+// The transport's existing dialing, including options such as WithBlockPrivateNetworks, is kept.
+// The delay is used by the dialer of this package's default client, and by options that add a
+// dialer, it has no effect on a DialContext set on a custom *http.Transport.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithFallbackDelay(d time.Duration) Opt {
+	return withTransport(func(t *http.Transport) error {
+		dial := t.DialContext
+		if dial == nil {
+			dial = dialContext
+		}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dial(context.WithValue(ctx, fallbackDelayContextKey{}, d), network, addr)
+		}
+		return nil
+	})
+}
+
+type fallbackDelayContextKey struct{}
+
+// dialContext dials using the default timeouts of this package, and the fallback delay set by
+// WithFallbackDelay.
+func dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if delay, ok := ctx.Value(fallbackDelayContextKey{}).(time.Duration); ok {
+		d.FallbackDelay = delay
+	}
+	return d.DialContext(ctx, network, addr)
+}
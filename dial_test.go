@@ -0,0 +1,68 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithAddressFamily(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+	_, port, err := net.SplitHostPort(s.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %v", err)
+	}
+	// The server listens on 127.0.0.1, which can only be reached using IPv4.
+	url := "http://127.0.0.1:" + port
+
+	t.Run("IPv4Only connects to IPv4 addresses", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), url,
+			jsonapi.WithFallbackDelay(-1),
+			jsonapi.WithAddressFamily(jsonapi.IPv4Only))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+	t.Run("IPv6Only doesn't connect to IPv4 addresses", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, url, jsonapi.WithAddressFamily(jsonapi.IPv6Only))
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestWithFallbackDelay(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+
+	t.Run("requests are sent", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithFallbackDelay(50*time.Millisecond))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+	t.Run("earlier dial checks are kept", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithClient(&http.Client{}),
+			jsonapi.WithBlockPrivateNetworks(),
+			jsonapi.WithFallbackDelay(-1))
+		var fhe jsonapi.ForbiddenHostError
+		if !errors.As(err, &fhe) {
+			t.Errorf("expected ForbiddenHostError, got %v", err)
+		}
+	})
+}
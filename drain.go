@@ -0,0 +1,117 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrClosed is returned for requests made after Drainer.Close has been called.
+var ErrClosed = errors.New("jsonapi: closed")
+
+// NewDrainer creates a Drainer.
+func NewDrainer() *Drainer {
+	return &Drainer{
+		drained: make(chan struct{}),
+		m:       &sync.Mutex{},
+	}
+}
+
+// Drainer tracks in-flight requests so that they can complete during a graceful shutdown.
+// A request is in-flight until its response body is closed.
+type Drainer struct {
+	inFlight int
+	closed   bool
+	drained  chan struct{}
+	onClose  []func()
+	m        *sync.Mutex
+}
+
+// WithDrainer tracks requests using the drainer. Requests made after the drainer has been
+// closed return ErrClosed. Create the drainer once and share it between calls.
+func WithDrainer(d *Drainer) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, d.intercept)
+		return nil
+	}
+}
+
+// InFlight returns the number of requests in-flight.
+func (d *Drainer) InFlight() int {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.inFlight
+}
+
+// OnClose registers f to be called by Close once in-flight requests have completed,
+// e.g. to stop background token refreshers or health checks.
+func (d *Drainer) OnClose(f func()) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.onClose = append(d.onClose, f)
+}
+
+// Close stops new requests, and waits for in-flight requests to complete, or the context
+// to be done. The functions registered with OnClose are then called, even if the context
+// is done, and the context error is returned.
+func (d *Drainer) Close(ctx context.Context) (err error) {
+	d.m.Lock()
+	if !d.closed {
+		d.closed = true
+		if d.inFlight == 0 {
+			close(d.drained)
+		}
+	}
+	d.m.Unlock()
+	select {
+	case <-d.drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	d.m.Lock()
+	onClose := d.onClose
+	d.onClose = nil
+	d.m.Unlock()
+	for _, f := range onClose {
+		if ferr := safely(func() error { f(); return nil }); ferr != nil {
+			err = errors.Join(err, ferr)
+		}
+	}
+	return err
+}
+
+func (d *Drainer) acquire() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	d.inFlight++
+	return nil
+}
+
+func (d *Drainer) release() {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.inFlight--
+	if d.closed && d.inFlight == 0 {
+		close(d.drained)
+	}
+}
+
+func (d *Drainer) intercept(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := d.acquire(); err != nil {
+			return nil, err
+		}
+		release := sync.OnceFunc(d.release)
+		res, err := next.Do(req)
+		if err != nil {
+			release()
+			return res, err
+		}
+		res.Body = releaseOnClose{ReadCloser: res.Body, release: release}
+		return res, nil
+	})
+}
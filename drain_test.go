@@ -0,0 +1,66 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestDrainer(t *testing.T) {
+	started, unblock := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	d := jsonapi.NewDrainer()
+	var stopped bool
+	d.OnClose(func() { stopped = true })
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithDrainer(d),
+	}
+
+	requestErr := make(chan error)
+	go func() {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		requestErr <- err
+	}()
+	<-started
+	if d.InFlight() != 1 {
+		t.Errorf("expected 1 request in-flight, got %d", d.InFlight())
+	}
+
+	t.Run("Close is bounded by the context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+	t.Run("new requests are rejected", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		if !errors.Is(err, jsonapi.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	})
+	t.Run("Close waits for in-flight requests", func(t *testing.T) {
+		stopped = false
+		d.OnClose(func() { stopped = true })
+		close(unblock)
+		if err := d.Close(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if err := <-requestErr; err != nil {
+			t.Errorf("expected the in-flight request to succeed, got %v", err)
+		}
+		if !stopped {
+			t.Error("expected OnClose functions to be called")
+		}
+	})
+}
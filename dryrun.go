@@ -0,0 +1,52 @@
+package jsonapi
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// DryRunError is returned by requests that were not sent because dry run mode is enabled.
+// The header and body are redacted using the config's Redactor, or NewRedactor if none is set.
+type DryRunError struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+func (e DryRunError) Error() string {
+	return fmt.Sprintf("dry run: %s %s not sent", e.Method, e.URL)
+}
+
+// WithDryRun prevents POST, PUT, PATCH and DELETE requests from being sent. The request,
+// including any changes made by middleware, is logged using the default slog logger, and a
+// DryRunError is returned. Other requests are sent as normal.
+func WithDryRun() Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if !isMutatingMethod(req.Method) && req.Method != http.MethodDelete {
+					return next.Do(req)
+				}
+				body, err := readRequestBody(req)
+				if err != nil {
+					return nil, err
+				}
+				r := c.Redactor
+				if r == nil {
+					r = NewRedactor()
+				}
+				e := DryRunError{
+					Method: req.Method,
+					URL:    req.URL.String(),
+					Header: r.Header(req.Header),
+					Body:   r.Body(body),
+				}
+				slog.Info("dry run", slog.String("method", e.Method), slog.String("url", e.URL), slog.Any("header", e.Header), slog.String("body", string(e.Body)))
+				return nil, e
+			})
+		})
+		return nil
+	}
+}
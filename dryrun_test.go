@@ -0,0 +1,57 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithDryRun(t *testing.T) {
+	var sent int
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		createTestRoutes().ServeHTTP(w, r)
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithAuthorization("Bearer abc"),
+		jsonapi.WithDryRun(),
+	}
+
+	t.Run("mutating requests are not sent", func(t *testing.T) {
+		sent = 0
+		_, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "/items/post/ok", map[string]any{"password": "secret", "name": "a"}, opts...)
+		var dre jsonapi.DryRunError
+		if !errors.As(err, &dre) {
+			t.Fatalf("expected DryRunError, got %v", err)
+		}
+		if sent != 0 {
+			t.Errorf("expected no requests to be sent, got %d", sent)
+		}
+		if dre.Method != http.MethodPost || dre.URL != "/items/post/ok" {
+			t.Errorf("unexpected request %s %s", dre.Method, dre.URL)
+		}
+		if dre.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected the request to include middleware headers, got %v", dre.Header)
+		}
+		if dre.Header.Get("Authorization") != "[REDACTED]" {
+			t.Errorf("expected the Authorization header to be redacted, got %q", dre.Header.Get("Authorization"))
+		}
+		if string(dre.Body) != `{"name":"a","password":"[REDACTED]"}` {
+			t.Errorf("unexpected body %s", dre.Body)
+		}
+	})
+	t.Run("GET requests are sent", func(t *testing.T) {
+		sent = 0
+		_, ok, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", opts...)
+		if err != nil || !ok {
+			t.Fatalf("expected ok, got %v, %v", ok, err)
+		}
+		if sent != 1 {
+			t.Errorf("expected 1 request, got %d", sent)
+		}
+	})
+}
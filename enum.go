@@ -0,0 +1,34 @@
+package jsonapi
+
+import (
+	"reflect"
+	"slices"
+)
+
+// WithEnum decodes string values of type T that are not in known as the unknown sentinel,
+// so that values added to an upstream enum don't cause decoding to fail. If onUnknown is not
+// nil, it's called with each unrecognized value, e.g. to log or count it.
+//
+//	jsonapi.WithEnum([]OrderStatus{OrderStatusOpen, OrderStatusClosed}, OrderStatusUnknown, func(v string) {
+//		slog.Warn("unknown order status", slog.String("status", v))
+//	})
+func WithEnum[T ~string](known []T, unknown T, onUnknown func(value string)) Opt {
+	return func(c *Config) error {
+		c.TypeHooks = append(c.TypeHooks, TypeHook{
+			Type: reflect.TypeFor[T](),
+			Decode: func(v any) (any, error) {
+				s, ok := v.(string)
+				if !ok || slices.Contains(known, T(s)) {
+					return v, nil
+				}
+				if onUnknown != nil {
+					if err := safely(func() error { onUnknown(s); return nil }); err != nil {
+						return nil, err
+					}
+				}
+				return string(unknown), nil
+			},
+		})
+		return nil
+	}
+}
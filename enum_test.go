@@ -0,0 +1,49 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type shipmentStatus string
+
+const (
+	shipmentStatusUnknown   shipmentStatus = "unknown"
+	shipmentStatusPending   shipmentStatus = "pending"
+	shipmentStatusDelivered shipmentStatus = "delivered"
+)
+
+type shipment struct {
+	Status  shipmentStatus   `json:"status"`
+	History []shipmentStatus `json:"history"`
+}
+
+func TestWithEnum(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"lost","history":["pending","in_transit","lost"]}`))
+	})
+	var unknown []string
+	resp, _, err := jsonapi.Get[shipment](context.Background(), "/shipments/1",
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithEnum([]shipmentStatus{shipmentStatusPending, shipmentStatusDelivered}, shipmentStatusUnknown, func(v string) {
+			unknown = append(unknown, v)
+		}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := shipment{
+		Status:  shipmentStatusUnknown,
+		History: []shipmentStatus{shipmentStatusPending, shipmentStatusUnknown, shipmentStatusUnknown},
+	}
+	if diff := cmp.Diff(expected, resp); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"lost", "in_transit", "lost"}, unknown); diff != "" {
+		t.Errorf("unexpected unknown values: %s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// FromEnv creates options from environment variables, so that deployments can configure
+// API clients without code changes. Each variable name is the prefix, an underscore, and:
+//
+//   - BASE_URL: the URL that relative request URLs are resolved against, see WithBaseURL.
+//   - TIMEOUT: the overall request timeout, e.g. "30s", see WithTimeout.
+//   - PROXY: the URL of a proxy server, see WithProxy.
+//   - CA_BUNDLE: the path to a PEM file of CA certificates used to verify servers, see WithCABundle.
+//   - AUTHORIZATION: the value of the Authorization header, see WithAuthorization.
+//   - BEARER_TOKEN: a token sent as "Bearer <token>" in the Authorization header.
+//   - RETRIES: the number of attempts made for each request, see WithRetry.
+//   - FAULTS: faults to inject into requests, e.g. "error=0.05,reset=0.01", see ParseFaults.
+//
+// Unset variables are ignored. Since TIMEOUT, PROXY and CA_BUNDLE modify the HTTP client,
+// the returned options must be applied after WithClient.
+func FromEnv(prefix string) (opts []Opt, err error) {
+	get := func(name string) string {
+		return os.Getenv(prefix + "_" + name)
+	}
+	if v := get("BASE_URL"); v != "" {
+		opts = append(opts, WithBaseURL(v))
+	}
+	if v := get("TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s_TIMEOUT: %w", prefix, err)
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+	if v := get("PROXY"); v != "" {
+		proxyURL, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s_PROXY: %w", prefix, err)
+		}
+		opts = append(opts, WithProxy(proxyURL))
+	}
+	if v := get("CA_BUNDLE"); v != "" {
+		opts = append(opts, WithCABundle(v))
+	}
+	authorization, token := get("AUTHORIZATION"), get("BEARER_TOKEN")
+	if authorization != "" && token != "" {
+		return nil, fmt.Errorf("only one of %s_AUTHORIZATION and %s_BEARER_TOKEN can be set", prefix, prefix)
+	}
+	if authorization != "" {
+		opts = append(opts, WithAuthorization(authorization))
+	}
+	if token != "" {
+		opts = append(opts, WithAuthorization("Bearer "+token))
+	}
+	if v := get("RETRIES"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s_RETRIES: %w", prefix, err)
+		}
+		opts = append(opts, WithRetry(attempts, 100*time.Millisecond))
+	}
+	if v := get("FAULTS"); v != "" {
+		faults, err := ParseFaults(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s_FAULTS: %w", prefix, err)
+		}
+		opts = append(opts, WithFaults(faults))
+	}
+	return opts, nil
+}
@@ -0,0 +1,89 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("the CA bundle and authorization are used", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				respond.WithError(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		}))
+		defer s.Close()
+
+		caBundle := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}), 0o600); err != nil {
+			t.Fatalf("failed to write CA bundle: %v", err)
+		}
+		t.Setenv("TEST_API_CA_BUNDLE", caBundle)
+		t.Setenv("TEST_API_BEARER_TOKEN", "abc")
+		t.Setenv("TEST_API_TIMEOUT", "5s")
+
+		opts, err := jsonapi.FromEnv("TEST_API")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		resp, ok, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !ok {
+			t.Error("expected ok")
+		}
+		if diff := cmp.Diff(expectedItemsGetResponse, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("relative URLs are resolved against the base URL", func(t *testing.T) {
+		t.Setenv("TEST_API_BASE_URL", "https://example.com/items")
+		opts, err := jsonapi.FromEnv("TEST_API")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_, ok, err := jsonapi.Get[itemsGetResponse](context.Background(), "/get/ok", append(opts, jsonapi.WithClient(testClient{Handler: createTestRoutes()}))...)
+		if err != nil || !ok {
+			t.Fatalf("expected ok, got %v, %v", ok, err)
+		}
+	})
+	t.Run("requests are sent through the proxy", func(t *testing.T) {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		}))
+		defer proxy.Close()
+		t.Setenv("TEST_API_PROXY", proxy.URL)
+
+		opts, err := jsonapi.FromEnv("TEST_API")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_, _, err = jsonapi.Get[itemsGetResponse](context.Background(), "http://api.example.com/items", opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if proxied != "http://api.example.com/items" {
+			t.Errorf("expected the request to be proxied, got %q", proxied)
+		}
+	})
+	t.Run("invalid values return an error", func(t *testing.T) {
+		t.Setenv("TEST_API_TIMEOUT", "soon")
+		if _, err := jsonapi.FromEnv("TEST_API"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
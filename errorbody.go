@@ -0,0 +1,38 @@
+package jsonapi
+
+import (
+	"io"
+)
+
+// WithMaxErrorBodyBytes limits the size of the response body included in errors, such as
+// InvalidStatusError, to n bytes. Truncated bodies end with " [truncated]". Without a limit,
+// the whole body is included.
+//
+// Bodies are redacted before they're truncated, so when WithRedactor is used, the whole body
+// is read. Otherwise, only the first n bytes of a non-success response are read.
+func WithMaxErrorBodyBytes(n int) Opt {
+	return func(c *Config) error {
+		c.MaxErrorBodyBytes = n
+		return nil
+	}
+}
+
+const truncatedMarker = " [truncated]"
+
+// readErrorBody reads the body of a non-success response for inclusion in an error.
+func (config *Config) readErrorBody(r io.Reader) string {
+	if config.MaxErrorBodyBytes > 0 && config.Redactor == nil {
+		r = io.LimitReader(r, int64(config.MaxErrorBodyBytes)+1)
+	}
+	body, _ := io.ReadAll(r)
+	return config.errorBody(body)
+}
+
+// errorBody redacts and truncates a response body for inclusion in an error.
+func (config *Config) errorBody(body []byte) string {
+	s := config.redactBody(body)
+	if config.MaxErrorBodyBytes > 0 && len(s) > config.MaxErrorBodyBytes {
+		return s[:config.MaxErrorBodyBytes] + truncatedMarker
+	}
+	return s
+}
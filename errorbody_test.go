@@ -0,0 +1,56 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithMaxErrorBodyBytes(t *testing.T) {
+	page := "<html>" + strings.Repeat("x", 10000) + "</html>"
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(page))
+	})
+	tests := []struct {
+		name     string
+		opts     []jsonapi.Opt
+		expected string
+	}{
+		{
+			name:     "the whole body is included by default",
+			expected: page,
+		},
+		{
+			name:     "long bodies are truncated",
+			opts:     []jsonapi.Opt{jsonapi.WithMaxErrorBodyBytes(10)},
+			expected: "<html>xxxx [truncated]",
+		},
+		{
+			name:     "bodies are redacted before they're truncated",
+			opts:     []jsonapi.Opt{jsonapi.WithMaxErrorBodyBytes(10), jsonapi.WithRedactor(jsonapi.NewRedactor())},
+			expected: "<html>xxxx [truncated]",
+		},
+		{
+			name:     "short bodies are not truncated",
+			opts:     []jsonapi.Opt{jsonapi.WithMaxErrorBodyBytes(len(page))},
+			expected: page,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h})}, tt.opts...)
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+			ise, ok := err.(jsonapi.InvalidStatusError)
+			if !ok {
+				t.Fatalf("expected InvalidStatusError, got %v", err)
+			}
+			if ise.Body != tt.expected {
+				t.Errorf("expected body %q, got %q", tt.expected, ise.Body)
+			}
+		})
+	}
+}
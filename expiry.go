@@ -0,0 +1,29 @@
+package jsonapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+)
+
+// WithCertificateExpiry calls warn during each TLS handshake where the server's leaf
+// certificate expires within the given duration, so that operators can be warned before
+// an upstream certificate expires. Use a duration of zero to call warn for every handshake,
+// e.g. to record the expiry as a metric.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithCertificateExpiry(within time.Duration, warn func(serverName string, leaf *x509.Certificate)) Opt {
+	return withTransport(func(t *http.Transport) error {
+		addVerifyConnection(t.TLSClientConfig, func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return nil
+			}
+			leaf := cs.PeerCertificates[0]
+			if within > 0 && time.Until(leaf.NotAfter) > within {
+				return nil
+			}
+			return safely(func() error { warn(cs.ServerName, leaf); return nil })
+		})
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package jsonapi_test
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithCertificateExpiry(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+
+	tests := []struct {
+		name     string
+		within   time.Duration
+		expected bool
+	}{
+		{name: "certificates expiring later are ignored", within: time.Hour, expected: false},
+		{name: "certificates expiring within the duration are reported", within: 200 * 365 * 24 * time.Hour, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warned bool
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+				jsonapi.WithRootCAs(pool),
+				jsonapi.WithCertificateExpiry(tt.within, func(serverName string, leaf *x509.Certificate) {
+					warned = leaf.NotAfter.Equal(s.Certificate().NotAfter)
+				}))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if warned != tt.expected {
+				t.Errorf("expected warned=%v, got %v", tt.expected, warned)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package jsonapi
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Faults configures the faults injected by WithFaults. Each probability is between 0 and 1.
+type Faults struct {
+	// LatencyProbability is the probability that Latency is added before the request is sent.
+	LatencyProbability float64
+	Latency            time.Duration
+	// ErrorProbability is the probability that a 500 response is returned without sending the request.
+	ErrorProbability float64
+	// ResetProbability is the probability that a connection reset error is returned without
+	// sending the request.
+	ResetProbability float64
+	// MalformedProbability is the probability that the response body is replaced with invalid JSON.
+	MalformedProbability float64
+	// Rand returns a random number in [0, 1). Defaults to math/rand/v2.Float64.
+	Rand func() float64
+}
+
+// ParseFaults parses a comma separated list of faults, e.g.
+// "latency=0.1:200ms,error=0.05,reset=0.01,malformed=0.01", where each value is the
+// probability of the fault. The latency fault includes the delay after a colon.
+func ParseFaults(s string) (f Faults, err error) {
+	for _, field := range strings.Split(s, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		if name == "latency" {
+			var latency string
+			value, latency, _ = strings.Cut(value, ":")
+			if f.Latency, err = time.ParseDuration(latency); err != nil {
+				return f, fmt.Errorf("failed to parse latency fault %q: %w", field, err)
+			}
+		}
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil || p < 0 || p > 1 {
+			return f, fmt.Errorf("failed to parse fault %q: probability must be between 0 and 1", field)
+		}
+		switch name {
+		case "latency":
+			f.LatencyProbability = p
+		case "error":
+			f.ErrorProbability = p
+		case "reset":
+			f.ResetProbability = p
+		case "malformed":
+			f.MalformedProbability = p
+		default:
+			return f, fmt.Errorf("failed to parse fault %q: unknown fault %q", field, name)
+		}
+	}
+	return f, nil
+}
+
+// WithFaults randomly delays requests, returns 500 responses or connection resets, and
+// corrupts response bodies, so that the resilience of services built on the client can
+// be tested, e.g. in a staging environment. Like other interceptors, faults are injected
+// outside of retries, so a fault fails the whole call rather than a single attempt.
+// See also FromEnv, which reads faults from an environment variable.
+func WithFaults(f Faults) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return f.intercept(c, next)
+		})
+		return nil
+	}
+}
+
+func (f Faults) intercept(c *Config, next Doer) Doer {
+	random := f.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if f.LatencyProbability > 0 && random() < f.LatencyProbability {
+			if err := c.clock().Sleep(req.Context(), f.Latency); err != nil {
+				return nil, err
+			}
+		}
+		if f.ResetProbability > 0 && random() < f.ResetProbability {
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		}
+		if f.ErrorProbability > 0 && random() < f.ErrorProbability {
+			body := `{"message":"injected fault","statusCode":500}`
+			return &http.Response{
+				Status:        "500 Internal Server Error",
+				StatusCode:    http.StatusInternalServerError,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{"Content-Type": []string{"application/json"}},
+				Body:          io.NopCloser(strings.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		}
+		res, err := next.Do(req)
+		if err != nil {
+			return res, err
+		}
+		if f.MalformedProbability > 0 && random() < f.MalformedProbability {
+			res.Body.Close()
+			res.Body = io.NopCloser(strings.NewReader(`{"malformed":`))
+			res.ContentLength = -1
+			res.Header.Del("Content-Length")
+		}
+		return res, nil
+	})
+}
@@ -0,0 +1,83 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseFaults(t *testing.T) {
+	f, err := jsonapi.ParseFaults("latency=0.1:200ms, error=0.05,reset=0.01,malformed=1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := jsonapi.Faults{
+		LatencyProbability:   0.1,
+		Latency:              200 * time.Millisecond,
+		ErrorProbability:     0.05,
+		ResetProbability:     0.01,
+		MalformedProbability: 1,
+	}
+	if diff := cmp.Diff(expected, f, cmpopts.IgnoreFields(jsonapi.Faults{}, "Rand")); diff != "" {
+		t.Error(diff)
+	}
+	for _, s := range []string{"error=2", "latency=0.1", "timeout=0.1", "error"} {
+		if _, err := jsonapi.ParseFaults(s); err == nil {
+			t.Errorf("%q: expected an error, got nil", s)
+		}
+	}
+}
+
+func TestWithFaults(t *testing.T) {
+	client := jsonapi.WithClient(testClient{Handler: createTestRoutes()})
+	always := func() float64 { return 0 }
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithFaults(jsonapi.Faults{ErrorProbability: 1, Rand: always}))
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusInternalServerError {
+			t.Errorf("expected a 500 InvalidStatusError, got %v", err)
+		}
+	})
+	t.Run("connection resets", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithFaults(jsonapi.Faults{ResetProbability: 1, Rand: always}))
+		if kind := jsonapi.ClassifyTransportError(err); kind != jsonapi.TransportErrorConnectionReset {
+			t.Errorf("expected a connection reset, got %v: %v", kind, err)
+		}
+	})
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithFaults(jsonapi.Faults{MalformedProbability: 1, Rand: always}))
+		var ije jsonapi.InvalidJSONError
+		if !errors.As(err, &ije) {
+			t.Errorf("expected InvalidJSONError, got %v", err)
+		}
+	})
+	t.Run("latency", func(t *testing.T) {
+		start := time.Now()
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithFaults(jsonapi.Faults{LatencyProbability: 1, Latency: 20 * time.Millisecond, Rand: always}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("expected latency to be added, took %v", elapsed)
+		}
+	})
+	t.Run("faults can be enabled with an environment variable", func(t *testing.T) {
+		t.Setenv("TEST_API_FAULTS", "error=1")
+		opts, err := jsonapi.FromEnv("TEST_API")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_, _, err = jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", append([]jsonapi.Opt{client}, opts...)...)
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusInternalServerError {
+			t.Errorf("expected a 500 InvalidStatusError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldErrors are validation messages, keyed by field name, e.g. for showing next to the
+// inputs of a form.
+type FieldErrors map[string][]string
+
+// Get returns the first message for the field, or an empty string.
+func (fe FieldErrors) Get(field string) string {
+	if messages := fe[field]; len(messages) > 0 {
+		return messages[0]
+	}
+	return ""
+}
+
+// parseFieldErrors decodes field errors from common validation error bodies:
+//
+//	{"errors": [{"field": "name", "message": "is required"}]}
+//	{"errors": {"name": ["is required"]}}
+//	{"invalid-params": [{"name": "name", "reason": "is required"}]}
+//	{"errors": [{"pointer": "#/name", "detail": "is required"}]}
+//
+// The last two are the problem details (RFC 7807 and RFC 9457) extensions.
+// It returns nil if the body doesn't contain field errors.
+func parseFieldErrors(body string) FieldErrors {
+	var envelope struct {
+		Errors        json.RawMessage `json:"errors"`
+		InvalidParams []struct {
+			Name   string `json:"name"`
+			Reason string `json:"reason"`
+		} `json:"invalid-params"`
+	}
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil
+	}
+	fe := FieldErrors{}
+	for _, p := range envelope.InvalidParams {
+		fe.add(p.Name, p.Reason)
+	}
+	var list []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+		Pointer string `json:"pointer"`
+		Detail  string `json:"detail"`
+	}
+	var byField map[string][]string
+	if json.Unmarshal(envelope.Errors, &list) == nil {
+		for _, e := range list {
+			if e.Field != "" {
+				fe.add(e.Field, e.Message)
+				continue
+			}
+			if e.Pointer != "" {
+				fe.add(strings.Join(jsonPointerTokens(strings.TrimPrefix(e.Pointer, "#")), "."), e.Detail)
+			}
+		}
+	} else if json.Unmarshal(envelope.Errors, &byField) == nil {
+		for field, messages := range byField {
+			for _, message := range messages {
+				fe.add(field, message)
+			}
+		}
+	}
+	if len(fe) == 0 {
+		return nil
+	}
+	return fe
+}
+
+func (fe FieldErrors) add(field, message string) {
+	if field != "" {
+		fe[field] = append(fe[field], message)
+	}
+}
@@ -0,0 +1,80 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFieldErrors(t *testing.T) {
+	post := func(status int, contentType, body string) error {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+		_, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "/users", map[string]any{}, jsonapi.WithClient(testClient{Handler: h}))
+		return err
+	}
+	tests := []struct {
+		name     string
+		body     string
+		expected jsonapi.FieldErrors
+	}{
+		{
+			name:     "errors list",
+			body:     `{"errors":[{"field":"name","message":"is required"},{"field":"name","message":"is too short"},{"field":"age","message":"must be positive"}]}`,
+			expected: jsonapi.FieldErrors{"name": {"is required", "is too short"}, "age": {"must be positive"}},
+		},
+		{
+			name:     "errors map",
+			body:     `{"errors":{"email":["is invalid"]}}`,
+			expected: jsonapi.FieldErrors{"email": {"is invalid"}},
+		},
+		{
+			name:     "problem details invalid-params",
+			body:     `{"type":"https://example.net/validation-error","title":"Your request is not valid.","invalid-params":[{"name":"age","reason":"must be a positive integer"}]}`,
+			expected: jsonapi.FieldErrors{"age": {"must be a positive integer"}},
+		},
+		{
+			name:     "problem details errors with pointers",
+			body:     `{"title":"Your request is not valid.","errors":[{"detail":"must be a positive integer","pointer":"#/address/number"}]}`,
+			expected: jsonapi.FieldErrors{"address.number": {"must be a positive integer"}},
+		},
+		{
+			name: "other bodies",
+			body: `{"message":"invalid request"}`,
+		},
+		{
+			name: "non-JSON bodies",
+			body: `invalid request`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uee jsonapi.UnprocessableEntityError
+			if err := post(http.StatusUnprocessableEntity, "application/problem+json", tt.body); !errors.As(err, &uee) {
+				t.Fatalf("expected UnprocessableEntityError, got %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, uee.FieldErrors); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("400 responses include field errors", func(t *testing.T) {
+		var bre jsonapi.BadRequestError
+		if err := post(http.StatusBadRequest, "application/json", `{"errors":[{"field":"name","message":"is required"}]}`); !errors.As(err, &bre) {
+			t.Fatalf("expected BadRequestError, got %v", err)
+		}
+		if msg := bre.FieldErrors.Get("name"); msg != "is required" {
+			t.Errorf("expected the name error, got %q", msg)
+		}
+		if msg := bre.FieldErrors.Get("age"); msg != "" {
+			t.Errorf("expected no age error, got %q", msg)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package jsonapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type FilterOp string
+
+const (
+	FilterOpEq  FilterOp = "eq"
+	FilterOpNe  FilterOp = "ne"
+	FilterOpGt  FilterOp = "gt"
+	FilterOpGte FilterOp = "gte"
+	FilterOpLt  FilterOp = "lt"
+	FilterOpLte FilterOp = "lte"
+	FilterOpIn  FilterOp = "in"
+)
+
+// Filter is a filter expression, rendered as filter[field]=value for equality, and
+// filter[field][op]=value for other operators. Create filters with FilterEq, FilterIn etc.
+type Filter struct {
+	Field  string
+	Op     FilterOp
+	Values []string
+}
+
+func FilterEq(field string, value any) Filter  { return newFilter(field, FilterOpEq, value) }
+func FilterNe(field string, value any) Filter  { return newFilter(field, FilterOpNe, value) }
+func FilterGt(field string, value any) Filter  { return newFilter(field, FilterOpGt, value) }
+func FilterGte(field string, value any) Filter { return newFilter(field, FilterOpGte, value) }
+func FilterLt(field string, value any) Filter  { return newFilter(field, FilterOpLt, value) }
+func FilterLte(field string, value any) Filter { return newFilter(field, FilterOpLte, value) }
+
+// FilterIn matches any of the values, rendered as filter[field][in]=a,b.
+func FilterIn[T any](field string, values ...T) Filter {
+	f := Filter{Field: field, Op: FilterOpIn}
+	for _, v := range values {
+		f.Values = append(f.Values, formatFilterValue(v))
+	}
+	return f
+}
+
+func newFilter(field string, op FilterOp, value any) Filter {
+	return Filter{Field: field, Op: op, Values: []string{formatFilterValue(value)}}
+}
+
+func formatFilterValue(v any) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+func (f Filter) key() string {
+	if f.Op == FilterOpEq {
+		return "filter[" + f.Field + "]"
+	}
+	return "filter[" + f.Field + "][" + string(f.Op) + "]"
+}
+
+// WithFilter adds filter query parameters to the request.
+func WithFilter(filters ...Filter) Opt {
+	return func(c *Config) error {
+		for _, f := range filters {
+			if err := WithQuery(f.key(), strings.Join(f.Values, ","))(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Sort is a sort field, see Asc and Desc.
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+func Asc(field string) Sort  { return Sort{Field: field} }
+func Desc(field string) Sort { return Sort{Field: field, Descending: true} }
+
+// WithSort adds a sort query parameter to the request, with descending fields prefixed
+// by a minus sign, e.g. WithSort(Asc("name"), Desc("created")) adds sort=name,-created.
+func WithSort(fields ...Sort) Opt {
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		values[i] = f.Field
+		if f.Descending {
+			values[i] = "-" + f.Field
+		}
+	}
+	return WithQuery("sort", strings.Join(values, ","))
+}
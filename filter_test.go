@@ -0,0 +1,40 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterAndSort(t *testing.T) {
+	var query string
+	_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/orders",
+		jsonapi.WithClient(queryCapturingClient(&query)),
+		jsonapi.WithFilter(
+			jsonapi.FilterEq("status", "active"),
+			jsonapi.FilterGte("created", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			jsonapi.FilterIn("region", "eu", "us"),
+		),
+		jsonapi.WithSort(jsonapi.Asc("name"), jsonapi.Desc("created")),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	actual, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	expected := url.Values{
+		"filter[status]":       {"active"},
+		"filter[created][gte]": {"2024-01-01T00:00:00Z"},
+		"filter[region][in]":   {"eu,us"},
+		"sort":                 {"name,-created"},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
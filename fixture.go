@@ -0,0 +1,192 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+)
+
+// NewFixtureWriter creates a FixtureWriter that writes fixtures to dir.
+func NewFixtureWriter(dir string) *FixtureWriter {
+	return &FixtureWriter{
+		Dir:      dir,
+		Redactor: NewRedactor(),
+	}
+}
+
+// FixtureWriter writes successful JSON responses to a directory of fixtures, so that test data
+// can be refreshed from a real API. It's intended for development, not production use.
+//
+// Each response is written to <name>.json, and a Go file named fixtures.go is generated that
+// embeds the fixtures and provides a Load function and a constant for each fixture name.
+type FixtureWriter struct {
+	Dir string
+	// Package is the package name of the generated loader. Defaults to the base name of Dir.
+	Package string
+	// Redactor scrubs response bodies before they're written. Set to nil to write bodies unchanged.
+	Redactor *Redactor
+	// Name returns the fixture name for a request. Defaults to the method and URL path,
+	// e.g. get_items_123 for GET /items/123.
+	Name func(req *http.Request) string
+
+	m sync.Mutex
+}
+
+// WithFixtures writes successful JSON responses to fixture files using w.
+func WithFixtures(w *FixtureWriter) Opt {
+	return func(c *Config) error {
+		if err := os.MkdirAll(w.Dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create fixtures directory: %w", err)
+		}
+		c.Interceptors = append(c.Interceptors, w.intercept)
+		return nil
+	}
+}
+
+func (w *FixtureWriter) intercept(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		res, err := next.Do(req)
+		if err != nil || res.StatusCode < 200 || res.StatusCode > 299 || !isJSONContentType(res.Header.Get("Content-Type")) {
+			return res, err
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		if err = w.Write(w.name(req), body); err != nil {
+			res.Body.Close()
+			return nil, err
+		}
+		return res, nil
+	})
+}
+
+func (w *FixtureWriter) name(req *http.Request) string {
+	if w.Name != nil {
+		return w.Name(req)
+	}
+	return fixtureName(req.Method + "/" + req.URL.Path)
+}
+
+// fixtureName converts s to a lower case name containing only letters, digits and underscores.
+func fixtureName(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteRune('_')
+	}
+	return strings.Join(strings.FieldsFunc(sb.String(), func(r rune) bool { return r == '_' }), "_")
+}
+
+// Write writes the body to the named fixture, and regenerates the loader.
+func (w *FixtureWriter) Write(name string, body []byte) error {
+	name = fixtureName(name)
+	if name == "" {
+		return fmt.Errorf("failed to write fixture: empty name")
+	}
+	if w.Redactor != nil {
+		body = w.Redactor.Body(body)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return fmt.Errorf("failed to format fixture %q: %w", name, err)
+	}
+	indented.WriteByte('\n')
+	w.m.Lock()
+	defer w.m.Unlock()
+	if err := os.WriteFile(filepath.Join(w.Dir, name+".json"), indented.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture %q: %w", name, err)
+	}
+	return w.writeLoader()
+}
+
+func (w *FixtureWriter) writeLoader() error {
+	files, err := filepath.Glob(filepath.Join(w.Dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Strings(names)
+	pkg := w.Package
+	if pkg == "" {
+		abs, err := filepath.Abs(w.Dir)
+		if err != nil {
+			return fmt.Errorf("failed to get fixtures directory: %w", err)
+		}
+		pkg = fixtureName(filepath.Base(abs))
+	}
+	var buf bytes.Buffer
+	if err = fixtureLoader.Execute(&buf, map[string]any{"Package": pkg, "Names": names}); err != nil {
+		return fmt.Errorf("failed to generate fixture loader: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format fixture loader: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(w.Dir, "fixtures.go"), src, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture loader: %w", err)
+	}
+	return nil
+}
+
+var fixtureLoader = template.Must(template.New("fixtures").Funcs(template.FuncMap{
+	"constant": func(name string) string {
+		var sb strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+		if s := sb.String(); unicode.IsDigit(rune(s[0])) {
+			return "Fixture" + s
+		}
+		return sb.String()
+	},
+}).Parse(`// Code generated by jsonapi.FixtureWriter. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed *.json
+var files embed.FS
+
+// Fixture names.
+const (
+{{- range .Names }}
+	{{ constant . }} = {{ printf "%q" . }}
+{{- end }}
+)
+
+// Load decodes the named fixture into v.
+func Load(name string, v any) error {
+	b, err := files.ReadFile(name + ".json")
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %q: %w", name, err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to decode fixture %q: %w", name, err)
+	}
+	return nil
+}
+`))
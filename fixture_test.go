@@ -0,0 +1,63 @@
+package jsonapi_test
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithFixtures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, map[string]any{"id": r.PathValue("id"), "token": "abc"}, http.StatusOK)
+	})
+	mux.HandleFunc("GET /missing", func(w http.ResponseWriter, r *http.Request) {
+		respond.WithError(w, "Not found", http.StatusNotFound)
+	})
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: mux}),
+		jsonapi.WithFixtures(jsonapi.NewFixtureWriter(dir)),
+	}
+
+	resp, _, err := jsonapi.Get[map[string]any](context.Background(), "/users/123", opts...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp["token"] != "abc" {
+		t.Errorf("expected the response not to be redacted, got %v", resp)
+	}
+	if _, _, err = jsonapi.Get[map[string]any](context.Background(), "/missing", opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(dir, "get_users_123.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if strings.Contains(string(fixture), "abc") || !strings.Contains(string(fixture), `"[REDACTED]"`) {
+		t.Errorf("expected the fixture to be redacted, got %s", fixture)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "get_missing.json")); !os.IsNotExist(err) {
+		t.Errorf("expected unsuccessful responses not to be written, got %v", err)
+	}
+
+	loader, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "fixtures.go"), nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse loader: %v", err)
+	}
+	if loader.Name.Name != "fixtures" {
+		t.Errorf("expected package fixtures, got %q", loader.Name.Name)
+	}
+	if loader.Scope.Lookup("GetUsers123") == nil || loader.Scope.Lookup("Load") == nil {
+		t.Error("expected the loader to declare GetUsers123 and Load")
+	}
+}
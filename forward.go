@@ -0,0 +1,58 @@
+package jsonapi
+
+import (
+	"context"
+	"net/http"
+)
+
+type inboundRequestContextKey struct{}
+
+// ContextWithInboundRequest returns a context that carries the inbound request being handled
+// by a server, so that values such as the Authorization header can be forwarded on outgoing
+// calls, see WithForwardAuthorization.
+func ContextWithInboundRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, inboundRequestContextKey{}, r)
+}
+
+// InboundRequest returns the request set by ContextWithInboundRequest, or nil if none was set.
+func InboundRequest(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(inboundRequestContextKey{}).(*http.Request)
+	return r
+}
+
+// InboundRequestHandler adds each request to its own context using ContextWithInboundRequest,
+// so that handlers can pass r.Context() to outgoing calls made with WithForwardAuthorization.
+func InboundRequestHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ContextWithInboundRequest(r.Context(), r)))
+	})
+}
+
+// WithForwardAuthorization sets the Authorization header of outgoing requests to the
+// Authorization header of the inbound request in the request context, so that a gateway or
+// backend for frontend can make calls on behalf of the caller. It replaces any Authorization
+// header set by earlier middleware. If the context has no inbound request, or the inbound
+// request has no Authorization header, the header is not modified.
+func WithForwardAuthorization() Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, forwardAuthorizationMiddleware{})
+		return nil
+	}
+}
+
+type forwardAuthorizationMiddleware struct{}
+
+func (forwardAuthorizationMiddleware) Request(req *http.Request) error {
+	inbound := InboundRequest(req.Context())
+	if inbound == nil {
+		return nil
+	}
+	if authorization := inbound.Header.Get("Authorization"); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return nil
+}
+
+func (forwardAuthorizationMiddleware) Response(res *http.Response) error {
+	return nil
+}
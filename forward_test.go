@@ -0,0 +1,38 @@
+package jsonapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithForwardAuthorization(t *testing.T) {
+	upstream := jsonapi.WithClient(testClient{Handler: createTestRoutes()})
+	gateway := jsonapi.InboundRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok, err := jsonapi.Get[itemsGetResponse](r.Context(), "/auth/items/get/ok", upstream, jsonapi.WithAuthorization("Bearer service"), jsonapi.WithForwardAuthorization())
+		if err != nil || !ok {
+			http.Error(w, "upstream call failed", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("the inbound Authorization header is forwarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer abc")
+		gateway.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+	t.Run("without an inbound Authorization header, the header is unchanged", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusBadGateway {
+			t.Errorf("expected status 502, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+// Package gen generates Go interfaces for JSON APIs, with implementations backed by the
+// github.com/a-h/jsonapi package. Business code can depend on the interface, and tests can
+// use mocks generated by tools such as moq or gomock.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Spec describes a service and its operations.
+type Spec struct {
+	// Package is the name of the generated Go package.
+	Package string `json:"package"`
+	// Service is the name of the service, e.g. "Orders" generates an OrdersAPI interface and OrdersClient.
+	Service    string      `json:"service"`
+	Operations []Operation `json:"operations"`
+}
+
+type Operation struct {
+	// Name is the name of the generated method, e.g. "GetOrder".
+	Name string `json:"name"`
+	// Method is the HTTP method, GET, POST, PUT, PATCH or DELETE.
+	Method string `json:"method"`
+	// Path is the path of the endpoint relative to the base URL. Path parameters are
+	// enclosed in braces, e.g. "/orders/{id}", and become string arguments of the method.
+	// Parameters that are Go keywords, predeclared identifiers, or clash with the names used
+	// by the generated code, such as "url" or "ctx", have "Param" appended, e.g. "typeParam".
+	Path string `json:"path"`
+	// Request is the Go type of the request body, required for POST, PUT and PATCH.
+	Request string `json:"request,omitempty"`
+	// Response is the Go type of the response body. It's optional for DELETE, in which case
+	// the method only returns an error.
+	Response string `json:"response,omitempty"`
+}
+
+// ReadSpec reads a JSON spec.
+func ReadSpec(r io.Reader) (spec Spec, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&spec); err != nil {
+		return spec, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	return spec, nil
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// reservedNames are used by the generated code, so can't be used as parameter names.
+var reservedNames = map[string]bool{
+	"c": true, "ctx": true, "opts": true, "callOpts": true, "request": true, "response": true,
+	"ok": true, "err": true, "url": true, "context": true, "jsonapi": true,
+}
+
+// paramIdent returns the Go identifier used for a path parameter.
+func paramIdent(name string) string {
+	if reservedNames[name] || token.IsKeyword(name) || types.Universe.Lookup(name) != nil {
+		return name + "Param"
+	}
+	return name
+}
+
+type operationData struct {
+	Operation
+	// Params are the Go identifiers of the path parameters.
+	Params []string
+	// PathExpr is a Go expression that builds the path.
+	PathExpr string
+}
+
+func (o operationData) Args() string {
+	var args []string
+	for _, p := range o.Params {
+		args = append(args, p+" string")
+	}
+	if o.Request != "" {
+		args = append(args, "request "+o.Request)
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+func (o operationData) Results() string {
+	switch {
+	case o.Method == http.MethodGet:
+		return fmt.Sprintf("(response %s, ok bool, err error)", o.Response)
+	case o.Response == "":
+		return "(err error)"
+	}
+	return fmt.Sprintf("(response %s, err error)", o.Response)
+}
+
+// Body returns the statements that make the call.
+func (o operationData) Body() string {
+	url := "c.BaseURL+" + o.PathExpr
+	switch o.Method {
+	case http.MethodGet:
+		return fmt.Sprintf("return jsonapi.Get[%s](ctx, %s, opts...)", o.Response, url)
+	case http.MethodPost:
+		return fmt.Sprintf("return jsonapi.Post[%s, %s](ctx, %s, request, opts...)", o.Request, o.Response, url)
+	case http.MethodPut:
+		return fmt.Sprintf("return jsonapi.Put[%s, %s](ctx, %s, request, opts...)", o.Request, o.Response, url)
+	case http.MethodPatch:
+		return fmt.Sprintf("return jsonapi.Patch[%s, %s](ctx, %s, request, opts...)", o.Request, o.Response, url)
+	case http.MethodDelete:
+		if o.Response == "" {
+			return fmt.Sprintf("_, err = jsonapi.Delete[struct{}](ctx, %s, opts...)\nreturn err", url)
+		}
+		return fmt.Sprintf("return jsonapi.Delete[%s](ctx, %s, opts...)", o.Response, url)
+	}
+	return ""
+}
+
+func newOperationData(o Operation) (od operationData, err error) {
+	od.Operation = o
+	od.Method = strings.ToUpper(o.Method)
+	if !token.IsIdentifier(o.Name) || !token.IsExported(o.Name) {
+		return od, fmt.Errorf("operation %q: name must be an exported Go identifier", o.Name)
+	}
+	switch od.Method {
+	case http.MethodGet, http.MethodDelete:
+		if o.Request != "" {
+			return od, fmt.Errorf("operation %q: %s operations can't have a request body", o.Name, od.Method)
+		}
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if o.Request == "" {
+			return od, fmt.Errorf("operation %q: %s operations require a request type", o.Name, od.Method)
+		}
+	default:
+		return od, fmt.Errorf("operation %q: unsupported method %q", o.Name, o.Method)
+	}
+	if o.Response == "" && od.Method != http.MethodDelete {
+		return od, fmt.Errorf("operation %q: missing response type", o.Name)
+	}
+	var parts []string
+	var last int
+	seen := map[string]bool{}
+	for _, m := range pathParamPattern.FindAllStringSubmatchIndex(o.Path, -1) {
+		param := paramIdent(o.Path[m[2]:m[3]])
+		if seen[param] {
+			return od, fmt.Errorf("operation %q: duplicate path parameter %q", o.Name, param)
+		}
+		seen[param] = true
+		od.Params = append(od.Params, param)
+		if m[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", o.Path[last:m[0]]))
+		}
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", param))
+		last = m[1]
+	}
+	if last < len(o.Path) || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", o.Path[last:]))
+	}
+	od.PathExpr = strings.Join(parts, "+")
+	return od, nil
+}
+
+var tmpl = template.Must(template.New("api").Parse(`// Code generated by jsonapi-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+	{{- if .UsesURL }}
+	"net/url"
+	{{- end }}
+
+	"github.com/a-h/jsonapi"
+)
+
+// {{ .Service }}API is the interface of the {{ .Service }} service.
+type {{ .Service }}API interface {
+{{- range .Operations }}
+	{{ .Name }}(ctx context.Context{{ .Args }}, opts ...jsonapi.Opt) {{ .Results }}
+{{- end }}
+}
+
+// New{{ .Service }}Client creates a {{ .Service }}API client for the base URL.
+// The options are applied to every call.
+func New{{ .Service }}Client(baseURL string, opts ...jsonapi.Opt) *{{ .Service }}Client {
+	return &{{ .Service }}Client{
+		BaseURL: baseURL,
+		Opts:    opts,
+	}
+}
+
+// {{ .Service }}Client implements {{ .Service }}API using the jsonapi package.
+type {{ .Service }}Client struct {
+	BaseURL string
+	Opts    []jsonapi.Opt
+}
+
+var _ {{ .Service }}API = (*{{ .Service }}Client)(nil)
+{{ range .Operations }}
+func (c *{{ $.Service }}Client) {{ .Name }}(ctx context.Context{{ .Args }}, callOpts ...jsonapi.Opt) {{ .Results }} {
+	opts := append(c.Opts[:len(c.Opts):len(c.Opts)], callOpts...)
+	{{ .Body }}
+}
+{{ end }}`))
+
+// Generate Go source code for the spec.
+func Generate(spec Spec) ([]byte, error) {
+	if spec.Package == "" || spec.Service == "" {
+		return nil, fmt.Errorf("package and service are required")
+	}
+	if !token.IsIdentifier(spec.Package) {
+		return nil, fmt.Errorf("package %q must be a Go identifier", spec.Package)
+	}
+	if !token.IsIdentifier(spec.Service) || !token.IsExported(spec.Service) {
+		return nil, fmt.Errorf("service %q must be an exported Go identifier", spec.Service)
+	}
+	data := struct {
+		Package    string
+		Service    string
+		UsesURL    bool
+		Operations []operationData
+	}{
+		Package: spec.Package,
+		Service: spec.Service,
+	}
+	for _, o := range spec.Operations {
+		od, err := newOperationData(o)
+		if err != nil {
+			return nil, err
+		}
+		data.UsesURL = data.UsesURL || len(od.Params) > 0
+		data.Operations = append(data.Operations, od)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
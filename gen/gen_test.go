@@ -0,0 +1,131 @@
+package gen_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi/gen"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+const ordersSpec = `{
+	"package": "orders",
+	"service": "Orders",
+	"operations": [
+		{"name": "GetOrder", "method": "GET", "path": "/customers/{customerID}/orders/{id}", "response": "Order"},
+		{"name": "CreateOrder", "method": "POST", "path": "/orders", "request": "CreateOrderRequest", "response": "Order"}
+	]
+}`
+
+func TestGenerate(t *testing.T) {
+	spec, err := gen.ReadSpec(strings.NewReader(ordersSpec))
+	if err != nil {
+		t.Fatalf("failed to read spec: %v", err)
+	}
+	src, err := gen.Generate(spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, expected := range []string{
+		"type OrdersAPI interface {",
+		"GetOrder(ctx context.Context, customerID string, id string, opts ...jsonapi.Opt) (response Order, ok bool, err error)",
+		"CreateOrder(ctx context.Context, request CreateOrderRequest, opts ...jsonapi.Opt) (response Order, err error)",
+		`jsonapi.Get[Order](ctx, c.BaseURL+"/customers/"+url.PathEscape(customerID)+"/orders/"+url.PathEscape(id), opts...)`,
+		`jsonapi.Post[CreateOrderRequest, Order](ctx, c.BaseURL+"/orders", request, opts...)`,
+		"var _ OrdersAPI = (*OrdersClient)(nil)",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected generated code to contain %q\n%s", expected, src)
+		}
+	}
+}
+
+func TestGenerateGolden(t *testing.T) {
+	specs, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatalf("failed to list specs: %v", err)
+	}
+	for _, specPath := range specs {
+		t.Run(filepath.Base(specPath), func(t *testing.T) {
+			data, err := os.ReadFile(specPath)
+			if err != nil {
+				t.Fatalf("failed to read spec: %v", err)
+			}
+			spec, err := gen.ReadSpec(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to read spec: %v", err)
+			}
+			src, err := gen.Generate(spec)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			goldenPath := strings.TrimSuffix(specPath, ".json") + ".go.golden"
+			if *update {
+				if err = os.WriteFile(goldenPath, src, 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			expected, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if diff := cmp.Diff(string(expected), string(src)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation gen.Operation
+	}{
+		{
+			name:      "POST without a request type",
+			operation: gen.Operation{Name: "CreateOrder", Method: "POST", Path: "/orders", Response: "Order"},
+		},
+		{
+			name:      "PATCH without a request type",
+			operation: gen.Operation{Name: "UpdateOrder", Method: "PATCH", Path: "/orders/{id}", Response: "Order"},
+		},
+		{
+			name:      "DELETE with a request type",
+			operation: gen.Operation{Name: "DeleteOrder", Method: "DELETE", Path: "/orders/{id}", Request: "Order"},
+		},
+		{
+			name:      "unsupported method",
+			operation: gen.Operation{Name: "TraceOrder", Method: "TRACE", Path: "/orders", Response: "Order"},
+		},
+		{
+			name:      "unexported name",
+			operation: gen.Operation{Name: "getOrder", Method: "GET", Path: "/orders", Response: "Order"},
+		},
+		{
+			name:      "invalid name",
+			operation: gen.Operation{Name: "Get-Order", Method: "GET", Path: "/orders", Response: "Order"},
+		},
+		{
+			name:      "duplicate path parameters",
+			operation: gen.Operation{Name: "GetOrder", Method: "GET", Path: "/orders/{type}/{typeParam}", Response: "Order"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gen.Generate(gen.Spec{
+				Package:    "orders",
+				Service:    "Orders",
+				Operations: []gen.Operation{tt.operation},
+			})
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/a-h/jsonapi
 
-go 1.22.5
+go 1.23
 
 require (
 	github.com/a-h/respond v0.0.2
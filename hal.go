@@ -0,0 +1,94 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// HAL can be embedded in response types to decode HAL hypermedia _links and _embedded resources.
+type HAL struct {
+	Links    HALLinks                   `json:"_links,omitempty"`
+	Embedded map[string]json.RawMessage `json:"_embedded,omitempty"`
+}
+
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// HALLinks maps link relations to links. HAL allows a relation to be a single link
+// or an array of links, both are decoded into a slice.
+type HALLinks map[string][]HALLink
+
+func (l *HALLinks) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*l = make(HALLinks, len(raw))
+	for rel, value := range raw {
+		if strings.HasPrefix(strings.TrimSpace(string(value)), "[") {
+			var links []HALLink
+			if err := json.Unmarshal(value, &links); err != nil {
+				return fmt.Errorf("failed to unmarshal %q links: %w", rel, err)
+			}
+			(*l)[rel] = links
+			continue
+		}
+		var link HALLink
+		if err := json.Unmarshal(value, &link); err != nil {
+			return fmt.Errorf("failed to unmarshal %q link: %w", rel, err)
+		}
+		(*l)[rel] = []HALLink{link}
+	}
+	return nil
+}
+
+// Link returns the first link with the given relation.
+func (h HAL) Link(rel string) (link HALLink, ok bool) {
+	links := h.Links[rel]
+	if len(links) == 0 {
+		return link, false
+	}
+	return links[0], true
+}
+
+// Embedded decodes the embedded resource with the given relation.
+func Embedded[T any](h HAL, rel string) (v T, ok bool, err error) {
+	raw, ok := h.Embedded[rel]
+	if !ok {
+		return v, false, nil
+	}
+	if err = json.Unmarshal(raw, &v); err != nil {
+		return v, false, fmt.Errorf("failed to unmarshal embedded %q resource: %w", rel, err)
+	}
+	return v, true, nil
+}
+
+// Follow resolves the link with the given relation against base, which is usually the URL
+// of the resource that contained the link, and gets it using the same options as Get.
+// Returns ok=false if the response was a 404.
+func Follow[TResp any](ctx context.Context, base string, h HAL, rel string, opts ...Opt) (response TResp, ok bool, err error) {
+	link, ok := h.Link(rel)
+	if !ok {
+		return response, false, fmt.Errorf("link relation %q not found", rel)
+	}
+	if link.Templated {
+		return response, false, fmt.Errorf("link relation %q is templated and must be expanded before it is followed", rel)
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return response, false, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	href, err := url.Parse(link.Href)
+	if err != nil {
+		return response, false, fmt.Errorf("failed to parse %q link: %w", rel, err)
+	}
+	return Get[TResp](ctx, baseURL.ResolveReference(href).String(), opts...)
+}
@@ -0,0 +1,72 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type halOrder struct {
+	jsonapi.HAL
+	ID string `json:"id"`
+}
+
+type halCustomer struct {
+	Name string `json:"name"`
+}
+
+func TestHAL(t *testing.T) {
+	routes := http.NewServeMux()
+	routes.HandleFunc("/orders/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "1",
+			"_links": {
+				"self": {"href": "/orders/1"},
+				"customer": {"href": "../customers/2"},
+				"items": [{"href": "/items/1"}, {"href": "/items/2"}]
+			},
+			"_embedded": {
+				"customer": {"name": "Alice"}
+			}
+		}`))
+	})
+	routes.HandleFunc("/customers/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Alice"}`))
+	})
+	ctx := context.Background()
+	opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: routes})}
+
+	order, _, err := jsonapi.Get[halOrder](ctx, "http://example.com/orders/1", opts...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(order.Links["items"]) != 2 {
+		t.Errorf("expected 2 item links, got %d", len(order.Links["items"]))
+	}
+	t.Run("embedded resources can be decoded", func(t *testing.T) {
+		customer, ok, err := jsonapi.Embedded[halCustomer](order.HAL, "customer")
+		if err != nil || !ok {
+			t.Fatalf("expected the embedded customer, got %v, %v", ok, err)
+		}
+		if customer.Name != "Alice" {
+			t.Errorf("expected Alice, got %q", customer.Name)
+		}
+	})
+	t.Run("links can be followed", func(t *testing.T) {
+		customer, ok, err := jsonapi.Follow[halCustomer](ctx, "http://example.com/orders/1", order.HAL, "customer", opts...)
+		if err != nil || !ok {
+			t.Fatalf("expected the customer, got %v, %v", ok, err)
+		}
+		if customer.Name != "Alice" {
+			t.Errorf("expected Alice, got %q", customer.Name)
+		}
+	})
+	t.Run("missing links are errors", func(t *testing.T) {
+		_, _, err := jsonapi.Follow[halCustomer](ctx, "http://example.com/orders/1", order.HAL, "missing", opts...)
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
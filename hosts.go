@@ -0,0 +1,89 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ForbiddenHostError is returned when a request, or a redirect, targets a host that isn't allowed.
+type ForbiddenHostError struct {
+	Host string `json:"host"`
+	// IP is the address that the host resolved to, if the host was rejected because of its address.
+	IP string `json:"ip,omitempty"`
+}
+
+func (e ForbiddenHostError) Error() string {
+	if e.IP != "" {
+		return fmt.Sprintf("requests to host %q at private address %s are not allowed", e.Host, e.IP)
+	}
+	return fmt.Sprintf("requests to host %q are not allowed", e.Host)
+}
+
+// WithAllowedHosts rejects requests to hosts that don't match any of the patterns with a
+// ForbiddenHostError, before a connection is made, so that a client that builds URLs from
+// user input can't be used to reach internal endpoints, such as cloud metadata services.
+// Redirect targets are also checked.
+//
+// Patterns are host names, e.g. "api.example.com", or "*.example.com" to match any subdomain.
+// Matching ignores case and port.
+//
+// The Opt returns an error if the underlying Doer is not an *http.Client with an *http.Transport,
+// and requests fail if a later option, such as WithClient, replaces the client.
+func WithAllowedHosts(patterns ...string) Opt {
+	return withHostCheck("WithAllowedHosts", func(host string) bool { return matchesHost(patterns, host) })
+}
+
+// WithDeniedHosts rejects requests to hosts that match any of the patterns with a
+// ForbiddenHostError. See WithAllowedHosts for the pattern syntax, and requirements.
+func WithDeniedHosts(patterns ...string) Opt {
+	return withHostCheck("WithDeniedHosts", func(host string) bool { return !matchesHost(patterns, host) })
+}
+
+func withHostCheck(name string, allowed func(host string) bool) Opt {
+	check := func(req *http.Request) error {
+		if host := req.URL.Hostname(); !allowed(host) {
+			return ForbiddenHostError{Host: host}
+		}
+		return nil
+	}
+	guard := transportGuard{
+		name: name,
+		check: func(req *http.Request, t *http.Transport) error {
+			return check(req)
+		},
+	}
+	return withTransportGuard(guard, func(t *http.Transport) error {
+		// Redirects are sent by the transport without calling the guard's check, but the
+		// transport calls Proxy for every request.
+		proxy := t.Proxy
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			if err := check(req); err != nil {
+				return nil, err
+			}
+			if proxy == nil {
+				return nil, nil
+			}
+			return proxy(req)
+		}
+		return nil
+	})
+}
+
+func matchesHost(patterns []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithAllowedHosts(t *testing.T) {
+	// Allowed requests fail to connect, rather than being rejected.
+	client := jsonapi.WithClient(&http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, errors.New("offline")
+		},
+	}})
+	tests := []struct {
+		name    string
+		url     string
+		opt     jsonapi.Opt
+		allowed bool
+	}{
+		{name: "exact match", url: "https://api.example.com/items/get/ok", opt: jsonapi.WithAllowedHosts("api.example.com"), allowed: true},
+		{name: "case and port are ignored", url: "https://API.example.com:8443/items/get/ok", opt: jsonapi.WithAllowedHosts("api.example.com"), allowed: true},
+		{name: "wildcard matches subdomains", url: "https://a.b.example.com/items/get/ok", opt: jsonapi.WithAllowedHosts("*.example.com"), allowed: true},
+		{name: "wildcard does not match the apex domain", url: "https://example.com/items/get/ok", opt: jsonapi.WithAllowedHosts("*.example.com")},
+		{name: "suffixes are not matched", url: "https://evilexample.com/items/get/ok", opt: jsonapi.WithAllowedHosts("*.example.com")},
+		{name: "unlisted hosts are rejected", url: "http://169.254.169.254/latest/meta-data", opt: jsonapi.WithAllowedHosts("api.example.com")},
+		{name: "denied hosts are rejected", url: "http://169.254.169.254/latest/meta-data", opt: jsonapi.WithDeniedHosts("169.254.169.254")},
+		{name: "other hosts are not denied", url: "https://api.example.com/items/get/ok", opt: jsonapi.WithDeniedHosts("169.254.169.254"), allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), tt.url, client, tt.opt)
+			var fhe jsonapi.ForbiddenHostError
+			if isForbidden := errors.As(err, &fhe); isForbidden == tt.allowed {
+				t.Errorf("expected allowed=%v, got error %v", tt.allowed, err)
+			}
+		})
+	}
+	t.Run("the Opt fails if the client isn't an *http.Client", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "https://api.example.com/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithAllowedHosts("api.example.com"))
+		if err == nil || !strings.Contains(err.Error(), "WithAllowedHosts") {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+	t.Run("requests fail if the client is replaced by a later option", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://169.254.169.254/latest/meta-data",
+			jsonapi.WithDeniedHosts("169.254.169.254"), client)
+		if err == nil || !strings.Contains(err.Error(), "WithDeniedHosts can't be applied") {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+	t.Run("redirects to other hosts are rejected", func(t *testing.T) {
+		var internalCalled bool
+		internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			internalCalled = true
+		}))
+		defer internal.Close()
+		_, port, _ := net.SplitHostPort(internal.Listener.Addr().String())
+		redirect := httptest.NewServer(http.RedirectHandler("http://localhost:"+port+"/", http.StatusFound))
+		defer redirect.Close()
+
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), redirect.URL,
+			jsonapi.WithClient(&http.Client{}), jsonapi.WithAllowedHosts("127.0.0.1"))
+		var fhe jsonapi.ForbiddenHostError
+		if !errors.As(err, &fhe) || fhe.Host != "localhost" {
+			t.Errorf("expected ForbiddenHostError for localhost, got %v", err)
+		}
+		if internalCalled {
+			t.Error("expected the redirect not to be followed")
+		}
+	})
+}
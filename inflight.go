@@ -0,0 +1,96 @@
+package jsonapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithMaxInFlight limits the number of concurrent requests made by calls that share the returned Opt.
+// Create the Opt once and pass it to each call that should share the limit.
+func WithMaxInFlight(n int) Opt {
+	return WithInFlightLimiter(NewInFlightLimiter(n))
+}
+
+// WithInFlightLimiter limits concurrent requests using the given limiter.
+// The Opt returns an error if the limiter's limit is less than one.
+func WithInFlightLimiter(l *InFlightLimiter) Opt {
+	return func(c *Config) error {
+		if l.limit < 1 {
+			return fmt.Errorf("in-flight limit must be at least 1, got %d", l.limit)
+		}
+		c.Interceptors = append(c.Interceptors, l.intercept)
+		return nil
+	}
+}
+
+// NewInFlightLimiter creates a limiter that allows up to n concurrent requests. Limiters with
+// an n of less than one are rejected by WithInFlightLimiter.
+func NewInFlightLimiter(n int) *InFlightLimiter {
+	return &InFlightLimiter{
+		limit: n,
+		sem:   make(chan struct{}, max(n, 0)),
+	}
+}
+
+// InFlightLimiter is a semaphore shared between requests. A request holds its slot
+// until the response body is closed.
+type InFlightLimiter struct {
+	// OnWait is called with the time that each request spent queueing for a slot.
+	OnWait  func(req *http.Request, wait time.Duration)
+	limit   int
+	sem     chan struct{}
+	waiting atomic.Int64
+}
+
+// InFlight returns the number of requests currently holding a slot.
+func (l *InFlightLimiter) InFlight() int {
+	return len(l.sem)
+}
+
+// Waiting returns the number of requests currently queueing for a slot.
+func (l *InFlightLimiter) Waiting() int {
+	return int(l.waiting.Load())
+}
+
+func (l *InFlightLimiter) intercept(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		l.waiting.Add(1)
+		select {
+		case l.sem <- struct{}{}:
+			l.waiting.Add(-1)
+		case <-req.Context().Done():
+			l.waiting.Add(-1)
+			return nil, req.Context().Err()
+		}
+		release := sync.OnceFunc(func() { <-l.sem })
+		if l.OnWait != nil {
+			wait := time.Since(start)
+			if err := safely(func() error { l.OnWait(req, wait); return nil }); err != nil {
+				release()
+				return nil, err
+			}
+		}
+		res, err := next.Do(req)
+		if err != nil {
+			release()
+			return res, err
+		}
+		res.Body = releaseOnClose{ReadCloser: res.Body, release: release}
+		return res, nil
+	})
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
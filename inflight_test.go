@@ -0,0 +1,128 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithMaxInFlight(t *testing.T) {
+	var current, max atomic.Int64
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			m := max.Load()
+			if n <= m || max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+
+	limiter := jsonapi.NewInFlightLimiter(2)
+	var waits atomic.Int64
+	limiter.OnWait = func(req *http.Request, wait time.Duration) {
+		waits.Add(1)
+	}
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithInFlightLimiter(limiter),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", max.Load())
+	}
+	if waits.Load() != 10 {
+		t.Errorf("expected OnWait to be called 10 times, got %d", waits.Load())
+	}
+	if limiter.InFlight() != 0 {
+		t.Errorf("expected all slots to be released, got %d in flight", limiter.InFlight())
+	}
+}
+
+func TestWithMaxInFlightInvalidLimit(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithMaxInFlight(n))
+		cancel()
+		if err == nil || !strings.Contains(err.Error(), "in-flight limit") {
+			t.Errorf("%d: expected an invalid limit error, got %v", n, err)
+		}
+	}
+}
+
+type failingResponseMiddleware struct {
+	panics bool
+}
+
+func (m failingResponseMiddleware) Request(req *http.Request) error {
+	return nil
+}
+
+func (m failingResponseMiddleware) Response(res *http.Response) error {
+	if m.panics {
+		panic("boom")
+	}
+	return errors.New("failed")
+}
+
+func TestWithMaxInFlightResponseMiddlewareError(t *testing.T) {
+	for _, panics := range []bool{false, true} {
+		limiter := jsonapi.NewInFlightLimiter(1)
+		for i := 0; i < 2; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok",
+				jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+				jsonapi.WithInFlightLimiter(limiter),
+				jsonapi.WithMiddleware(failingResponseMiddleware{panics: panics}))
+			cancel()
+			if err == nil || errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("panics=%v, request %d: expected the middleware error, got %v", panics, i, err)
+			}
+		}
+		if limiter.InFlight() != 0 {
+			t.Errorf("panics=%v: expected the slot to be released, got %d in flight", panics, limiter.InFlight())
+		}
+	}
+}
+
+func TestWithMaxInFlightNotFound(t *testing.T) {
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithMaxInFlight(1),
+	}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, ok, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/404", opts...)
+		cancel()
+		if err != nil {
+			t.Fatalf("request %d: expected no error, got %v", i, err)
+		}
+		if ok {
+			t.Errorf("request %d: expected ok=false", i)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package jsonapi
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// InsecureAcknowledgement must be passed to WithInsecureSkipVerify.
+const InsecureAcknowledgement = "I understand that this disables TLS certificate verification"
+
+// WithInsecureSkipVerify disables TLS certificate verification, for local development against
+// servers with self-signed certificates. The acknowledgement must be InsecureAcknowledgement,
+// so that the setting can't be enabled by accident, e.g. by a boolean read from configuration.
+// A warning is logged using the default slog logger the first time the Opt is applied.
+//
+// Prefer WithRootCAs or WithCABundle to trust a development certificate authority.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithInsecureSkipVerify(acknowledgement string) Opt {
+	if acknowledgement != InsecureAcknowledgement {
+		return func(c *Config) error {
+			return errors.New("WithInsecureSkipVerify requires the InsecureAcknowledgement")
+		}
+	}
+	var once sync.Once
+	opt := withTransport(func(t *http.Transport) error {
+		t.TLSClientConfig.InsecureSkipVerify = true
+		return nil
+	})
+	return func(c *Config) error {
+		once.Do(func() {
+			slog.Warn("jsonapi: TLS certificate verification is disabled, do not use this setting in production")
+		})
+		return opt(c)
+	}
+}
@@ -0,0 +1,35 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+
+	t.Run("self-signed certificates are rejected by default", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("the acknowledgement is required", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithInsecureSkipVerify("yes")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("verification can be disabled", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithInsecureSkipVerify(jsonapi.InsecureAcknowledgement))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package jsonapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// IntegrityError is returned when a response body doesn't match its digest.
+type IntegrityError struct {
+	// Header is the header that contained the digest, e.g. "Content-Digest".
+	Header    string `json:"header"`
+	Algorithm string `json:"algorithm"`
+	// Expected and Actual are the base64 encoded digests.
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+func (e IntegrityError) Error() string {
+	return fmt.Sprintf("response body does not match %s %s digest: expected %s, got %s", e.Header, e.Algorithm, e.Expected, e.Actual)
+}
+
+// WithIntegrityCheck verifies response bodies against the sha-256 or sha-512 digest in the
+// Content-Digest header, or a strong ETag of the form "sha256-<digest>", where the digest is
+// base64 or hex encoded. Reading a body that doesn't match returns an IntegrityError once the
+// whole body has been read. Responses without a digest are not checked, nor are responses
+// without content, such as responses to HEAD requests, 204 and 304 responses, and empty
+// bodies, since their digest headers describe the representation that wasn't sent.
+//
+// Digests are calculated over the encoded content, so responses that were transparently
+// decompressed by the HTTP transport are not checked.
+func WithIntegrityCheck() Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				res, err := next.Do(req)
+				if err != nil || res.Uncompressed || !hasContent(req, res) {
+					return res, err
+				}
+				if d, ok := parseDigest(res.Header); ok {
+					d.ReadCloser = res.Body
+					res.Body = d
+				}
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
+
+func hasContent(req *http.Request, res *http.Response) bool {
+	if req.Method == http.MethodHead || res.ContentLength == 0 || res.Body == http.NoBody {
+		return false
+	}
+	return res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotModified
+}
+
+type digestReader struct {
+	io.ReadCloser
+	header    string
+	algorithm string
+	expected  []byte
+	hash      hash.Hash
+}
+
+func (d *digestReader) Read(p []byte) (n int, err error) {
+	n, err = d.ReadCloser.Read(p)
+	d.hash.Write(p[:n])
+	if err == io.EOF {
+		if actual := d.hash.Sum(nil); !bytes.Equal(actual, d.expected) {
+			return n, IntegrityError{
+				Header:    d.header,
+				Algorithm: d.algorithm,
+				Expected:  base64.StdEncoding.EncodeToString(d.expected),
+				Actual:    base64.StdEncoding.EncodeToString(actual),
+			}
+		}
+	}
+	return n, err
+}
+
+// parseDigest returns a reader that verifies the strongest digest in the headers.
+func parseDigest(h http.Header) (d *digestReader, ok bool) {
+	if v := h.Get("Content-Digest"); v != "" {
+		digests := map[string][]byte{}
+		for _, member := range strings.Split(v, ",") {
+			alg, value, _ := strings.Cut(strings.TrimSpace(member), "=")
+			value = strings.Trim(value, ":")
+			if digest, err := base64.StdEncoding.DecodeString(value); err == nil {
+				digests[strings.ToLower(alg)] = digest
+			}
+		}
+		if digest, ok := digests["sha-512"]; ok {
+			return &digestReader{header: "Content-Digest", algorithm: "sha-512", expected: digest, hash: sha512.New()}, true
+		}
+		if digest, ok := digests["sha-256"]; ok {
+			return &digestReader{header: "Content-Digest", algorithm: "sha-256", expected: digest, hash: sha256.New()}, true
+		}
+	}
+	etag := h.Get("ETag")
+	if value, ok := strings.CutPrefix(etag, `"sha256-`); ok {
+		value = strings.TrimSuffix(value, `"`)
+		digest, err := hex.DecodeString(value)
+		if err != nil {
+			digest, err = base64.StdEncoding.DecodeString(value)
+		}
+		if err == nil && len(digest) == sha256.Size {
+			return &digestReader{header: "ETag", algorithm: "sha-256", expected: digest, hash: sha256.New()}, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,84 @@
+package jsonapi_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithIntegrityCheck(t *testing.T) {
+	body := []byte(`{"items":["item1","item2"]}`)
+	sum := sha256.Sum256(body)
+	b64, hexSum := base64.StdEncoding.EncodeToString(sum[:]), hex.EncodeToString(sum[:])
+	tests := []struct {
+		name        string
+		header      http.Header
+		body        []byte
+		expectError bool
+	}{
+		{name: "no digest", header: http.Header{}, body: body},
+		{name: "matching Content-Digest", header: http.Header{"Content-Digest": []string{"sha-256=:" + b64 + ":"}}, body: body},
+		{name: "mismatched Content-Digest", header: http.Header{"Content-Digest": []string{"sha-256=:" + b64 + ":"}}, body: []byte(`{"items":[]}`), expectError: true},
+		{name: "matching ETag", header: http.Header{"Etag": []string{`"sha256-` + hexSum + `"`}}, body: body},
+		{name: "mismatched ETag", header: http.Header{"Etag": []string{`"sha256-` + b64 + `"`}}, body: []byte(`{"items":[]}`), expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.Write(tt.body)
+			})
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+				jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithIntegrityCheck())
+			var ie jsonapi.IntegrityError
+			if isIntegrityError := errors.As(err, &ie); isIntegrityError != tt.expectError {
+				t.Errorf("expected integrity error=%v, got %v", tt.expectError, err)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWithIntegrityCheckWithoutContent(t *testing.T) {
+	sum := sha256.Sum256([]byte(`{"items":["item1","item2"]}`))
+	digest := "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{name: "HEAD", method: http.MethodHead, status: http.StatusOK},
+		{name: "no content", method: http.MethodDelete, status: http.StatusNoContent},
+		{name: "not modified", method: http.MethodGet, status: http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Digest", digest)
+				w.WriteHeader(tt.status)
+			})
+			rt := jsonapi.NewTransport(jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithIntegrityCheck())
+			req, _ := http.NewRequest(tt.method, "http://example.com/items", nil)
+			res, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer res.Body.Close()
+			if _, err := io.ReadAll(res.Body); err != nil {
+				t.Errorf("expected the empty body not to be checked, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// InvalidPathError is returned by JoinURL when a path element is rejected.
+type InvalidPathError struct {
+	Element string `json:"element"`
+	Reason  string `json:"reason"`
+}
+
+func (e InvalidPathError) Error() string {
+	return fmt.Sprintf("invalid path element %q: %s", e.Element, e.Reason)
+}
+
+// JoinURL joins the path elements to the base URL, escaping each element as required.
+//
+// Unlike string concatenation, or url.JoinPath, elements containing path traversal (".."),
+// empty segments (double slashes), or unencoded spaces are rejected with an InvalidPathError,
+// since these are usually the result of building a URL from a missing or unvalidated value.
+func JoinURL(base string, elems ...string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	for _, elem := range elems {
+		if err := validatePathElement(elem); err != nil {
+			return "", err
+		}
+	}
+	return u.JoinPath(elems...).String(), nil
+}
+
+func validatePathElement(elem string) error {
+	if strings.TrimSpace(elem) == "" {
+		return InvalidPathError{Element: elem, Reason: "empty element"}
+	}
+	if strings.ContainsAny(elem, " \t\r\n") {
+		return InvalidPathError{Element: elem, Reason: "unencoded whitespace"}
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(elem, "/"), "/"), "/") {
+		if segment == "" {
+			return InvalidPathError{Element: elem, Reason: "double slash"}
+		}
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		if segment == "." || segment == ".." {
+			return InvalidPathError{Element: elem, Reason: "path traversal"}
+		}
+	}
+	return nil
+}
+
+// WithBaseURL resolves relative request URLs, e.g. "/items/123", against the base URL, so that
+// callers don't need to build URLs by concatenating strings. Paths are joined to the base path,
+// and validated as in JoinURL. Absolute request URLs are not changed.
+func WithBaseURL(baseURL string) Opt {
+	u, err := url.Parse(baseURL)
+	if err == nil && (!u.IsAbs() || u.Host == "") {
+		err = fmt.Errorf("base URL %q must be absolute", baseURL)
+	}
+	return func(c *Config) error {
+		if err != nil {
+			return fmt.Errorf("failed to parse base URL: %w", err)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// resolveURL resolves ref against the base URL. Absolute URLs are returned unchanged. The path
+// of a relative ref is joined to the base path and validated as in JoinURL, and its query is
+// added to the base query.
+func resolveURL(base *url.URL, ref string) (string, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if r.IsAbs() {
+		return ref, nil
+	}
+	u := *base
+	rawPath, _, _ := strings.Cut(ref, "?")
+	rawPath, _, _ = strings.Cut(rawPath, "#")
+	if strings.Trim(rawPath, "/") != "" {
+		if err := validatePathElement(rawPath); err != nil {
+			return "", err
+		}
+		u = *u.JoinPath(rawPath)
+	}
+	if r.RawQuery != "" {
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += r.RawQuery
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,92 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		elems    []string
+		expected string
+		reason   string
+	}{
+		{name: "elements are joined", base: "https://example.com/api", elems: []string{"users", "123"}, expected: "https://example.com/api/users/123"},
+		{name: "slashes between elements are not duplicated", base: "https://example.com/api/", elems: []string{"/users/", "123"}, expected: "https://example.com/api/users/123"},
+		{name: "multi-segment elements are allowed", base: "https://example.com", elems: []string{"users/123/orders"}, expected: "https://example.com/users/123/orders"},
+		{name: "the trailing slash is kept", base: "https://example.com", elems: []string{"users/"}, expected: "https://example.com/users/"},
+		{name: "the query is kept", base: "https://example.com/api?v=2", elems: []string{"users"}, expected: "https://example.com/api/users?v=2"},
+		{name: "special characters are escaped", base: "https://example.com", elems: []string{"a?b#c"}, expected: "https://example.com/a%3Fb%23c"},
+		{name: "path traversal is rejected", base: "https://example.com", elems: []string{"users", "../admin"}, reason: "path traversal"},
+		{name: "encoded path traversal is rejected", base: "https://example.com", elems: []string{"%2e%2e"}, reason: "path traversal"},
+		{name: "double slashes are rejected", base: "https://example.com", elems: []string{"users//123"}, reason: "double slash"},
+		{name: "empty elements are rejected", base: "https://example.com", elems: []string{"users", ""}, reason: "empty element"},
+		{name: "spaces are rejected", base: "https://example.com", elems: []string{"first name"}, reason: "unencoded whitespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := jsonapi.JoinURL(tt.base, tt.elems...)
+			if tt.reason != "" {
+				var ipe jsonapi.InvalidPathError
+				if !errors.As(err, &ipe) {
+					t.Fatalf("expected InvalidPathError, got %v", err)
+				}
+				if ipe.Reason != tt.reason {
+					t.Errorf("expected reason %q, got %q", tt.reason, ipe.Reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	var received string
+	routes := createTestRoutes()
+	client := jsonapi.WithClient(testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.String()
+		routes.ServeHTTP(w, r)
+	})})
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{name: "paths are joined to the base path", url: "/get/ok?page=2", expected: "https://example.com/items/get/ok?page=2"},
+		{name: "paths without a leading slash are joined", url: "get/ok", expected: "https://example.com/items/get/ok"},
+		{name: "escaped characters are kept", url: "/get/a%2Fb", expected: "https://example.com/items/get/a%2Fb"},
+		{name: "absolute URLs are unchanged", url: "https://other.example.com/items/get/ok", expected: "https://other.example.com/items/get/ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonapi.Get[itemsGetResponse](context.Background(), tt.url, client, jsonapi.WithBaseURL("https://example.com/items"))
+			if received != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, received)
+			}
+		})
+	}
+	t.Run("path traversal is rejected", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/../admin", client, jsonapi.WithBaseURL("https://example.com/items"))
+		var ipe jsonapi.InvalidPathError
+		if !errors.As(err, &ipe) {
+			t.Errorf("expected InvalidPathError, got %v", err)
+		}
+	})
+	t.Run("relative base URLs are rejected", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/get/ok", client, jsonapi.WithBaseURL("/items")); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
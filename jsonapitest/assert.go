@@ -0,0 +1,193 @@
+package jsonapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// BodyMatcher checks a request body, which has been decoded from JSON. It returns an error
+// describing the mismatch, or nil if the body matches.
+type BodyMatcher func(body any) error
+
+// AssertCalled fails the test unless the mock received a call with the method and URL whose
+// request body matches all of the matchers. It returns true if a matching call was found.
+func AssertCalled(t testing.TB, m *Mock, method, url string, matchers ...BodyMatcher) bool {
+	t.Helper()
+	var mismatches []string
+	for _, c := range m.Calls() {
+		if c.Method != method || c.URL != url {
+			continue
+		}
+		err := matchBody(c.Request, matchers)
+		if err == nil {
+			return true
+		}
+		mismatches = append(mismatches, err.Error())
+	}
+	if len(mismatches) == 0 {
+		t.Errorf("expected a call to %s %s, got %s", method, url, formatCalls(m.Calls()))
+		return false
+	}
+	t.Errorf("expected a call to %s %s with a matching body, got:\n%s", method, url, strings.Join(mismatches, "\n"))
+	return false
+}
+
+// AssertNotCalled fails the test if the mock received a call with the method and URL.
+func AssertNotCalled(t testing.TB, m *Mock, method, url string) bool {
+	t.Helper()
+	for _, c := range m.Calls() {
+		if c.Method == method && c.URL == url {
+			t.Errorf("expected no calls to %s %s", method, url)
+			return false
+		}
+	}
+	return true
+}
+
+func matchBody(request any, matchers []BodyMatcher) error {
+	body, err := toJSONValue(request)
+	if err != nil {
+		return err
+	}
+	for _, match := range matchers {
+		if err = match(body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "no calls"
+	}
+	s := make([]string, len(calls))
+	for i, c := range calls {
+		s[i] = c.Method + " " + c.URL
+	}
+	return strings.Join(s, ", ")
+}
+
+// Subset matches bodies that contain all of the fields of expected, with equal values.
+// Objects in the body may have additional fields. Arrays must have the same length, and
+// each element must contain the expected element.
+func Subset(expected any) BodyMatcher {
+	return func(body any) error {
+		e, err := toJSONValue(expected)
+		if err != nil {
+			return err
+		}
+		return subset("", e, body)
+	}
+}
+
+func subset(path string, expected, actual any) error {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %s", pathOrRoot(path), formatJSON(actual))
+		}
+		for k, v := range e {
+			av, ok := a[k]
+			if !ok {
+				return fmt.Errorf("%s: missing field", path+"/"+k)
+			}
+			if err := subset(path+"/"+k, v, av); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			return fmt.Errorf("%s: expected %s, got %s", pathOrRoot(path), formatJSON(expected), formatJSON(actual))
+		}
+		for i := range e {
+			if err := subset(path+"/"+strconv.Itoa(i), e[i], a[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("%s: expected %s, got %s", pathOrRoot(path), formatJSON(expected), formatJSON(actual))
+	}
+	return nil
+}
+
+// PointerEquals matches bodies where the value at the JSON pointer, e.g. "/items/0/id",
+// is equal to expected.
+func PointerEquals(pointer string, expected any) BodyMatcher {
+	return func(body any) error {
+		e, err := toJSONValue(expected)
+		if err != nil {
+			return err
+		}
+		actual, ok := lookup(body, pointer)
+		if !ok {
+			return fmt.Errorf("%s: not found", pointer)
+		}
+		if !reflect.DeepEqual(e, actual) {
+			return fmt.Errorf("%s: expected %s, got %s", pointer, formatJSON(e), formatJSON(actual))
+		}
+		return nil
+	}
+}
+
+func lookup(v any, pointer string) (any, bool) {
+	if pointer == "" {
+		return v, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = value[token]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil, false
+			}
+			v = value[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// toJSONValue converts v to the value that decoding its JSON encoding into an any would produce.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapitest: failed to marshal body: %w", err)
+	}
+	var value any
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("jsonapitest: failed to unmarshal body: %w", err)
+	}
+	return value, nil
+}
+
+func formatJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
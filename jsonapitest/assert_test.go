@@ -0,0 +1,68 @@
+package jsonapitest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi/jsonapitest"
+)
+
+type lineItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type createOrder struct {
+	Customer string     `json:"customer"`
+	Items    []lineItem `json:"items"`
+	Note     string     `json:"note"`
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCalled(t *testing.T) {
+	m := jsonapitest.NewMock().On(http.MethodPost, "/orders", order{ID: "1"})
+	req := createOrder{Customer: "c1", Items: []lineItem{{SKU: "a", Quantity: 2}}, Note: "leave at door"}
+	if err := m.Post(context.Background(), "/orders", req, &order{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("matching calls pass", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		jsonapitest.AssertCalled(rt, m, http.MethodPost, "/orders",
+			jsonapitest.Subset(map[string]any{"customer": "c1", "items": []any{map[string]any{"sku": "a"}}}),
+			jsonapitest.PointerEquals("/items/0/quantity", 2))
+		jsonapitest.AssertNotCalled(rt, m, http.MethodDelete, "/orders")
+		if len(rt.errors) > 0 {
+			t.Errorf("expected no failures, got %v", rt.errors)
+		}
+	})
+	t.Run("mismatched bodies fail with the path of the mismatch", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		jsonapitest.AssertCalled(rt, m, http.MethodPost, "/orders", jsonapitest.Subset(map[string]any{"items": []any{map[string]any{"sku": "b"}}}))
+		if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], `/items/0/sku: expected "b", got "a"`) {
+			t.Errorf("unexpected failures %v", rt.errors)
+		}
+	})
+	t.Run("missing calls fail", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		jsonapitest.AssertCalled(rt, m, http.MethodPut, "/orders")
+		jsonapitest.AssertNotCalled(rt, m, http.MethodPost, "/orders")
+		if len(rt.errors) != 2 {
+			t.Errorf("expected 2 failures, got %v", rt.errors)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+// Package jsonapitest provides test doubles for code that uses the jsonapi package.
+package jsonapitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/a-h/jsonapi"
+)
+
+// Call is a call received by a Mock.
+type Call struct {
+	Method string
+	URL    string
+	// Request is the request value passed to Post or Put.
+	Request any
+}
+
+type reply struct {
+	response any
+	err      error
+}
+
+// NewMock creates a Mock with no registered responses.
+func NewMock() *Mock {
+	return &Mock{
+		replies: map[string]reply{},
+		m:       &sync.Mutex{},
+	}
+}
+
+// Mock is a jsonapi.Caller that returns registered responses without making HTTP requests.
+// Calls without a registered response return an error.
+type Mock struct {
+	calls   []Call
+	replies map[string]reply
+	m       *sync.Mutex
+}
+
+var _ jsonapi.Caller = (*Mock)(nil)
+
+// On registers the response for calls with the given method and URL. The response is
+// copied into the caller's response value by encoding it to JSON and decoding it again.
+func (m *Mock) On(method, url string, response any) *Mock {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.replies[method+" "+url] = reply{response: response}
+	return m
+}
+
+// OnError registers the error returned for calls with the given method and URL.
+// A Get call that receives a jsonapi.InvalidStatusError with a 404 status returns ok=false,
+// matching the behaviour of jsonapi.Get.
+func (m *Mock) OnError(method, url string, err error) *Mock {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.replies[method+" "+url] = reply{err: err}
+	return m
+}
+
+// Calls returns the calls received by the mock, in order.
+func (m *Mock) Calls() []Call {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *Mock) Get(ctx context.Context, url string, response any, opts ...jsonapi.Opt) (ok bool, err error) {
+	err = m.call(http.MethodGet, url, nil, response)
+	if ise, isStatusErr := err.(jsonapi.InvalidStatusError); isStatusErr && ise.Status == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (m *Mock) Post(ctx context.Context, url string, request, response any, opts ...jsonapi.Opt) error {
+	return m.call(http.MethodPost, url, request, response)
+}
+
+func (m *Mock) Put(ctx context.Context, url string, request, response any, opts ...jsonapi.Opt) error {
+	return m.call(http.MethodPut, url, request, response)
+}
+
+func (m *Mock) Delete(ctx context.Context, url string, response any, opts ...jsonapi.Opt) error {
+	return m.call(http.MethodDelete, url, nil, response)
+}
+
+func (m *Mock) call(method, url string, request, response any) error {
+	m.m.Lock()
+	m.calls = append(m.calls, Call{Method: method, URL: url, Request: request})
+	r, ok := m.replies[method+" "+url]
+	m.m.Unlock()
+	if !ok {
+		return fmt.Errorf("jsonapitest: no response registered for %s %s", method, url)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if response == nil || r.response == nil {
+		return nil
+	}
+	data, err := json.Marshal(r.response)
+	if err != nil {
+		return fmt.Errorf("jsonapitest: failed to marshal response: %w", err)
+	}
+	if err = json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("jsonapitest: failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package jsonapitest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/jsonapi/jsonapitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+type order struct {
+	ID string `json:"id"`
+}
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+	var caller jsonapi.Caller = jsonapitest.NewMock().
+		On(http.MethodGet, "/orders/1", order{ID: "1"}).
+		OnError(http.MethodGet, "/orders/2", jsonapi.InvalidStatusError{Status: http.StatusNotFound}).
+		On(http.MethodPost, "/orders", order{ID: "3"})
+
+	var o order
+	ok, err := caller.Get(ctx, "/orders/1", &o)
+	if err != nil || !ok {
+		t.Fatalf("expected ok with no error, got %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(order{ID: "1"}, o); diff != "" {
+		t.Error(diff)
+	}
+
+	ok, err = caller.Get(ctx, "/orders/2", &o)
+	if err != nil || ok {
+		t.Errorf("expected not ok with no error, got %v, %v", ok, err)
+	}
+
+	if err = caller.Post(ctx, "/orders", order{}, &o); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err = caller.Delete(ctx, "/orders/3", nil); err == nil {
+		t.Error("expected an error for an unregistered call")
+	}
+
+	expected := []jsonapitest.Call{
+		{Method: http.MethodGet, URL: "/orders/1"},
+		{Method: http.MethodGet, URL: "/orders/2"},
+		{Method: http.MethodPost, URL: "/orders", Request: order{}},
+		{Method: http.MethodDelete, URL: "/orders/3"},
+	}
+	if diff := cmp.Diff(expected, caller.(*jsonapitest.Mock).Calls()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,20 @@
+package jsonapitest
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/a-h/jsonapi"
+)
+
+// NewTLSServer starts a TLS server for the handler, and returns options that trust the
+// server's certificate, and resolve relative request URLs, e.g. "/orders/1", against
+// server.URL. Requests use the package's default HTTP client and transport, so tests
+// exercise the TLS and transport code paths. The caller must close the server.
+func NewTLSServer(handler http.Handler) (server *httptest.Server, opts []jsonapi.Opt) {
+	server = httptest.NewTLSServer(handler)
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return server, []jsonapi.Opt{jsonapi.WithRootCAs(pool), jsonapi.WithBaseURL(server.URL)}
+}
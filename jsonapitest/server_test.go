@@ -0,0 +1,30 @@
+package jsonapitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/jsonapi/jsonapitest"
+)
+
+func TestNewTLSServer(t *testing.T) {
+	s, opts := jsonapitest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil {
+			http.Error(w, "expected TLS", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(order{ID: "1"})
+	}))
+	defer s.Close()
+
+	o, ok, err := jsonapi.Get[order](context.Background(), "/orders/1", opts...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !ok || o.ID != "1" {
+		t.Errorf("unexpected response %v, %+v", ok, o)
+	}
+}
@@ -0,0 +1,259 @@
+package jsonapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// InvalidTokenError is returned when a token fails validation.
+type InvalidTokenError struct {
+	Reason string `json:"reason"`
+}
+
+func (e InvalidTokenError) Error() string {
+	return "invalid token: " + e.Reason
+}
+
+// NewJWKS creates a JWKS that validates tokens using the keys published at url.
+// The opts are used to fetch the keys.
+func NewJWKS(url string, opts ...Opt) *JWKS {
+	return &JWKS{
+		URL:  url,
+		Opts: opts,
+	}
+}
+
+// JWKS validates RS256 and ES256 signed JWTs using a JSON Web Key Set, e.g. from an identity
+// provider's jwks_uri. Keys are fetched on first use, and fetched again when a token is signed
+// with an unknown key ID.
+type JWKS struct {
+	URL  string
+	Opts []Opt
+	// Audience, if set, must be one of the token's audiences.
+	Audience string
+	// Issuer, if set, must match the token's issuer.
+	Issuer string
+
+	m    sync.Mutex
+	keys map[string]crypto.PublicKey
+}
+
+type jwk struct {
+	KeyID string `json:"kid"`
+	Type  string `json:"kty"`
+	Curve string `json:"crv"`
+	N     string `json:"n"`
+	E     string `json:"e"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtValidationClaims struct {
+	Issuer   string   `json:"iss"`
+	Audience audience `json:"aud"`
+}
+
+// audience is a JWT aud claim, which can be a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// Validate checks the token's signature, audience and issuer. Expiry is not checked.
+// Returns an InvalidTokenError if the token is not valid.
+func (j *JWKS) Validate(ctx context.Context, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return InvalidTokenError{Reason: "unexpected token format"}
+	}
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return InvalidTokenError{Reason: "failed to decode header: " + err.Error()}
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return InvalidTokenError{Reason: "failed to decode signature: " + err.Error()}
+	}
+	key, err := j.key(ctx, header.KeyID)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = verifyJWTSignature(header.Algorithm, key, digest[:], signature); err != nil {
+		return err
+	}
+	var claims jwtValidationClaims
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return InvalidTokenError{Reason: "failed to decode claims: " + err.Error()}
+	}
+	if j.Audience != "" && !slices.Contains(claims.Audience, j.Audience) {
+		return InvalidTokenError{Reason: fmt.Sprintf("audience %q not in %q", j.Audience, []string(claims.Audience))}
+	}
+	if j.Issuer != "" && claims.Issuer != j.Issuer {
+		return InvalidTokenError{Reason: fmt.Sprintf("expected issuer %q, got %q", j.Issuer, claims.Issuer)}
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, digest, signature []byte) error {
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return InvalidTokenError{Reason: "RS256 token signed with a non-RSA key"}
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, signature); err != nil {
+			return InvalidTokenError{Reason: "invalid signature"}
+		}
+		return nil
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return InvalidTokenError{Reason: "ES256 token signed with a non-EC key"}
+		}
+		if len(signature) != 64 {
+			return InvalidTokenError{Reason: "invalid signature length"}
+		}
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return InvalidTokenError{Reason: "invalid signature"}
+		}
+		return nil
+	}
+	return InvalidTokenError{Reason: fmt.Sprintf("unsupported algorithm %q", alg)}
+}
+
+// Ready fetches the key set if it hasn't been fetched, e.g. for use in a readiness probe.
+func (j *JWKS) Ready(ctx context.Context) error {
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.keys != nil {
+		return nil
+	}
+	return j.fetch(ctx)
+}
+
+// key returns the key with the ID, fetching the key set if the key isn't known.
+func (j *JWKS) key(ctx context.Context, id string) (crypto.PublicKey, error) {
+	j.m.Lock()
+	defer j.m.Unlock()
+	if key, ok := j.keys[id]; ok {
+		return key, nil
+	}
+	if err := j.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := j.keys[id]; ok {
+		return key, nil
+	}
+	return nil, InvalidTokenError{Reason: fmt.Sprintf("unknown key ID %q", id)}
+}
+
+func (j *JWKS) fetch(ctx context.Context) error {
+	set, _, err := Get[jwkSet](ctx, j.URL, j.Opts...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			return fmt.Errorf("failed to parse JWKS key %q: %w", k.KeyID, err)
+		}
+		if key != nil {
+			keys[k.KeyID] = key
+		}
+	}
+	j.keys = keys
+	return nil
+}
+
+// publicKey returns the key, or nil if the key type is not supported.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch {
+	case k.Type == "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode modulus: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case k.Type == "EC" && k.Curve == "P-256":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode x: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode y: %w", err)
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+// WithTokenValidator validates tokens fetched by the auth middleware added by WithAuthMiddleware
+// before they're cached and used, e.g. using JWKS.Validate, so that a misconfigured token fetcher
+// returns a clear error instead of causing 401 responses. It must be applied after WithAuthMiddleware.
+func WithTokenValidator(validate func(ctx context.Context, token string) error) Opt {
+	return func(c *Config) error {
+		var found bool
+		for _, m := range c.Middleware {
+			if am, ok := m.(*AuthMiddleware); ok {
+				am.Validate = validate
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("failed to set token validator: no auth middleware, use WithAuthMiddleware first")
+		}
+		return nil
+	}
+}
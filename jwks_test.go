@@ -0,0 +1,129 @@
+package jsonapi_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(input))
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + b64(signature)
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	var fetches int
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		respond.WithJSON(w, map[string]any{"keys": []map[string]string{
+			{"kid": "rsa", "kty": "RSA", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kid": "ec", "kty": "EC", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}}, http.StatusOK)
+	})
+	jwks := jsonapi.NewJWKS("/.well-known/jwks.json", jsonapi.WithClient(testClient{Handler: h}))
+	jwks.Audience = "orders"
+	claims := map[string]any{"aud": []string{"orders", "billing"}, "exp": 2000000000}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "RS256", token: signJWT(t, "RS256", "rsa", rsaKey, claims), valid: true},
+		{name: "ES256", token: signJWT(t, "ES256", "ec", ecKey, claims), valid: true},
+		{name: "wrong key", token: signJWT(t, "ES256", "ec", otherKey, claims)},
+		{name: "unknown key ID", token: signJWT(t, "ES256", "other", otherKey, claims)},
+		{name: "wrong algorithm for key", token: signJWT(t, "ES256", "rsa", ecKey, claims)},
+		{name: "wrong audience", token: signJWT(t, "RS256", "rsa", rsaKey, map[string]any{"aud": "billing"})},
+		{name: "malformed", token: "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jwks.Validate(context.Background(), tt.token)
+			if tt.valid && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			var ite jsonapi.InvalidTokenError
+			if !tt.valid && !errors.As(err, &ite) {
+				t.Fatalf("expected InvalidTokenError, got %v", err)
+			}
+		})
+	}
+	t.Run("keys are cached until an unknown key ID is used", func(t *testing.T) {
+		if fetches != 2 {
+			t.Errorf("expected 2 fetches, got %d", fetches)
+		}
+	})
+	t.Run("auth middleware tokens are validated", func(t *testing.T) {
+		badToken := signJWT(t, "ES256", "ec", otherKey, claims)
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+			jsonapi.WithAuthMiddleware(func() (string, error) { return badToken, nil }),
+			jsonapi.WithTokenValidator(jwks.Validate))
+		var ite jsonapi.InvalidTokenError
+		if !errors.As(err, &ite) {
+			t.Errorf("expected InvalidTokenError, got %v", err)
+		}
+	})
+	t.Run("Ready does not refetch keys", func(t *testing.T) {
+		if err := jwks.Ready(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if fetches != 2 {
+			t.Errorf("expected 2 fetches, got %d", fetches)
+		}
+	})
+	t.Run("the validator requires auth middleware", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", jsonapi.WithTokenValidator(jwks.Validate))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type languageContextKey struct{}
+
+// ContextWithLanguage returns a context that carries the end user's preferred languages,
+// as BCP 47 tags in order of preference, e.g. "fr-CH", "fr", "en".
+// The languages are sent in the Accept-Language header of requests made with WithLanguage.
+func ContextWithLanguage(ctx context.Context, tags ...string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, tags)
+}
+
+// LanguageFromContext returns the languages set by ContextWithLanguage.
+func LanguageFromContext(ctx context.Context) (tags []string) {
+	tags, _ = ctx.Value(languageContextKey{}).([]string)
+	return tags
+}
+
+// WithLanguage sets the Accept-Language header of requests to the languages in the request
+// context, see ContextWithLanguage. If the context has no languages, the tags are used,
+// in order of preference. If neither are set, the header is not modified.
+func WithLanguage(tags ...string) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, languageMiddleware(tags))
+		return nil
+	}
+}
+
+type languageMiddleware []string
+
+func (m languageMiddleware) Request(req *http.Request) error {
+	tags := LanguageFromContext(req.Context())
+	if len(tags) == 0 {
+		tags = m
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	req.Header.Set("Accept-Language", acceptLanguage(tags))
+	return nil
+}
+
+func (m languageMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+// acceptLanguage formats the tags with decreasing quality values, e.g. "fr-CH, fr;q=0.9, en;q=0.8".
+func acceptLanguage(tags []string) string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		q := 10 - i
+		switch {
+		case i == 0:
+			values[i] = tag
+		case q > 0:
+			values[i] = fmt.Sprintf("%s;q=0.%d", tag, q)
+		default:
+			values[i] = tag + ";q=0.1"
+		}
+	}
+	return strings.Join(values, ", ")
+}
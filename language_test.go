@@ -0,0 +1,55 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		tags     []string
+		expected string
+	}{
+		{
+			name:     "no languages",
+			ctx:      context.Background(),
+			expected: "",
+		},
+		{
+			name:     "default languages",
+			ctx:      context.Background(),
+			tags:     []string{"en-GB", "en"},
+			expected: "en-GB, en;q=0.9",
+		},
+		{
+			name:     "context languages take precedence",
+			ctx:      jsonapi.ContextWithLanguage(context.Background(), "fr-CH", "fr", "en"),
+			tags:     []string{"en-GB"},
+			expected: "fr-CH, fr;q=0.9, en;q=0.8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual string
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actual = r.Header.Get("Accept-Language")
+				respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+			})
+			_, _, err := jsonapi.Get[itemsGetResponse](tt.ctx, "/items",
+				jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithLanguage(tt.tags...))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// NewLazy creates a Lazy that initializes its value by calling init on first use.
+func NewLazy[T any](init func(ctx context.Context) (T, error)) *Lazy[T] {
+	return &Lazy[T]{Init: init}
+}
+
+// Lazy is a value that is expensive to create, e.g. because it loads certificates or fetches
+// configuration over the network, and so is initialized on first use rather than at startup.
+//
+// Concurrent callers share a single call to Init. If Init fails, the error is returned to the
+// waiting callers, and the next call tries again.
+type Lazy[T any] struct {
+	Init func(ctx context.Context) (T, error)
+
+	m     sync.Mutex
+	ready bool
+	value T
+	call  *lazyCall[T]
+}
+
+type lazyCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Get returns the value, initializing it if required. Init is not cancelled if ctx is done, so
+// that other callers can still use the result.
+func (l *Lazy[T]) Get(ctx context.Context) (value T, err error) {
+	l.m.Lock()
+	if l.ready {
+		l.m.Unlock()
+		return l.value, nil
+	}
+	call := l.call
+	if call == nil {
+		call = &lazyCall[T]{done: make(chan struct{})}
+		l.call = call
+		go l.init(context.WithoutCancel(ctx), call)
+	}
+	l.m.Unlock()
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case <-call.done:
+		return call.value, call.err
+	}
+}
+
+func (l *Lazy[T]) init(ctx context.Context, call *lazyCall[T]) {
+	call.err = safely(func() (err error) {
+		call.value, err = l.Init(ctx)
+		return err
+	})
+	l.m.Lock()
+	if call.err == nil {
+		l.ready, l.value = true, call.value
+	}
+	l.call = nil
+	l.m.Unlock()
+	close(call.done)
+}
+
+// Ready initializes the value if required, and returns an error if initialization fails, e.g.
+// for use in a readiness probe.
+func (l *Lazy[T]) Ready(ctx context.Context) error {
+	_, err := l.Get(ctx)
+	return err
+}
+
+// WithLazyClient sets the client used to make requests to one that's created on the first
+// request, e.g. an *http.Client with client certificates loaded from a secret store.
+func WithLazyClient(client *Lazy[Doer]) Opt {
+	return func(c *Config) error {
+		c.Client = DoerFunc(func(req *http.Request) (*http.Response, error) {
+			doer, err := client.Get(req.Context())
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client: %w", err)
+			}
+			return doer.Do(req)
+		})
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("concurrent callers share a single initialization", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		l := jsonapi.NewLazy(func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		})
+		var wg sync.WaitGroup
+		values := make([]int, 10)
+		for i := range values {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				values[i], _ = l.Get(context.Background())
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		if n := calls.Load(); n != 1 {
+			t.Errorf("expected 1 initialization, got %d", n)
+		}
+		for _, v := range values {
+			if v != 42 {
+				t.Errorf("expected 42, got %d", v)
+			}
+		}
+	})
+	t.Run("failed initialization is retried", func(t *testing.T) {
+		var calls int
+		l := jsonapi.NewLazy(func(ctx context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("unavailable")
+			}
+			return "ok", nil
+		})
+		if err := l.Ready(context.Background()); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if err := l.Ready(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if v, _ := l.Get(context.Background()); v != "ok" || calls != 2 {
+			t.Errorf("expected ok after 2 calls, got %q after %d calls", v, calls)
+		}
+	})
+	t.Run("callers can stop waiting", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		l := jsonapi.NewLazy(func(ctx context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := l.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+	t.Run("the client is created on the first request", func(t *testing.T) {
+		var created bool
+		client := jsonapi.NewLazy(func(ctx context.Context) (jsonapi.Doer, error) {
+			created = true
+			return testClient{Handler: createTestRoutes()}, nil
+		})
+		opts := []jsonapi.Opt{jsonapi.WithLazyClient(client)}
+		if created {
+			t.Fatal("expected the client not to be created before use")
+		}
+		resp, ok, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", opts...)
+		if err != nil || !ok {
+			t.Fatalf("expected ok, got %v, %v", ok, err)
+		}
+		if resp.Items[0] != expectedItemsGetResponse.Items[0] || !created {
+			t.Errorf("unexpected response %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package jsonapi
+
+import (
+	"context"
+	"maps"
+)
+
+type callMetadataContextKey struct{}
+
+// WithCallMetadata returns a context that carries call-site metadata, such as an operation
+// name, to middleware and interceptors, which can read it from the request context using
+// CallMetadata. The metadata is merged with any metadata already in the context, with
+// values in m taking precedence. The metadata is not sent to the server.
+func WithCallMetadata(ctx context.Context, m map[string]string) context.Context {
+	merged := maps.Clone(CallMetadata(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(m))
+	}
+	maps.Copy(merged, m)
+	return context.WithValue(ctx, callMetadataContextKey{}, merged)
+}
+
+// CallMetadata returns the metadata set by WithCallMetadata, or nil if none was set.
+// The returned map must not be modified.
+func CallMetadata(ctx context.Context) map[string]string {
+	m, _ := ctx.Value(callMetadataContextKey{}).(map[string]string)
+	return m
+}
@@ -0,0 +1,41 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type metadataMiddleware struct {
+	metadata map[string]string
+}
+
+func (m *metadataMiddleware) Request(req *http.Request) error {
+	m.metadata = jsonapi.CallMetadata(req.Context())
+	return nil
+}
+
+func (m *metadataMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+func TestWithCallMetadata(t *testing.T) {
+	ctx := jsonapi.WithCallMetadata(context.Background(), map[string]string{"operation": "ListItems", "team": "a"})
+	ctx = jsonapi.WithCallMetadata(ctx, map[string]string{"team": "b"})
+
+	m := &metadataMiddleware{}
+	_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok", jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithMiddleware(m))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[string]string{"operation": "ListItems", "team": "b"}
+	if diff := cmp.Diff(expected, m.metadata); diff != "" {
+		t.Error(diff)
+	}
+	if jsonapi.CallMetadata(context.Background()) != nil {
+		t.Error("expected no metadata in an empty context")
+	}
+}
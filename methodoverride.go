@@ -0,0 +1,65 @@
+package jsonapi
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WithMethodOverride resends PATCH and DELETE requests as POST requests with an
+// X-HTTP-Method-Override header when the response is a 405 Method Not Allowed or a
+// 501 Not Implemented, e.g. because a proxy between the client and the API rejects the method.
+//
+// URLs that reject a method are remembered, so that later requests with the method to the same
+// host and path are sent as POST requests without trying the original method first. Rejections
+// are only remembered if the POST request isn't rejected too, and a rejection by one path isn't
+// applied to the rest of the host, since an API may not implement a method for some endpoints.
+// Create the Opt once and share it between calls to share what's remembered.
+func WithMethodOverride() Opt {
+	var m sync.Mutex
+	rejected := map[string]bool{}
+	key := func(req *http.Request) string {
+		return req.Method + " " + req.URL.Host + req.URL.EscapedPath()
+	}
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodPatch && req.Method != http.MethodDelete {
+					return next.Do(req)
+				}
+				body, err := readRequestBody(req)
+				if err != nil {
+					return nil, err
+				}
+				m.Lock()
+				override := rejected[key(req)]
+				m.Unlock()
+				if !override {
+					res, err := next.Do(req)
+					if err != nil || (res.StatusCode != http.StatusMethodNotAllowed && res.StatusCode != http.StatusNotImplemented) {
+						return res, err
+					}
+					io.Copy(io.Discard, res.Body)
+					res.Body.Close()
+				}
+				post := req.Clone(req.Context())
+				post.Method = http.MethodPost
+				post.Header.Set("X-HTTP-Method-Override", req.Method)
+				setRequestBody(post, body)
+				res, err := next.Do(post)
+				if err != nil {
+					return res, err
+				}
+				m.Lock()
+				if res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusNotImplemented {
+					delete(rejected, key(req))
+				} else {
+					rejected[key(req)] = true
+				}
+				m.Unlock()
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithMethodOverride(t *testing.T) {
+	var received []string
+	// The proxy only allows GET and POST requests.
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method+" "+r.Header.Get("X-HTTP-Method-Override"))
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var m map[string]any
+		json.NewDecoder(r.Body).Decode(&m)
+		respond.WithJSON(w, m, http.StatusOK)
+	})
+	opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithMethodOverride()}
+
+	t.Run("rejected methods are resent as POST", func(t *testing.T) {
+		received = nil
+		resp, err := jsonapi.Patch[map[string]any, map[string]any](context.Background(), "https://example.com/items/1", map[string]any{"name": "a"}, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "a"}, resp); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"PATCH ", "POST PATCH"}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("rejected methods are sent as POST for later requests", func(t *testing.T) {
+		received = nil
+		if _, err := jsonapi.Patch[map[string]any, map[string]any](context.Background(), "https://example.com/items/1", map[string]any{"name": "b"}, opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]string{"POST PATCH"}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("rejections are remembered per path", func(t *testing.T) {
+		received = nil
+		if _, err := jsonapi.Patch[map[string]any, map[string]any](context.Background(), "https://example.com/items/2", map[string]any{"name": "c"}, opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]string{"PATCH ", "POST PATCH"}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("rejections are not remembered if the override is rejected", func(t *testing.T) {
+		var received []string
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = append(received, r.Method+" "+r.Header.Get("X-HTTP-Method-Override"))
+			http.Error(w, "not implemented", http.StatusNotImplemented)
+		})
+		opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithMethodOverride()}
+		for range 2 {
+			if _, err := jsonapi.Delete[map[string]any](context.Background(), "https://example.com/items/1", opts...); err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+		}
+		if diff := cmp.Diff([]string{"DELETE ", "POST DELETE", "DELETE ", "POST DELETE"}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("other methods are not overridden", func(t *testing.T) {
+		received = nil
+		_, err := jsonapi.Put[map[string]any, map[string]any](context.Background(), "https://example.com/items/1", map[string]any{}, opts...)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if diff := cmp.Diff([]string{"PUT "}, received); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -29,6 +29,18 @@ func WithAuthorization(authorization string) Opt {
 	return WithRequestHeader("Authorization", authorization)
 }
 
+// WithCallAuthorization sets the Authorization header for a single call, e.g. to act on behalf
+// of a specific user, overriding the Authorization header set by other middleware. Middleware
+// added by WithAuthMiddleware is skipped, so that no token is fetched.
+//
+//	opts := append(clientOpts, jsonapi.WithCallAuthorization("Bearer "+userToken))
+func WithCallAuthorization(authorization string) Opt {
+	return func(c *Config) error {
+		c.CallAuthorization = authorization
+		return nil
+	}
+}
+
 func WithContentType(contentType string) Opt {
 	return WithRequestHeader("Content-Type", contentType)
 }
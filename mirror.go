@@ -0,0 +1,105 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithMirror asynchronously sends a copy of a sample of requests to a secondary base URL,
+// e.g. to validate a new backend with production traffic before cutting over. sampleRate is
+// the fraction of requests that are mirrored, between 0 and 1. Responses to mirrored requests
+// don't affect the primary request, and are discarded, or compared with the primary response
+// if WithMirrorComparison is used.
+//
+// The scheme and host of the request URL are replaced with those of the secondary base URL,
+// and its path, if any, is prepended to the request path. Mirrored requests include the headers
+// set by middleware, such as Authorization, and are sent through the interceptors added after
+// WithMirror, retries and the Doer set by WithClient. Transport guards, such as
+// WithAllowedHosts, apply to mirrored requests too. All methods are mirrored, so a secondary
+// backend that receives POST, PUT, PATCH or DELETE requests must not have side effects on
+// shared data.
+func WithMirror(secondaryBaseURL string, sampleRate float64) Opt {
+	return func(c *Config) error {
+		base, err := url.Parse(secondaryBaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse mirror base URL: %w", err)
+		}
+		if sampleRate < 0 || sampleRate > 1 {
+			return fmt.Errorf("mirror sample rate must be between 0 and 1, got %v", sampleRate)
+		}
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if sampleRate == 0 || rand.Float64() >= sampleRate {
+					return next.Do(req)
+				}
+				body, err := readRequestBody(req)
+				if err != nil {
+					return nil, err
+				}
+				mirror := req.Clone(context.WithoutCancel(req.Context()))
+				mirror.URL = rebaseURL(base, req.URL)
+				mirror.Host = ""
+				setRequestBody(mirror, body)
+				cmp := c.MirrorComparison
+				primary := make(chan mirroredResponse, 1)
+				go func() {
+					if err := c.checkTransport(mirror); err != nil {
+						return
+					}
+					res, err := next.Do(mirror)
+					if err != nil {
+						return
+					}
+					defer res.Body.Close()
+					if cmp == nil {
+						io.Copy(io.Discard, res.Body)
+						return
+					}
+					shadowBody, err := io.ReadAll(res.Body)
+					if err != nil {
+						return
+					}
+					if p, ok := <-primary; ok {
+						cmp.compare(req, p, mirroredResponse{status: res.StatusCode, body: shadowBody})
+					}
+				}()
+				res, err := next.Do(req)
+				if err != nil || cmp == nil {
+					close(primary)
+					return res, err
+				}
+				primaryBody, err := io.ReadAll(res.Body)
+				res.Body.Close()
+				if err != nil {
+					close(primary)
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				res.Body = io.NopCloser(bytes.NewReader(primaryBody))
+				primary <- mirroredResponse{status: res.StatusCode, body: primaryBody}
+				close(primary)
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
+
+// rebaseURL returns u with the scheme and host of base, and the path of base prepended.
+func rebaseURL(base, u *url.URL) *url.URL {
+	rebased := *u
+	rebased.Scheme = base.Scheme
+	rebased.Host = base.Host
+	if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" {
+		rebased.Path = prefix + u.Path
+		if u.RawPath != "" {
+			rebased.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + u.RawPath
+		}
+	}
+	return &rebased
+}
@@ -0,0 +1,165 @@
+package jsonapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mirroredRequest struct {
+	URL           string
+	Authorization string
+	Body          string
+}
+
+func TestWithMirror(t *testing.T) {
+	mirrored := make(chan mirroredRequest, 10)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host == "shadow.example.com" {
+			body, _ := io.ReadAll(r.Body)
+			mirrored <- mirroredRequest{URL: r.URL.String(), Authorization: r.Header.Get("Authorization"), Body: string(body)}
+			respond.WithError(w, "shadow failure", http.StatusInternalServerError)
+			return
+		}
+		r.URL.Path = "/auth" + r.URL.Path
+		createTestRoutes().ServeHTTP(w, r)
+	})
+	client := jsonapi.WithClient(testClient{Handler: h})
+
+	t.Run("sampled requests are mirrored to the secondary base URL", func(t *testing.T) {
+		m := map[string]any{"key": "value"}
+		resp, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "http://api.example.com/items/post/ok?id=1", m,
+			client, jsonapi.WithAuthorization("Bearer abc"), jsonapi.WithMirror("http://shadow.example.com/v2", 1))
+		if err != nil {
+			t.Fatalf("expected the shadow failure to be ignored, got %v", err)
+		}
+		if resp["key"] != "value" {
+			t.Errorf("expected the primary response, got %v", resp)
+		}
+		select {
+		case r := <-mirrored:
+			expected := mirroredRequest{URL: "http://shadow.example.com/v2/items/post/ok?id=1", Authorization: "Bearer abc", Body: `{"key":"value"}`}
+			if r != expected {
+				t.Errorf("expected %+v, got %+v", expected, r)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the mirrored request")
+		}
+	})
+	t.Run("requests are not mirrored with a zero sample rate", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://api.example.com/items/get/ok",
+			client, jsonapi.WithAuthorization("Bearer abc"), jsonapi.WithMirror("http://shadow.example.com", 0))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		select {
+		case r := <-mirrored:
+			t.Errorf("expected no mirrored requests, got %+v", r)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+	t.Run("mirrored requests are sent through the later interceptors", func(t *testing.T) {
+		hosts := make(chan string, 2)
+		record := jsonapi.WithInterceptor(func(next jsonapi.Doer) jsonapi.Doer {
+			return jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				hosts <- req.URL.Host
+				return next.Do(req)
+			})
+		})
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://api.example.com/items/get/ok",
+			client, jsonapi.WithAuthorization("Bearer abc"), jsonapi.WithMirror("http://shadow.example.com", 1), record)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		seen := map[string]bool{}
+		for range 2 {
+			select {
+			case host := <-hosts:
+				seen[host] = true
+			case <-time.After(time.Second):
+			}
+		}
+		<-mirrored
+		if !seen["api.example.com"] || !seen["shadow.example.com"] {
+			t.Errorf("expected both requests to be intercepted, got %v", seen)
+		}
+	})
+	t.Run("mirrored requests are checked by transport guards", func(t *testing.T) {
+		hosts := make(chan string, 2)
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hosts <- r.Host
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		}))
+		defer s.Close()
+		u, _ := url.Parse(s.URL)
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithClient(&http.Client{Transport: &http.Transport{}}), jsonapi.WithAllowedHosts("127.0.0.1"),
+			jsonapi.WithMirror("http://localhost:"+u.Port(), 1))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if host := <-hosts; host != u.Host {
+			t.Errorf("expected the primary request, got %q", host)
+		}
+		select {
+		case host := <-hosts:
+			t.Errorf("expected the mirrored request to be blocked, got a request to %q", host)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+	t.Run("invalid sample rates are rejected", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://api.example.com/items/get/ok", client, jsonapi.WithMirror("http://shadow.example.com", 2))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
+
+func TestWithMirrorComparison(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host == "shadow.example.com" {
+			respond.WithJSON(w, map[string]any{"id": "b", "items": []any{map[string]any{"sku": "a", "updated": 2}}, "total": 2}, http.StatusOK)
+			return
+		}
+		respond.WithJSON(w, map[string]any{"id": "a", "items": []any{map[string]any{"sku": "a", "updated": 1}, map[string]any{"sku": "b"}}, "total": 2}, http.StatusOK)
+	})
+	mismatches := make(chan jsonapi.MirrorMismatch, 1)
+	resp, _, err := jsonapi.Get[map[string]any](context.Background(), "http://api.example.com/orders/1",
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithMirror("http://shadow.example.com", 1),
+		jsonapi.WithMirrorComparison(jsonapi.MirrorComparison{
+			Ignore:     []string{"/id", "/items/*/updated"},
+			OnMismatch: func(m jsonapi.MirrorMismatch) { mismatches <- m },
+		}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp["id"] != "a" {
+		t.Errorf("expected the primary response, got %v", resp)
+	}
+	select {
+	case m := <-mismatches:
+		expected := jsonapi.MirrorMismatch{
+			Method:        http.MethodGet,
+			URL:           "http://api.example.com/orders/1",
+			PrimaryStatus: http.StatusOK,
+			ShadowStatus:  http.StatusOK,
+			Differences: []jsonapi.JSONDifference{
+				{Pointer: "/items/1", Primary: map[string]any{"sku": "b"}},
+			},
+		}
+		if diff := cmp.Diff(expected, m); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the mismatch")
+	}
+}
@@ -0,0 +1,147 @@
+package jsonapi
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MirrorComparison compares the responses to requests mirrored by WithMirror, so that parity
+// between the primary and secondary backends can be verified automatically.
+type MirrorComparison struct {
+	// Ignore lists JSON pointers of values that are expected to differ, e.g. "/id" or
+	// "/items/*/updated", where * matches any object key or array index.
+	Ignore []string
+	// OnMismatch is called when the status or body of the responses differ. It's called from
+	// a separate goroutine after both responses have been received.
+	OnMismatch func(m MirrorMismatch)
+}
+
+// MirrorMismatch describes the differences between a primary and mirrored response.
+type MirrorMismatch struct {
+	Method        string `json:"method"`
+	URL           string `json:"url"`
+	PrimaryStatus int    `json:"primaryStatus"`
+	ShadowStatus  int    `json:"shadowStatus"`
+	// Differences are the values that differ, in JSON pointer order. If either body is not
+	// valid JSON, a single difference with an empty pointer compares the bodies as strings.
+	Differences []JSONDifference `json:"differences"`
+}
+
+// JSONDifference is a value that differs between two JSON documents. Missing values are nil.
+type JSONDifference struct {
+	Pointer string `json:"pointer"`
+	Primary any    `json:"primary"`
+	Shadow  any    `json:"shadow"`
+}
+
+// WithMirrorComparison compares the responses to requests mirrored by WithMirror. Primary
+// response bodies are buffered so that they can be compared.
+func WithMirrorComparison(cmp MirrorComparison) Opt {
+	return func(c *Config) error {
+		c.MirrorComparison = &cmp
+		return nil
+	}
+}
+
+type mirroredResponse struct {
+	status int
+	body   []byte
+}
+
+func (cmp *MirrorComparison) compare(req *http.Request, primary, shadow mirroredResponse) {
+	if cmp.OnMismatch == nil {
+		return
+	}
+	m := MirrorMismatch{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		PrimaryStatus: primary.status,
+		ShadowStatus:  shadow.status,
+	}
+	p, perr := decodeJSONTree(primary.body)
+	s, serr := decodeJSONTree(shadow.body)
+	if perr != nil || serr != nil {
+		if !bytes.Equal(primary.body, shadow.body) {
+			m.Differences = []JSONDifference{{Primary: string(primary.body), Shadow: string(shadow.body)}}
+		}
+	} else {
+		ignore := make([][]string, len(cmp.Ignore))
+		for i, pointer := range cmp.Ignore {
+			ignore[i] = jsonPointerTokens(pointer)
+		}
+		m.Differences = diffJSON(nil, p, s, ignore, nil)
+	}
+	if m.PrimaryStatus == m.ShadowStatus && len(m.Differences) == 0 {
+		return
+	}
+	safely(func() error { cmp.OnMismatch(m); return nil })
+}
+
+// diffJSON appends the differences between the JSON values a and b at the path to diffs.
+func diffJSON(path []string, a, b any, ignore [][]string, diffs []JSONDifference) []JSONDifference {
+	for _, pattern := range ignore {
+		if matchPointer(pattern, path) {
+			return diffs
+		}
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffs = diffJSON(append(path[:len(path):len(path)], k), av[k], bv[k], ignore, diffs)
+		}
+		return diffs
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(av), len(bv)) {
+			var ai, bi any
+			if i < len(av) {
+				ai = av[i]
+			}
+			if i < len(bv) {
+				bi = bv[i]
+			}
+			diffs = diffJSON(append(path[:len(path):len(path)], strconv.Itoa(i)), ai, bi, ignore, diffs)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(a, b) {
+		return diffs
+	}
+	return append(diffs, JSONDifference{Pointer: formatPointer(path), Primary: a, Shadow: b})
+}
+
+// matchPointer returns true if the path matches the pattern, where * matches any token.
+func matchPointer(pattern, path []string) bool {
+	return slices.EqualFunc(pattern, path, func(p, t string) bool { return p == "*" || p == t })
+}
+
+func formatPointer(path []string) string {
+	var sb strings.Builder
+	for _, token := range path {
+		sb.WriteString("/")
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// multiStatusItem is an item of a bulk response, containing its own status and body.
+type multiStatusItem struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// PostMultiStatus posts a request to a bulk endpoint, and decodes the per-item results of a
+// 207 Multi-Status, or other successful, response. The response body must be a JSON array of
+// objects with a status and a body, e.g. [{"status":201,"body":{...}},{"status":409,"body":{...}}].
+// Use WithUnwrap if the array is nested within the response.
+//
+// Items with a 2xx status are decoded into Result.Value. Other items have a Result.Err, which
+// is an InvalidStatusError, or a more specific error such as PreconditionFailedError. An error
+// is only returned if the whole request failed.
+func PostMultiStatus[TReq, T any](ctx context.Context, url string, request TReq, opts ...Opt) (results []Result[T], err error) {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	buf, err := config.marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return nil, err
+	}
+	var items []multiStatusItem
+	if err = decodeResponseInto(config, res, &items); err != nil {
+		return nil, err
+	}
+	results = make([]Result[T], len(items))
+	for i, item := range items {
+		if item.Status < 200 || item.Status > 299 {
+			results[i].Err = config.newStatusError(item.Status, nil, config.errorBody(item.Body))
+			continue
+		}
+		if len(item.Body) == 0 {
+			continue
+		}
+		if err = config.unmarshalJSON(item.Body, &results[i].Value); err != nil {
+			results[i].Err = InvalidJSONError{
+				Status: item.Status,
+				Body:   config.errorBody(item.Body),
+				Err:    err,
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,45 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type bulkItem struct {
+	ID string `json:"id"`
+}
+
+func TestPostMultiStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[
+			{"status":201,"body":{"id":"1"}},
+			{"status":409,"body":{"message":"duplicate"}},
+			{"status":200,"body":{"id":2}}
+		]}`))
+	})
+	results, err := jsonapi.PostMultiStatus[[]bulkItem, bulkItem](context.Background(), "/items/bulk", []bulkItem{{ID: "1"}, {ID: "1"}, {ID: "2"}},
+		jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithUnwrap("/results"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Value.ID != "1" {
+		t.Errorf("expected the first item to succeed, got %+v", results[0])
+	}
+	var ise jsonapi.InvalidStatusError
+	if !errors.As(results[1].Err, &ise) || ise.Status != http.StatusConflict || ise.Body != `{"message":"duplicate"}` {
+		t.Errorf("expected a 409 InvalidStatusError, got %v", results[1].Err)
+	}
+	var ije jsonapi.InvalidJSONError
+	if !errors.As(results[2].Err, &ije) {
+		t.Errorf("expected InvalidJSONError, got %v", results[2].Err)
+	}
+}
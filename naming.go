@@ -0,0 +1,49 @@
+package jsonapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldNaming converts a Go struct field name to a JSON object key.
+type FieldNaming func(goName string) string
+
+// WithFieldNaming sets the naming strategy for struct fields that don't have a json tag,
+// e.g. WithFieldNaming(SnakeCase) encodes a FirstName field as "first_name", and decodes
+// "first_name" into the FirstName field. Fields with a json tag are not renamed.
+func WithFieldNaming(naming FieldNaming) Opt {
+	return func(c *Config) error {
+		c.FieldNaming = naming
+		return nil
+	}
+}
+
+// SnakeCase converts a Go field name to snake_case, e.g. UserID becomes user_id.
+func SnakeCase(goName string) string {
+	return strings.Join(splitWords(goName), "_")
+}
+
+// CamelCase converts a Go field name to camelCase, e.g. UserID becomes userId.
+func CamelCase(goName string) string {
+	words := splitWords(goName)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go identifier into lower case words, keeping initialisms together,
+// e.g. HTTPServerID becomes http, server, id.
+func splitWords(s string) (words []string) {
+	runes := []rune(s)
+	var start int
+	for i := 1; i < len(runes); i++ {
+		prev, curr := runes[i-1], runes[i]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(curr) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)) {
+			words = append(words, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	return append(words, strings.ToLower(string(runes[start:])))
+}
@@ -0,0 +1,58 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFieldNamingFuncs(t *testing.T) {
+	tests := []struct {
+		input, snake, camel string
+	}{
+		{input: "FirstName", snake: "first_name", camel: "firstName"},
+		{input: "UserID", snake: "user_id", camel: "userId"},
+		{input: "HTTPServer", snake: "http_server", camel: "httpServer"},
+		{input: "Address2Line", snake: "address2_line", camel: "address2Line"},
+		{input: "ID", snake: "id", camel: "id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if actual := jsonapi.SnakeCase(tt.input); actual != tt.snake {
+				t.Errorf("expected snake case %q, got %q", tt.snake, actual)
+			}
+			if actual := jsonapi.CamelCase(tt.input); actual != tt.camel {
+				t.Errorf("expected camel case %q, got %q", tt.camel, actual)
+			}
+		})
+	}
+}
+
+type namedUser struct {
+	FirstName string
+	UserID    int
+	Tagged    string `json:"TAGGED"`
+	Address   namedAddress
+}
+
+type namedAddress struct {
+	PostCode string
+}
+
+func TestWithFieldNaming(t *testing.T) {
+	var received string
+	user := namedUser{FirstName: "Alice", UserID: 1, Tagged: "x", Address: namedAddress{PostCode: "AB1"}}
+	resp, err := jsonapi.Post[namedUser, namedUser](context.Background(), "/users", user, jsonapi.WithClient(echoClient(&received)), jsonapi.WithFieldNaming(jsonapi.SnakeCase))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := `{"TAGGED":"x","address":{"post_code":"AB1"},"first_name":"Alice","user_id":1}`
+	if received != expected {
+		t.Errorf("expected %s, got %s", expected, received)
+	}
+	if diff := cmp.Diff(user, resp); diff != "" {
+		t.Error(diff)
+	}
+}
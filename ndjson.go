@@ -0,0 +1,48 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"reflect"
+)
+
+// isNDJSONContentType returns true for newline delimited JSON, also known as JSON Lines.
+func isNDJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return true
+	}
+	return false
+}
+
+func isSlicePointer(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice
+}
+
+// unmarshalNDJSON decodes each line of the body into an element of the slice that v points to.
+func unmarshalNDJSON(body []byte, v any, unmarshal func(data []byte, v any) error) error {
+	slice := reflect.ValueOf(v).Elem()
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var line json.RawMessage
+		err := dec.Decode(&line)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err = unmarshal(line, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+}
@@ -0,0 +1,28 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNDJSONResponses(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("{\"id\":\"1\",\"status\":\"new\"}\n{\"id\":\"2\",\"status\":\"shipped\"}\n"))
+	})
+	resp, ok, err := jsonapi.Get[[]orderUpdated](context.Background(), "/orders", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithStrictContentType())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !ok {
+		t.Error("expected ok to be true")
+	}
+	expected := []orderUpdated{{ID: "1", Status: "new"}, {ID: "2", Status: "shipped"}}
+	if diff := cmp.Diff(expected, resp); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,44 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithNonce sets a unique nonce, and the current Unix time in seconds, on each request,
+// for APIs that reject replayed requests. If a header name is empty, the header is not set.
+//
+// Middleware runs in the order it's added, so add WithNonce before any middleware that
+// signs requests, so that the signature covers the nonce and timestamp. Retries of a request
+// reuse its nonce and timestamp.
+func WithNonce(nonceHeader, timestampHeader string) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, &nonceMiddleware{
+			nonceHeader:     nonceHeader,
+			timestampHeader: timestampHeader,
+			now:             time.Now,
+		})
+		return nil
+	}
+}
+
+type nonceMiddleware struct {
+	nonceHeader     string
+	timestampHeader string
+	now             func() time.Time
+}
+
+func (m *nonceMiddleware) Request(req *http.Request) error {
+	if m.nonceHeader != "" {
+		req.Header.Set(m.nonceHeader, newID())
+	}
+	if m.timestampHeader != "" {
+		req.Header.Set(m.timestampHeader, strconv.FormatInt(m.now().Unix(), 10))
+	}
+	return nil
+}
+
+func (m *nonceMiddleware) Response(res *http.Response) error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithNonce(t *testing.T) {
+	nonces := map[string]bool{}
+	var timestamp string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces[r.Header.Get("X-Nonce")] = true
+		timestamp = r.Header.Get("X-Timestamp")
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithNonce("X-Nonce", "X-Timestamp"),
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(nonces) != 2 || nonces[""] {
+		t.Errorf("expected 2 unique nonces, got %v", nonces)
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("expected a Unix timestamp, got %q", timestamp)
+	}
+	if d := time.Since(time.Unix(ts, 0)); d < 0 || d > time.Minute {
+		t.Errorf("expected a current timestamp, got %v", time.Unix(ts, 0))
+	}
+}
@@ -0,0 +1,96 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PresignedUpload is a pre-signed object storage URL returned by an API, used to upload a
+// large payload directly to object storage.
+type PresignedUpload struct {
+	URL string `json:"url"`
+	// Method is the HTTP method of the upload. Defaults to PUT.
+	Method string `json:"method,omitempty"`
+	// Headers must be sent with the upload, e.g. headers included in the signature.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Key identifies the uploaded object in the metadata request.
+	Key string `json:"key,omitempty"`
+}
+
+// Offload sends a large payload to object storage using a pre-signed URL, and then posts JSON
+// metadata that references it to the API. If the upload or metadata request fails, Rollback is
+// called so that the uploaded object can be deleted.
+type Offload[TReq, TResp any] struct {
+	// PresignURL is the API endpoint that returns a PresignedUpload in response to PresignRequest.
+	PresignURL     string
+	PresignRequest any
+	// URL is the API endpoint that receives the metadata.
+	URL string
+	// Metadata returns the metadata request that references the upload.
+	Metadata func(upload PresignedUpload) TReq
+	// Rollback is called if the upload or metadata request fails, e.g. to ask the API to
+	// delete the object. Its error is joined with the error of the failed request.
+	Rollback func(ctx context.Context, upload PresignedUpload) error
+	// Opts are applied to the API requests.
+	Opts []Opt
+	// UploadOpts are applied to the upload request. The upload is authorized by the
+	// pre-signed URL, so Opts are not applied, to avoid sending API credentials to object storage.
+	UploadOpts []Opt
+}
+
+// Do uploads size bytes of body with the given Content-Type, then posts the metadata.
+func (o Offload[TReq, TResp]) Do(ctx context.Context, body io.Reader, size int64, contentType string) (response TResp, err error) {
+	upload, err := Post[any, PresignedUpload](ctx, o.PresignURL, o.PresignRequest, o.Opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to get pre-signed upload URL: %w", err)
+	}
+	defer func() {
+		if err == nil || o.Rollback == nil {
+			return
+		}
+		if rerr := safely(func() error { return o.Rollback(context.WithoutCancel(ctx), upload) }); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to roll back upload: %w", rerr))
+		}
+	}()
+	if err = o.upload(ctx, upload, body, size, contentType); err != nil {
+		return response, err
+	}
+	response, err = Post[TReq, TResp](ctx, o.URL, o.Metadata(upload), o.Opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to post metadata: %w", err)
+	}
+	return response, nil
+}
+
+func (o Offload[TReq, TResp]) upload(ctx context.Context, upload PresignedUpload, body io.Reader, size int64, contentType string) error {
+	method := upload.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+	req, err := http.NewRequestWithContext(ctx, method, upload.URL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = size
+	opts := append([]Opt{WithContentType(contentType)}, o.UploadOpts...)
+	for k, v := range upload.Headers {
+		opts = append(opts, WithRequestHeader(k, v))
+	}
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("failed to upload: %w", config.newStatusError(res.StatusCode, res.Header, config.readErrorBody(res.Body)))
+	}
+	io.Copy(io.Discard, res.Body)
+	return nil
+}
@@ -0,0 +1,97 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+type reportMetadata struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+func TestOffload(t *testing.T) {
+	newHandler := func(metadataStatus int) (http.Handler, *string, *string) {
+		var stored, authorization string
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /uploads", func(w http.ResponseWriter, r *http.Request) {
+			respond.WithJSON(w, jsonapi.PresignedUpload{
+				URL:     "http://storage.example.com/bucket/abc?signature=xyz",
+				Headers: map[string]string{"X-Amz-Meta-Owner": "1"},
+				Key:     "abc",
+			}, http.StatusOK)
+		})
+		mux.HandleFunc("PUT storage.example.com/bucket/abc", func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			if r.Header.Get("X-Amz-Meta-Owner") != "1" || r.Header.Get("Content-Type") != "text/csv" {
+				http.Error(w, "invalid headers", http.StatusForbidden)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			stored = string(body)
+		})
+		mux.HandleFunc("POST /reports", func(w http.ResponseWriter, r *http.Request) {
+			var m reportMetadata
+			json.NewDecoder(r.Body).Decode(&m)
+			respond.WithJSON(w, m, metadataStatus)
+		})
+		return mux, &stored, &authorization
+	}
+	newOffload := func(h http.Handler, rolledBack *string) jsonapi.Offload[reportMetadata, reportMetadata] {
+		return jsonapi.Offload[reportMetadata, reportMetadata]{
+			PresignURL:     "http://api.example.com/uploads",
+			PresignRequest: map[string]any{"contentType": "text/csv"},
+			URL:            "http://api.example.com/reports",
+			Metadata: func(upload jsonapi.PresignedUpload) reportMetadata {
+				return reportMetadata{Name: "report.csv", Key: upload.Key}
+			},
+			Rollback: func(ctx context.Context, upload jsonapi.PresignedUpload) error {
+				*rolledBack = upload.Key
+				return nil
+			},
+			Opts:       []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithAuthorization("Bearer abc")},
+			UploadOpts: []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h})},
+		}
+	}
+
+	t.Run("the payload is uploaded and the metadata posted", func(t *testing.T) {
+		h, stored, authorization := newHandler(http.StatusCreated)
+		var rolledBack string
+		resp, err := newOffload(h, &rolledBack).Do(context.Background(), strings.NewReader("a,b\n1,2\n"), 8, "text/csv")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.Key != "abc" {
+			t.Errorf("expected the metadata to reference the upload, got %+v", resp)
+		}
+		if *stored != "a,b\n1,2\n" {
+			t.Errorf("unexpected stored payload %q", *stored)
+		}
+		if *authorization != "" {
+			t.Errorf("expected API credentials not to be sent to storage, got %q", *authorization)
+		}
+		if rolledBack != "" {
+			t.Errorf("expected no rollback, got %q", rolledBack)
+		}
+	})
+	t.Run("the upload is rolled back if the metadata request fails", func(t *testing.T) {
+		h, _, _ := newHandler(http.StatusUnprocessableEntity)
+		var rolledBack string
+		_, err := newOffload(h, &rolledBack).Do(context.Background(), strings.NewReader("a,b\n"), 4, "text/csv")
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) {
+			t.Fatalf("expected InvalidStatusError, got %v", err)
+		}
+		if rolledBack != "abc" {
+			t.Errorf("expected rollback, got %q", rolledBack)
+		}
+	})
+}
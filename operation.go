@@ -0,0 +1,60 @@
+package jsonapi
+
+import "context"
+
+const (
+	operationMetadataKey = "operation"
+	routeMetadataKey     = "route"
+)
+
+// WithOperationName sets the logical name of the call, e.g. "GetOrder", so that logs and
+// metrics can be aggregated by operation rather than by concrete URL. The name is added to
+// the call metadata of the request context, and can be read by middleware and interceptors
+// using OperationName. It's included in audit events and deprecation notices.
+//
+// If no operation name is set, but a route is set using WithRoute, the operation name is the
+// method and route, e.g. "GET /orders/{id}".
+func WithOperationName(name string) Opt {
+	return func(c *Config) error {
+		c.OperationName = name
+		return nil
+	}
+}
+
+// OperationName returns the operation name of the call, set by WithOperationName, or by
+// WithCallMetadata using the "operation" key.
+func OperationName(ctx context.Context) string {
+	return CallMetadata(ctx)[operationMetadataKey]
+}
+
+// WithRoute sets the route template of the call, e.g. "/orders/{id}", so that metrics and
+// traces can be labelled with the route instead of the concrete URL, which would create a
+// label value for every order. The concrete URL is still used for the request. The route
+// can be read from the request context using Route.
+func WithRoute(template string) Opt {
+	return func(c *Config) error {
+		c.Route = template
+		return nil
+	}
+}
+
+// Route returns the route template of the call, set by WithRoute.
+func Route(ctx context.Context) string {
+	return CallMetadata(ctx)[routeMetadataKey]
+}
+
+// callMetadata returns the metadata added to the request context, or nil if there is none.
+func (config *Config) callMetadata(method string) map[string]string {
+	if config.OperationName == "" && config.Route == "" {
+		return nil
+	}
+	m := make(map[string]string, 2)
+	if config.Route != "" {
+		m[routeMetadataKey] = config.Route
+		m[operationMetadataKey] = method + " " + config.Route
+	}
+	if config.OperationName != "" {
+		m[operationMetadataKey] = config.OperationName
+	}
+	return m
+}
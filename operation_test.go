@@ -0,0 +1,84 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithOperationName(t *testing.T) {
+	var operation string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		createTestRoutes().ServeHTTP(w, r)
+	})
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithDeprecationNotice(func(n jsonapi.DeprecationNotice) { operation = n.Operation }),
+	}
+
+	t.Run("the operation name is available to interceptors", func(t *testing.T) {
+		operation = ""
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", append(opts, jsonapi.WithOperationName("ListItems"))...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if operation != "ListItems" {
+			t.Errorf("expected operation ListItems, got %q", operation)
+		}
+	})
+	t.Run("the operation name can be set in the call metadata", func(t *testing.T) {
+		operation = ""
+		ctx := jsonapi.WithCallMetadata(context.Background(), map[string]string{"operation": "GetItems"})
+		if _, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items/get/ok", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if operation != "GetItems" {
+			t.Errorf("expected operation GetItems, got %q", operation)
+		}
+	})
+}
+
+func TestWithRoute(t *testing.T) {
+	var route, operation, path string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		createTestRoutes().ServeHTTP(w, r)
+	})
+	interceptor := func(next jsonapi.Doer) jsonapi.Doer {
+		return jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			route, operation = jsonapi.Route(req.Context()), jsonapi.OperationName(req.Context())
+			return next.Do(req)
+		})
+	}
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithInterceptor(interceptor),
+		jsonapi.WithRoute("/items/get/{status}"),
+	}
+
+	t.Run("the operation name is derived from the route", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if path != "/items/get/ok" {
+			t.Errorf("expected the concrete URL to be requested, got %q", path)
+		}
+		if route != "/items/get/{status}" {
+			t.Errorf("unexpected route %q", route)
+		}
+		if operation != "GET /items/get/{status}" {
+			t.Errorf("unexpected operation %q", operation)
+		}
+	})
+	t.Run("the operation name takes precedence", func(t *testing.T) {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", append(opts, jsonapi.WithOperationName("GetItems"))...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if operation != "GetItems" {
+			t.Errorf("unexpected operation %q", operation)
+		}
+	})
+}
@@ -0,0 +1,454 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutboxEntry is a request that was persisted because the upstream was unreachable.
+type OutboxEntry struct {
+	// ID is used as the idempotency key when the request is replayed.
+	ID      string      `json:"id"`
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Created time.Time   `json:"created"`
+}
+
+// OutboxStore persists outbox entries. List must return entries in the order they were added.
+type OutboxStore interface {
+	Add(ctx context.Context, e OutboxEntry) error
+	List(ctx context.Context) ([]OutboxEntry, error)
+	Remove(ctx context.Context, id string) error
+}
+
+// QueuedError is returned when a request could not be sent and was added to the outbox instead.
+type QueuedError struct {
+	ID  string `json:"id"`
+	Err error  `json:"error"`
+}
+
+func (e QueuedError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("request queued in outbox as %s", e.ID)
+	}
+	return fmt.Sprintf("request queued in outbox as %s: %v", e.ID, e.Err)
+}
+
+func (e QueuedError) Unwrap() error {
+	return e.Err
+}
+
+// NewOutbox creates an outbox that persists requests to the store.
+func NewOutbox(store OutboxStore) *Outbox {
+	return &Outbox{
+		Store:                store,
+		IdempotencyKeyHeader: "Idempotency-Key",
+		m:                    &sync.Mutex{},
+	}
+}
+
+// Outbox persists POST, PUT and PATCH requests made while the upstream is unreachable,
+// so that they can be replayed in order once connectivity returns.
+type Outbox struct {
+	Store OutboxStore
+	// IdempotencyKeyHeader is set on each mutating request, so that the upstream can
+	// discard duplicates if a request was received before the connection failed.
+	IdempotencyKeyHeader string
+	// m guards listed and pending, which track whether the store has entries, so that the
+	// store doesn't need to be listed for each request.
+	m       *sync.Mutex
+	listed  bool
+	pending bool
+}
+
+// WithOutbox queues mutating requests in the outbox if the upstream can't be reached.
+// While the outbox has pending entries, new mutating requests are queued behind them to
+// preserve ordering. Queued requests return a QueuedError.
+//
+// The store is listed once, by the first mutating request, after which the outbox keeps
+// track of whether it has pending entries. Entries added to the store by another process, or
+// by Enqueue, are not queued behind.
+//
+// Requests with a streamed body, such as those sent by PostStream, are sent without using the
+// outbox, since the body would need to be held in memory to be persisted.
+func WithOutbox(o *Outbox) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return o.intercept(next, c.clock())
+		})
+		return nil
+	}
+}
+
+type outboxReplayContextKey struct{}
+
+func (o *Outbox) intercept(next Doer, clock Clock) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if !isMutatingMethod(req.Method) || isStreamingBody(req) || req.Context().Value(outboxReplayContextKey{}) != nil {
+			return next.Do(req)
+		}
+		e, err := o.newEntry(req, clock.Now())
+		if err != nil {
+			return nil, err
+		}
+		pending, err := o.hasPending(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, o.queue(req.Context(), e, nil)
+		}
+		res, err := next.Do(req)
+		if err != nil && isUnreachable(err) {
+			return nil, o.queue(req.Context(), e, err)
+		}
+		return res, err
+	})
+}
+
+func (o *Outbox) newEntry(req *http.Request, now time.Time) (e OutboxEntry, err error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return e, err
+	}
+	id := req.Header.Get(o.IdempotencyKeyHeader)
+	if id == "" {
+		id = newID()
+		if o.IdempotencyKeyHeader != "" {
+			req.Header.Set(o.IdempotencyKeyHeader, id)
+		}
+	}
+	// Credentials are not persisted, they're added by middleware when the entry is replayed.
+	header := req.Header.Clone()
+	header.Del("Authorization")
+	return OutboxEntry{
+		ID:      id,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Header:  header,
+		Body:    body,
+		Created: now,
+	}, nil
+}
+
+func (o *Outbox) hasPending(ctx context.Context) (bool, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	if !o.listed {
+		entries, err := o.Store.List(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to list outbox: %w", err)
+		}
+		o.listed, o.pending = true, len(entries) > 0
+	}
+	return o.pending, nil
+}
+
+func (o *Outbox) queue(ctx context.Context, e OutboxEntry, cause error) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+	if err := o.Store.Add(context.WithoutCancel(ctx), e); err != nil {
+		return fmt.Errorf("failed to add request to outbox: %w", err)
+	}
+	o.pending = true
+	return QueuedError{ID: e.ID, Err: cause}
+}
+
+// Replay sends pending outbox entries in order using the given options.
+//
+// Replay stops at the first entry that can't be delivered, keeping it in the outbox, because
+// the upstream is unreachable, or returns a 5xx, 408 Request Timeout, 429 Too Many Requests,
+// 401 Unauthorized or 403 Forbidden status, since these may succeed later, e.g. once
+// credentials are refreshed. The error returned for a 429 is a TooManyRequestsError, with the
+// delay requested by the upstream. Entries that receive any other response are removed.
+func (o *Outbox) Replay(ctx context.Context, opts ...Opt) (sent int, err error) {
+	entries, err := o.Store.List(ctx)
+	if err != nil {
+		return sent, fmt.Errorf("failed to list outbox: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			err = o.refreshPending(ctx)
+		}
+	}()
+	for _, e := range entries {
+		req, err := http.NewRequestWithContext(context.WithValue(ctx, outboxReplayContextKey{}, e.ID), e.Method, e.URL, bytes.NewReader(e.Body))
+		if err != nil {
+			return sent, fmt.Errorf("failed to create request for outbox entry %s: %w", e.ID, err)
+		}
+		req.Header = e.Header.Clone()
+		config, err := newConfig(opts...)
+		if err != nil {
+			return sent, fmt.Errorf("failed to create config: %w", err)
+		}
+		res, err := config.raw(req)
+		if err != nil {
+			return sent, fmt.Errorf("failed to replay outbox entry %s: %w", e.ID, err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+		res.Body.Close()
+		if isRetainedStatus(res.StatusCode) {
+			return sent, fmt.Errorf("failed to replay outbox entry %s: %w", e.ID, config.newStatusError(res.StatusCode, res.Header, config.redactBody(body)))
+		}
+		if err = o.Store.Remove(ctx, e.ID); err != nil {
+			return sent, fmt.Errorf("failed to remove outbox entry %s: %w", e.ID, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// isRetainedStatus returns true if an entry that received the status should be kept in the
+// outbox, because a later attempt may succeed.
+func isRetainedStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	return status >= 500
+}
+
+// refreshPending lists the store after a replay, so that new mutating requests are no longer
+// queued once the outbox is empty.
+func (o *Outbox) refreshPending(ctx context.Context) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+	entries, err := o.Store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list outbox: %w", err)
+	}
+	o.listed, o.pending = true, len(entries) > 0
+	return nil
+}
+
+// Enqueue creates an outbox entry for a request, and calls persist to store it, so that a
+// critical write can be saved in the same database transaction as the change that caused it,
+// and sent later by Relay or Replay. persist must write to the storage read by the outbox's
+// Store. The entry ID is sent as the idempotency key, so that the upstream can discard
+// duplicates if an entry is sent more than once. The request is encoded using the opts, but
+// middleware is not run until the entry is sent.
+func (o *Outbox) Enqueue(ctx context.Context, method, url string, request any, persist func(ctx context.Context, e OutboxEntry) error, opts ...Opt) (e OutboxEntry, err error) {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return e, fmt.Errorf("failed to create config: %w", err)
+	}
+	body, err := config.marshal(request)
+	if err != nil {
+		return e, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	e = OutboxEntry{
+		ID:      newID(),
+		Method:  method,
+		URL:     url,
+		Header:  http.Header{},
+		Body:    body,
+		Created: config.clock().Now(),
+	}
+	if o.IdempotencyKeyHeader != "" {
+		e.Header.Set(o.IdempotencyKeyHeader, e.ID)
+	}
+	if err = persist(ctx, e); err != nil {
+		return e, fmt.Errorf("failed to persist outbox entry: %w", err)
+	}
+	return e, nil
+}
+
+// Relay replays the outbox every interval until the context is cancelled, and returns the
+// context's error. Replay errors are passed to onError, if not nil, and retried at the next
+// interval, or after the delay requested by a 429 Too Many Requests response, if longer.
+// The interval is waited for using the Clock set by WithClock.
+func (o *Outbox) Relay(ctx context.Context, interval time.Duration, onError func(err error), opts ...Opt) error {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	for {
+		wait := interval
+		if _, err := o.Replay(ctx, opts...); err != nil {
+			if onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+			var tmr TooManyRequestsError
+			if errors.As(err, &tmr) && tmr.RetryAfter > wait {
+				wait = tmr.RetryAfter
+			}
+		}
+		if err := config.clock().Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// isUnreachable returns true if the error is a failure to dial, resolve or reach the host
+// in time, rather than a cancellation, a TLS failure, or a request rejected by policy.
+func isUnreachable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || isPolicyError(err) {
+		return false
+	}
+	switch ClassifyTransportError(err) {
+	case TransportErrorCertificate, TransportErrorTLS:
+		return false
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var ne net.Error
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || (errors.As(err, &ne) && ne.Timeout())
+}
+
+// isPolicyError returns true if the request was rejected by the client, e.g. by WithAllowedHosts,
+// WithBlockPrivateNetworks or WithPinnedCertificates, so repeating it won't help.
+func isPolicyError(err error) bool {
+	var fhe ForbiddenHostError
+	var pce PinnedCertificateError
+	return errors.As(err, &fhe) || errors.As(err, &pce) || errors.Is(err, ErrProxyNotAllowed) || errors.Is(err, ErrClosed)
+}
+
+// isStreamingBody returns true if the request has a body that can only be read once, e.g.
+// the pipe used by PostStream.
+func isStreamingBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.GetBody == nil
+}
+
+func readRequestBody(req *http.Request) (body []byte, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get request body: %w", err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	body, err = io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewMemoryOutboxStore creates an OutboxStore that holds entries in memory.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{m: &sync.Mutex{}}
+}
+
+// MemoryOutboxStore is an OutboxStore that holds entries in memory. It is not durable.
+type MemoryOutboxStore struct {
+	entries []OutboxEntry
+	m       *sync.Mutex
+}
+
+func (s *MemoryOutboxStore) Add(ctx context.Context, e OutboxEntry) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *MemoryOutboxStore) List(ctx context.Context) ([]OutboxEntry, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]OutboxEntry(nil), s.entries...), nil
+}
+
+func (s *MemoryOutboxStore) Remove(ctx context.Context, id string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i, e := range s.entries {
+		if e.ID == id {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// NewFileOutboxStore creates an OutboxStore that persists each entry as a JSON file in dir.
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	return &FileOutboxStore{Dir: dir}, nil
+}
+
+// FileOutboxStore is an OutboxStore that persists each entry as a JSON file in a directory.
+type FileOutboxStore struct {
+	Dir string
+}
+
+func (s *FileOutboxStore) Add(ctx context.Context, e OutboxEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+	name := filepath.Join(s.Dir, fmt.Sprintf("%020d-%s.json", e.Created.UnixNano(), e.ID))
+	if err = os.WriteFile(name+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+	return os.Rename(name+".tmp", name)
+}
+
+func (s *FileOutboxStore) List(ctx context.Context) (entries []OutboxEntry, err error) {
+	names, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox entries: %w", err)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox entry: %w", err)
+		}
+		var e OutboxEntry
+		if err = json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox entry %q: %w", name, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (s *FileOutboxStore) Remove(ctx context.Context, id string) error {
+	names, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list outbox entries: %w", err)
+	}
+	for _, name := range names {
+		if strings.HasSuffix(name, "-"+id+".json") {
+			return os.Remove(name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type offlineClient struct {
+	Offline bool
+	Client  jsonapi.Doer
+}
+
+func (c *offlineClient) Do(req *http.Request) (*http.Response, error) {
+	if c.Offline {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return c.Client.Do(req)
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	store, err := jsonapi.NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	outbox := jsonapi.NewOutbox(store)
+
+	var received []string
+	routes := createTestRoutes()
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Idempotency-Key"))
+		routes.ServeHTTP(w, r)
+	})
+	client := &offlineClient{Offline: true, Client: testClient{Handler: h}}
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(client),
+		jsonapi.WithOutbox(outbox),
+		jsonapi.WithAuthorization("Bearer abc"),
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/auth/items/post/ok", map[string]any{"i": i}, opts...)
+		var qe jsonapi.QueuedError
+		if !errors.As(err, &qe) {
+			t.Fatalf("expected QueuedError, got %v", err)
+		}
+	}
+	entries, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Header.Get("Authorization") != "" {
+		t.Error("expected credentials not to be persisted")
+	}
+
+	t.Run("replay fails while offline", func(t *testing.T) {
+		sent, err := outbox.Replay(ctx, opts...)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if sent != 0 {
+			t.Errorf("expected 0 sent, got %d", sent)
+		}
+	})
+	t.Run("replay sends entries in order once online", func(t *testing.T) {
+		client.Offline = false
+		sent, err := outbox.Replay(ctx, opts...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if sent != 2 {
+			t.Errorf("expected 2 sent, got %d", sent)
+		}
+		if len(received) != 2 || received[0] != entries[0].ID || received[1] != entries[1].ID {
+			t.Errorf("expected idempotency keys %q and %q, got %v", entries[0].ID, entries[1].ID, received)
+		}
+		remaining, _ := store.List(ctx)
+		if len(remaining) != 0 {
+			t.Errorf("expected the outbox to be empty, got %d entries", len(remaining))
+		}
+	})
+}
+
+type countingOutboxStore struct {
+	*jsonapi.MemoryOutboxStore
+	lists int
+}
+
+func (s *countingOutboxStore) List(ctx context.Context) ([]jsonapi.OutboxEntry, error) {
+	s.lists++
+	return s.MemoryOutboxStore.List(ctx)
+}
+
+func TestOutboxReplayStatus(t *testing.T) {
+	ctx := context.Background()
+	store := &countingOutboxStore{MemoryOutboxStore: jsonapi.NewMemoryOutboxStore()}
+	outbox := jsonapi.NewOutbox(store)
+	client := &offlineClient{Offline: true}
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(client),
+		jsonapi.WithOutbox(outbox),
+	}
+	if _, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/items", map[string]any{}, opts...); err == nil {
+		t.Fatal("expected the request to be queued")
+	}
+
+	tests := []struct {
+		status int
+		header http.Header
+		kept   bool
+	}{
+		{status: http.StatusServiceUnavailable, kept: true},
+		{status: http.StatusRequestTimeout, kept: true},
+		{status: http.StatusUnauthorized, kept: true},
+		{status: http.StatusForbidden, kept: true},
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": []string{"120"}}, kept: true},
+		{status: http.StatusBadRequest, kept: false},
+	}
+	for _, test := range tests {
+		client.Offline = false
+		client.Client = testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range test.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(test.status)
+		})}
+		_, err := outbox.Replay(ctx, opts...)
+		entries, _ := store.MemoryOutboxStore.List(ctx)
+		if kept := len(entries) == 1; kept != test.kept {
+			t.Errorf("status %d: expected kept=%v, got %v", test.status, test.kept, kept)
+		}
+		if test.status == http.StatusTooManyRequests {
+			var tmr jsonapi.TooManyRequestsError
+			if !errors.As(err, &tmr) || tmr.RetryAfter != 2*time.Minute {
+				t.Errorf("expected TooManyRequestsError with a 2m delay, got %v", err)
+			}
+		}
+	}
+
+	t.Run("the store isn't listed for each request", func(t *testing.T) {
+		client.Client = testClient{Handler: createTestRoutes()}
+		lists := store.lists
+		for i := 0; i < 3; i++ {
+			if _, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/items/post/ok", map[string]any{}, opts...); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if store.lists != lists {
+			t.Errorf("expected no calls to List, got %d", store.lists-lists)
+		}
+	})
+}
+
+func TestOutboxEnqueue(t *testing.T) {
+	store, err := jsonapi.NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	outbox := jsonapi.NewOutbox(store)
+
+	// persist would normally write to the caller's database in a transaction.
+	var rollback bool
+	persist := func(ctx context.Context, e jsonapi.OutboxEntry) error {
+		if rollback {
+			return errors.New("transaction rolled back")
+		}
+		return store.Add(ctx, e)
+	}
+	e, err := outbox.Enqueue(context.Background(), http.MethodPost, "/auth/items/post/ok", map[string]any{"i": 1}, persist)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rollback = true
+	if _, err = outbox.Enqueue(context.Background(), http.MethodPost, "/auth/items/post/ok", map[string]any{"i": 2}, persist); err == nil {
+		t.Fatal("expected the persist error, got nil")
+	}
+
+	received := make(chan string, 1)
+	routes := createTestRoutes()
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("Idempotency-Key")
+		routes.ServeHTTP(w, r)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- outbox.Relay(ctx, time.Millisecond, func(err error) { t.Errorf("unexpected relay error: %v", err) },
+			jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithAuthorization("Bearer abc"))
+	}()
+	select {
+	case key := <-received:
+		if key != e.ID {
+			t.Errorf("expected idempotency key %q, got %q", e.ID, key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the entry to be relayed")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list entries: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected the outbox to be empty, got %d entries", len(entries))
+	}
+}
+
+func TestOutboxStreamingBodies(t *testing.T) {
+	store := jsonapi.NewMemoryOutboxStore()
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(&offlineClient{Offline: true}),
+		jsonapi.WithOutbox(jsonapi.NewOutbox(store)),
+	}
+	items := func(yield func(int) bool) {
+		for i := range 3 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	_, err := jsonapi.PostStream[int, map[string]any](context.Background(), "/ingest", items, opts...)
+	var qe jsonapi.QueuedError
+	if err == nil || errors.As(err, &qe) {
+		t.Errorf("expected the network error to be returned without queueing, got %v", err)
+	}
+	if entries, _ := store.List(context.Background()); len(entries) != 0 {
+		t.Errorf("expected the outbox to be empty, got %d entries", len(entries))
+	}
+}
+
+func TestOutboxRelayClock(t *testing.T) {
+	store := jsonapi.NewMemoryOutboxStore()
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	outbox := jsonapi.NewOutbox(store)
+	e, err := outbox.Enqueue(context.Background(), http.MethodPost, "/items", map[string]any{"i": 1}, store.Add, jsonapi.WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !e.Created.Equal(clock.now) {
+		t.Errorf("expected the entry to be created at %v, got %v", clock.now, e.Created)
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	relayClock := &cancellingClock{fakeClock: fakeClock{now: clock.now}, after: 2, cancel: cancel}
+	err = outbox.Relay(ctx, time.Minute, nil, jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithClock(relayClock))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if diff := cmp.Diff([]time.Duration{2 * time.Minute, 2 * time.Minute}, relayClock.sleeps); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOutboxPolicyErrors(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+	tests := []struct {
+		name string
+		opts []jsonapi.Opt
+	}{
+		{
+			name: "private network",
+			opts: []jsonapi.Opt{jsonapi.WithClient(&http.Client{Transport: &http.Transport{}}), jsonapi.WithBlockPrivateNetworks()},
+		},
+		{
+			name: "allowed hosts",
+			opts: []jsonapi.Opt{jsonapi.WithAllowedHosts("api.example.com")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := jsonapi.NewFileOutboxStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+			opts := append([]jsonapi.Opt{jsonapi.WithOutbox(jsonapi.NewOutbox(store))}, tt.opts...)
+			_, err = jsonapi.Post[map[string]any, map[string]any](context.Background(), s.URL, map[string]any{}, opts...)
+			var fhe jsonapi.ForbiddenHostError
+			if !errors.As(err, &fhe) {
+				t.Fatalf("expected ForbiddenHostError, got %v", err)
+			}
+			var qe jsonapi.QueuedError
+			if errors.As(err, &qe) {
+				t.Error("expected the request not to be queued")
+			}
+			entries, err := store.List(context.Background())
+			if err != nil {
+				t.Fatalf("failed to list entries: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("expected no entries, got %d", len(entries))
+			}
+		})
+	}
+}
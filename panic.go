@@ -0,0 +1,32 @@
+package jsonapi
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned when middleware, an interceptor hook or an event handler panics.
+type PanicError struct {
+	Value any    `json:"value"`
+	Stack []byte `json:"stack"`
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// safely calls f, converting any panic into a PanicError.
+func safely(f func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,36 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type panickingMiddleware struct{}
+
+func (panickingMiddleware) Request(req *http.Request) error {
+	panic("buggy middleware")
+}
+
+func (panickingMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+func TestMiddlewarePanics(t *testing.T) {
+	_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithMiddleware(panickingMiddleware{}))
+	var pe jsonapi.PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected PanicError, got %v", err)
+	}
+	if pe.Value != "buggy middleware" {
+		t.Errorf("unexpected panic value %v", pe.Value)
+	}
+	if len(pe.Stack) == 0 {
+		t.Error("expected a stack trace")
+	}
+}
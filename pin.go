@@ -0,0 +1,89 @@
+package jsonapi
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// PinnedCertificateError is returned when none of the server's verified certificates match a pinned hash.
+type PinnedCertificateError struct {
+	ServerName string `json:"serverName"`
+	// Hashes are the SPKI hashes of the certificates in the server's verified chains.
+	Hashes []string `json:"hashes"`
+}
+
+func (e PinnedCertificateError) Error() string {
+	return fmt.Sprintf("no verified certificate of %q matches a pinned hash, verified: %s", e.ServerName, strings.Join(e.Hashes, ", "))
+}
+
+// SPKIHash returns the base64 encoded SHA-256 hash of the certificate's SubjectPublicKeyInfo,
+// the format used by WithPinnedCertificates.
+func SPKIHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// WithPinnedCertificates fails the TLS handshake with a PinnedCertificateError unless one of the
+// certificates in the server's verified chains has one of the given SPKI hashes, see SPKIHash.
+// Certificates that the server presents, but that aren't part of a verified chain, are ignored,
+// since anyone can present a copy of a public certificate. Pinning is in addition to the usual
+// certificate verification, so if verification is skipped, e.g. with InsecureSkipVerify, no pins
+// match.
+//
+// The Opt returns an error if no hashes are given, or if the underlying Doer is not an
+// *http.Client with an *http.Transport, and requests fail if a later option, such as WithClient,
+// replaces the client.
+func WithPinnedCertificates(spkiHashes ...string) Opt {
+	if len(spkiHashes) == 0 {
+		return func(c *Config) error {
+			return errors.New("WithPinnedCertificates requires at least one SPKI hash")
+		}
+	}
+	pins := make(map[string]bool, len(spkiHashes))
+	for _, h := range spkiHashes {
+		pins[h] = true
+	}
+	return withTransportGuard(transportGuard{name: "WithPinnedCertificates"}, func(t *http.Transport) error {
+		addVerifyConnection(t.TLSClientConfig, func(cs tls.ConnectionState) error {
+			var verified []string
+			for _, chain := range cs.VerifiedChains {
+				for _, cert := range chain {
+					hash := SPKIHash(cert)
+					if pins[hash] {
+						return nil
+					}
+					if !slices.Contains(verified, hash) {
+						verified = append(verified, hash)
+					}
+				}
+			}
+			return PinnedCertificateError{
+				ServerName: cs.ServerName,
+				Hashes:     verified,
+			}
+		})
+		return nil
+	})
+}
+
+// addVerifyConnection adds f to the config's VerifyConnection, after any existing function.
+func addVerifyConnection(config *tls.Config, f func(cs tls.ConnectionState) error) {
+	previous := config.VerifyConnection
+	if previous == nil {
+		config.VerifyConnection = f
+		return
+	}
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if err := previous(cs); err != nil {
+			return err
+		}
+		return f(cs)
+	}
+}
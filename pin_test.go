@@ -0,0 +1,128 @@
+package jsonapi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithPinnedCertificates(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+
+	t.Run("matching pins succeed", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithRootCAs(pool),
+			jsonapi.WithPinnedCertificates("other", jsonapi.SPKIHash(s.Certificate())))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+	t.Run("mismatched pins fail", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithRootCAs(pool),
+			jsonapi.WithPinnedCertificates("other"))
+		var pce jsonapi.PinnedCertificateError
+		if !errors.As(err, &pce) {
+			t.Fatalf("expected PinnedCertificateError, got %v", err)
+		}
+		if len(pce.Hashes) == 0 || pce.Hashes[0] != jsonapi.SPKIHash(s.Certificate()) {
+			t.Errorf("expected the presented hash, got %v", pce.Hashes)
+		}
+	})
+	t.Run("the Opt fails if the client isn't an *http.Client", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+			jsonapi.WithPinnedCertificates(jsonapi.SPKIHash(s.Certificate())))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("the Opt fails if the transport is wrapped", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithRoundTripperMiddleware(func(next http.RoundTripper) http.RoundTripper { return next }),
+			jsonapi.WithPinnedCertificates(jsonapi.SPKIHash(s.Certificate())))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("requests fail if the client is replaced by a later option", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithPinnedCertificates("other"),
+			jsonapi.WithClient(&http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+		var pce jsonapi.PinnedCertificateError
+		if errors.As(err, &pce) {
+			t.Errorf("expected the request not to be sent, got %v", err)
+		}
+	})
+	t.Run("later transport options keep the pins", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithPinnedCertificates("other"),
+			jsonapi.WithRootCAs(pool),
+			jsonapi.WithRoundTripperMiddleware(func(next http.RoundTripper) http.RoundTripper { return next }))
+		var pce jsonapi.PinnedCertificateError
+		if !errors.As(err, &pce) {
+			t.Fatalf("expected PinnedCertificateError, got %v", err)
+		}
+	})
+	t.Run("unverified certificates presented by the server are ignored", func(t *testing.T) {
+		// The pinned certificate is public, so an attacker with any trusted certificate can
+		// append it to the chain they present.
+		pinnedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+		pinnedDER, err := x509.CreateCertificate(rand.Reader, template, template, &pinnedKey.PublicKey, pinnedKey)
+		if err != nil {
+			t.Fatalf("failed to create certificate: %v", err)
+		}
+		pinned, err := x509.ParseCertificate(pinnedDER)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+		cert := s.TLS.Certificates[0]
+		mitm := httptest.NewUnstartedServer(s.Config.Handler)
+		mitm.TLS = &tls.Config{Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cert.Certificate[0], pinnedDER},
+			PrivateKey:  cert.PrivateKey,
+		}}}
+		mitm.StartTLS()
+		defer mitm.Close()
+		_, _, err = jsonapi.Get[itemsGetResponse](context.Background(), mitm.URL,
+			jsonapi.WithRootCAs(pool),
+			jsonapi.WithPinnedCertificates(jsonapi.SPKIHash(pinned)))
+		var pce jsonapi.PinnedCertificateError
+		if !errors.As(err, &pce) {
+			t.Fatalf("expected PinnedCertificateError, got %v", err)
+		}
+	})
+	t.Run("the Opt fails if no hashes are given", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithRootCAs(pool),
+			jsonapi.WithPinnedCertificates())
+		if err == nil || !strings.Contains(err.Error(), "at least one") {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package presets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/a-h/jsonapi"
+)
+
+// GitHubError is the error envelope returned by the GitHub API, e.g.
+// {"message": "Bad credentials", "documentation_url": "https://docs.github.com/rest"}.
+// It wraps the error that would otherwise have been returned, such as a jsonapi.UnauthorizedError.
+type GitHubError struct {
+	Status           int    `json:"status"`
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentationURL,omitempty"`
+	Err              error  `json:"-"`
+}
+
+func (e GitHubError) Error() string {
+	return fmt.Sprintf("github: %s (status %d)", e.Message, e.Status)
+}
+
+func (e GitHubError) Unwrap() error {
+	return e.Err
+}
+
+func decodeGitHubError(err error) error {
+	var ise jsonapi.InvalidStatusError
+	if !errors.As(err, &ise) {
+		return err
+	}
+	var envelope struct {
+		Message          string `json:"message"`
+		DocumentationURL string `json:"documentation_url"`
+	}
+	if json.Unmarshal([]byte(ise.Body), &envelope) != nil || envelope.Message == "" {
+		return err
+	}
+	return GitHubError{
+		Status:           ise.Status,
+		Message:          envelope.Message,
+		DocumentationURL: envelope.DocumentationURL,
+		Err:              err,
+	}
+}
+
+// StripeError is the error envelope returned by the Stripe API, e.g.
+// {"error": {"type": "card_error", "code": "card_declined", "message": "Your card was declined."}}.
+// It wraps the error that would otherwise have been returned, such as a jsonapi.InvalidStatusError.
+type StripeError struct {
+	Status      int    `json:"status"`
+	Type        string `json:"type"`
+	Code        string `json:"code,omitempty"`
+	DeclineCode string `json:"declineCode,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Param       string `json:"param,omitempty"`
+	Err         error  `json:"-"`
+}
+
+func (e StripeError) Error() string {
+	return fmt.Sprintf("stripe: %s: %s (status %d)", e.Type, e.Message, e.Status)
+}
+
+func (e StripeError) Unwrap() error {
+	return e.Err
+}
+
+func decodeStripeError(err error) error {
+	var ise jsonapi.InvalidStatusError
+	if !errors.As(err, &ise) {
+		return err
+	}
+	var envelope struct {
+		Error *struct {
+			Type        string `json:"type"`
+			Code        string `json:"code"`
+			DeclineCode string `json:"decline_code"`
+			Message     string `json:"message"`
+			Param       string `json:"param"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(ise.Body), &envelope) != nil || envelope.Error == nil {
+		return err
+	}
+	return StripeError{
+		Status:      ise.Status,
+		Type:        envelope.Error.Type,
+		Code:        envelope.Error.Code,
+		DeclineCode: envelope.Error.DeclineCode,
+		Message:     envelope.Error.Message,
+		Param:       envelope.Error.Param,
+		Err:         err,
+	}
+}
+
+// SlackError is returned when the Slack Web API responds with a 2xx status, and an "ok" field
+// of false, e.g. {"ok": false, "error": "channel_not_found"}.
+type SlackError struct {
+	Status int `json:"status"`
+	// Code is the value of the "error" field, e.g. "channel_not_found".
+	Code string `json:"code"`
+	// Warning is the value of the "warning" field, if any.
+	Warning string `json:"warning,omitempty"`
+}
+
+func (e SlackError) Error() string {
+	return fmt.Sprintf("slack: %s", e.Code)
+}
+
+// slackErrors returns a SlackError for successful responses with an "ok" field of false.
+func slackErrors(next jsonapi.Doer) jsonapi.Doer {
+	return jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		res, err := next.Do(req)
+		if err != nil || res.StatusCode < 200 || res.StatusCode > 299 {
+			return res, err
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		var envelope struct {
+			OK      *bool  `json:"ok"`
+			Error   string `json:"error"`
+			Warning string `json:"warning"`
+		}
+		if json.Unmarshal(body, &envelope) == nil && envelope.OK != nil && !*envelope.OK {
+			return nil, SlackError{Status: res.StatusCode, Code: envelope.Error, Warning: envelope.Warning}
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		return res, nil
+	})
+}
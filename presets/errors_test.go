@@ -0,0 +1,83 @@
+package presets_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/jsonapi/presets"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func respondWith(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+func TestGitHubError(t *testing.T) {
+	h := respondWith(http.StatusUnauthorized, `{"message":"Bad credentials","documentation_url":"https://docs.github.com/rest"}`)
+	_, _, err := jsonapi.Get[map[string]any](context.Background(), "/user",
+		jsonapi.WithClient(testClient{Handler: h}), presets.GitHub("abc"))
+	var ge presets.GitHubError
+	if !errors.As(err, &ge) {
+		t.Fatalf("expected GitHubError, got %v", err)
+	}
+	expected := presets.GitHubError{Status: http.StatusUnauthorized, Message: "Bad credentials", DocumentationURL: "https://docs.github.com/rest"}
+	if diff := cmp.Diff(expected, ge, cmpopts.IgnoreFields(presets.GitHubError{}, "Err")); diff != "" {
+		t.Error(diff)
+	}
+	var ue jsonapi.UnauthorizedError
+	if !errors.As(err, &ue) {
+		t.Errorf("expected the error to wrap UnauthorizedError, got %v", err)
+	}
+}
+
+func TestStripeError(t *testing.T) {
+	h := respondWith(http.StatusPaymentRequired, `{"error":{"type":"card_error","code":"card_declined","decline_code":"insufficient_funds","message":"Your card was declined."}}`)
+	_, _, err := jsonapi.Get[map[string]any](context.Background(), "/v1/charges/ch_1",
+		jsonapi.WithClient(testClient{Handler: h}), presets.Stripe("sk_test", "2024-06-20"))
+	var se presets.StripeError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected StripeError, got %v", err)
+	}
+	expected := presets.StripeError{Status: http.StatusPaymentRequired, Type: "card_error", Code: "card_declined", DeclineCode: "insufficient_funds", Message: "Your card was declined."}
+	if diff := cmp.Diff(expected, se, cmpopts.IgnoreFields(presets.StripeError{}, "Err")); diff != "" {
+		t.Error(diff)
+	}
+	var ise jsonapi.InvalidStatusError
+	if !errors.As(err, &ise) || ise.Status != http.StatusPaymentRequired {
+		t.Errorf("expected the error to wrap InvalidStatusError, got %v", err)
+	}
+}
+
+func TestSlackError(t *testing.T) {
+	t.Run("ok false is returned as a SlackError", func(t *testing.T) {
+		h := respondWith(http.StatusOK, `{"ok":false,"error":"channel_not_found"}`)
+		_, _, err := jsonapi.Get[map[string]any](context.Background(), "/conversations.info",
+			jsonapi.WithClient(testClient{Handler: h}), presets.Slack("xoxb"))
+		var se presets.SlackError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected SlackError, got %v", err)
+		}
+		if diff := cmp.Diff(presets.SlackError{Status: http.StatusOK, Code: "channel_not_found"}, se); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("ok true responses are decoded", func(t *testing.T) {
+		h := respondWith(http.StatusOK, `{"ok":true,"channel":{"id":"C1"}}`)
+		res, _, err := jsonapi.Get[map[string]any](context.Background(), "/conversations.info",
+			jsonapi.WithClient(testClient{Handler: h}), presets.Slack("xoxb"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res["ok"] != true {
+			t.Errorf("expected the body to be decoded, got %v", res)
+		}
+	})
+}
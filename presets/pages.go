@@ -0,0 +1,142 @@
+package presets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/a-h/jsonapi"
+)
+
+// GitHubList returns each item of a GitHub list endpoint, e.g. "/user/repos", following the
+// "next" link of the Link header until the last page. Iteration stops after the first error.
+func GitHubList[T any](ctx context.Context, url string, opts ...jsonapi.Opt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for next := url; next != ""; {
+			capture := &headerCapture{}
+			items, _, err := jsonapi.Get[[]T](ctx, next, append(opts[:len(opts):len(opts)], jsonapi.WithMiddleware(capture))...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			next = nextLink(capture.header)
+		}
+	}
+}
+
+// StripeList returns each item of a Stripe list endpoint, e.g. "/v1/customers", requesting
+// the next page with the starting_after parameter while has_more is true. Iteration stops
+// after the first error.
+func StripeList[T any](ctx context.Context, url string, opts ...jsonapi.Opt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var startingAfter string
+		for {
+			var hasMore bool
+			var ids []struct {
+				ID string `json:"id"`
+			}
+			pageOpts := append(opts[:len(opts):len(opts)],
+				jsonapi.WithUnwrap("data"),
+				jsonapi.WithUnwrapMeta("has_more", &hasMore),
+				jsonapi.WithUnwrapMeta("data", &ids))
+			if startingAfter != "" {
+				pageOpts = append(pageOpts, jsonapi.WithQuery("starting_after", startingAfter))
+			}
+			items, _, err := jsonapi.Get[[]T](ctx, url, pageOpts...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !hasMore || len(ids) == 0 {
+				return
+			}
+			startingAfter = ids[len(ids)-1].ID
+		}
+	}
+}
+
+// SlackList returns each item of the field of a Slack Web API method, e.g. the "channels"
+// of "/conversations.list", requesting the next page with the cursor parameter while the
+// response_metadata contains a next_cursor. Iteration stops after the first error.
+func SlackList[T any](ctx context.Context, method, field string, opts ...jsonapi.Opt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var cursor string
+		for {
+			var body bytes.Buffer
+			pageOpts := append(opts[:len(opts):len(opts)], jsonapi.WithUnwrap(field), jsonapi.WithResponseTee(&body))
+			if cursor != "" {
+				pageOpts = append(pageOpts, jsonapi.WithQuery("cursor", cursor))
+			}
+			items, _, err := jsonapi.Get[[]T](ctx, method, pageOpts...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			var page struct {
+				ResponseMetadata struct {
+					NextCursor string `json:"next_cursor"`
+				} `json:"response_metadata"`
+			}
+			json.Unmarshal(body.Bytes(), &page)
+			if cursor = page.ResponseMetadata.NextCursor; cursor == "" {
+				return
+			}
+		}
+	}
+}
+
+// headerCapture is middleware that stores the headers of the response.
+type headerCapture struct {
+	header http.Header
+}
+
+func (c *headerCapture) Request(req *http.Request) error {
+	return nil
+}
+
+func (c *headerCapture) Response(res *http.Response) error {
+	c.header = res.Header
+	return nil
+}
+
+// nextLink returns the target of the "next" link in a Link header, e.g.
+// `<https://api.github.com/user/repos?page=2>; rel="next"`, or an empty string.
+func nextLink(h http.Header) string {
+	for _, v := range h.Values("Link") {
+		for _, link := range strings.Split(v, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(key, "rel") && slices.Contains(strings.Fields(strings.Trim(value, `"`)), "next") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package presets_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/jsonapi/presets"
+	"github.com/google/go-cmp/cmp"
+)
+
+type item struct {
+	ID string `json:"id"`
+}
+
+func collect[T any](t *testing.T, seq func(func(T, error) bool)) (items []T) {
+	t.Helper()
+	for item, err := range seq {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestGitHubList(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/user/repos?page=2>; rel="next", <%s/user/repos?page=2>; rel="last"`, presets.GitHubBaseURL, presets.GitHubBaseURL))
+			w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/user/repos?page=1>; rel="prev"`, presets.GitHubBaseURL))
+			w.Write([]byte(`[{"id":"3"}]`))
+		}
+	})
+	items := collect(t, presets.GitHubList[item](context.Background(), "/user/repos",
+		jsonapi.WithClient(testClient{Handler: h}), presets.GitHub("abc")))
+	if diff := cmp.Diff([]item{{"1"}, {"2"}, {"3"}}, items); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestStripeList(t *testing.T) {
+	var startingAfter []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startingAfter = append(startingAfter, r.URL.Query().Get("starting_after"))
+		switch r.URL.Query().Get("starting_after") {
+		case "":
+			w.Write([]byte(`{"object":"list","data":[{"id":"cus_1"},{"id":"cus_2"}],"has_more":true}`))
+		case "cus_2":
+			w.Write([]byte(`{"object":"list","data":[{"id":"cus_3"}],"has_more":false}`))
+		}
+	})
+	items := collect(t, presets.StripeList[item](context.Background(), "/v1/customers",
+		jsonapi.WithClient(testClient{Handler: h}), presets.Stripe("sk_test", "2024-06-20")))
+	if diff := cmp.Diff([]item{{"cus_1"}, {"cus_2"}, {"cus_3"}}, items); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"", "cus_2"}, startingAfter); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSlackList(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"ok":true,"channels":[{"id":"C1"}],"response_metadata":{"next_cursor":"abc"}}`))
+		case "abc":
+			w.Write([]byte(`{"ok":true,"channels":[{"id":"C2"}],"response_metadata":{"next_cursor":""}}`))
+		}
+	})
+	items := collect(t, presets.SlackList[item](context.Background(), "/conversations.list", "channels",
+		jsonapi.WithClient(testClient{Handler: h}), presets.Slack("xoxb")))
+	if diff := cmp.Diff([]item{{"C1"}, {"C2"}}, items); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	h := respondWith(http.StatusOK, `{"ok":false,"error":"invalid_auth"}`)
+	var calls int
+	for _, err := range presets.SlackList[item](context.Background(), "/conversations.list", "channels",
+		jsonapi.WithClient(testClient{Handler: h}), presets.Slack("xoxb")) {
+		calls++
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected iteration to stop after the error, got %d values", calls)
+	}
+}
@@ -0,0 +1,105 @@
+// Package presets provides options for popular APIs.
+//
+// Each preset returns a single Opt that sets the base URL, the authentication header, the
+// headers required by the API, a retry policy, and decodes the API's error envelope into an
+// error type, such as GitHubError. Presets that track rate limits hold the tracker in the Opt,
+// so create the Opt once and reuse it for each call.
+//
+//	github := presets.GitHub(os.Getenv("GITHUB_TOKEN"))
+//	user, _, err := jsonapi.Get[User](ctx, "/user", github)
+//
+// List endpoints are paged using the API's pagination style by GitHubList, StripeList and
+// SlackList.
+//
+//	for repo, err := range presets.GitHubList[Repo](ctx, "/user/repos", github) {
+//		...
+//	}
+package presets
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+const (
+	GitHubBaseURL = "https://api.github.com"
+	StripeBaseURL = "https://api.stripe.com"
+	SlackBaseURL  = "https://slack.com/api"
+)
+
+// GitHub API preset. Requests that exhaust the rate limit wait until it resets. Errors are
+// returned as a GitHubError.
+func GitHub(token string) jsonapi.Opt {
+	rateLimits := jsonapi.NewRateLimits()
+	rateLimits.Pace = true
+	return join(
+		jsonapi.WithBaseURL(GitHubBaseURL),
+		jsonapi.WithAuthorization("Bearer "+token),
+		jsonapi.WithRequestHeader("Accept", "application/vnd.github+json"),
+		jsonapi.WithRequestHeader("X-GitHub-Api-Version", "2022-11-28"),
+		jsonapi.WithRateLimits(rateLimits),
+		jsonapi.WithRetry(3, 500*time.Millisecond),
+		jsonapi.WithStatusErrorDecoder(decodeGitHubError),
+	)
+}
+
+// Stripe API preset. Stripe's v1 API only accepts form encoded request bodies,
+// so use the preset for GET requests to v1 endpoints, and for v2 endpoints.
+// Errors are returned as a StripeError.
+//
+// POST requests without an Idempotency-Key header are given a random key, which is reused
+// when the request is retried, so that a retry can't repeat a charge.
+func Stripe(secretKey, version string) jsonapi.Opt {
+	return join(
+		jsonapi.WithBaseURL(StripeBaseURL),
+		jsonapi.WithAuthorization("Bearer "+secretKey),
+		jsonapi.WithRequestHeader("Stripe-Version", version),
+		jsonapi.WithInterceptor(stripeIdempotencyKey),
+		jsonapi.WithRetry(3, 500*time.Millisecond),
+		jsonapi.WithStatusErrorDecoder(decodeStripeError),
+	)
+}
+
+// stripeIdempotencyKey sets the Idempotency-Key header of POST requests. Interceptors are
+// outside the retry policy, so each attempt of a request sends the same key.
+func stripeIdempotencyKey(next jsonapi.Doer) jsonapi.Doer {
+	return jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost && req.Header.Get("Idempotency-Key") == "" {
+			b := make([]byte, 16)
+			rand.Read(b)
+			req.Header.Set("Idempotency-Key", hex.EncodeToString(b))
+		}
+		return next.Do(req)
+	})
+}
+
+// Slack Web API preset. Slack returns errors with a 200 status code and an "ok" field of
+// false, which are returned as a SlackError.
+//
+// Slack doesn't support idempotency keys, so only GET and HEAD requests are retried, to avoid
+// repeating calls such as chat.postMessage.
+func Slack(token string) jsonapi.Opt {
+	return join(
+		jsonapi.WithBaseURL(SlackBaseURL),
+		jsonapi.WithAuthorization("Bearer "+token),
+		jsonapi.WithContentType("application/json; charset=utf-8"),
+		jsonapi.WithRetry(3, time.Second),
+		jsonapi.WithRetryMethods(http.MethodGet, http.MethodHead),
+		jsonapi.WithInterceptor(slackErrors),
+	)
+}
+
+func join(opts ...jsonapi.Opt) jsonapi.Opt {
+	return func(c *jsonapi.Config) error {
+		for _, o := range opts {
+			if err := o(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
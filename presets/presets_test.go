@@ -0,0 +1,97 @@
+package presets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/jsonapi/presets"
+)
+
+type testClient struct {
+	Handler http.Handler
+}
+
+func (c testClient) Do(req *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	c.Handler.ServeHTTP(w, req)
+	return w.Result(), nil
+}
+
+func TestGitHub(t *testing.T) {
+	var header http.Header
+	var url string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		url = r.URL.String()
+		w.Header().Set("X-RateLimit-Remaining", "10")
+		json.NewEncoder(w).Encode(map[string]string{"login": "a-h"})
+	})
+	github := presets.GitHub("abc")
+	_, _, err := jsonapi.Get[map[string]string](context.Background(), "/user",
+		jsonapi.WithClient(testClient{Handler: h}), github)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if url != presets.GitHubBaseURL+"/user" {
+		t.Errorf("expected the path to be resolved against the base URL, got %q", url)
+	}
+	expected := map[string]string{
+		"Authorization":        "Bearer abc",
+		"Accept":               "application/vnd.github+json",
+		"X-Github-Api-Version": "2022-11-28",
+	}
+	for k, v := range expected {
+		if header.Get(k) != v {
+			t.Errorf("expected %s header %q, got %q", k, v, header.Get(k))
+		}
+	}
+}
+
+func TestStripeIdempotencyKeys(t *testing.T) {
+	var keys []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"ch_1"}`))
+	})
+	post := func(opts ...jsonapi.Opt) {
+		opts = append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), presets.Stripe("sk_test", "2024-06-20"), jsonapi.WithRetry(3, 0)}, opts...)
+		if _, err := jsonapi.Post[map[string]any, map[string]any](context.Background(), "/v2/charges", map[string]any{}, opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	post()
+	post()
+	post(jsonapi.WithRequestHeader("Idempotency-Key", "abc"))
+	if len(keys) != 6 || keys[0] == "" || keys[0] != keys[1] || keys[2] != keys[3] || keys[0] == keys[2] {
+		t.Errorf("expected a new key for each request, reused by its retry, got %v", keys)
+	}
+	if keys[4] != "abc" || keys[5] != "abc" {
+		t.Errorf("expected the caller's key to be kept, got %v", keys[4:])
+	}
+}
+
+func TestSlackRetries(t *testing.T) {
+	for _, tt := range []struct {
+		method        string
+		expectedCalls int
+	}{{http.MethodGet, 3}, {http.MethodPost, 1}} {
+		var calls int
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		_, _ = jsonapi.Do[any, map[string]any](context.Background(), tt.method, "/chat.postMessage", nil,
+			jsonapi.WithClient(testClient{Handler: h}), presets.Slack("xoxb"), jsonapi.WithRetry(3, 0))
+		if calls != tt.expectedCalls {
+			t.Errorf("%s: expected %d calls, got %d", tt.method, tt.expectedCalls, calls)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+)
+
+// WithBlockPrivateNetworks rejects connections to non-public addresses with a ForbiddenHostError,
+// so that a client that builds URLs from user input can't be used to reach internal services,
+// including via redirects, or host names that resolve to internal addresses. See privatePrefixes
+// for the blocked ranges, which include loopback, private (RFC 1918 and RFC 4193), shared
+// (RFC 6598, used by some cloud metadata services), link-local and unspecified addresses, and
+// IPv6 ranges that embed IPv4 addresses, such as NAT64.
+//
+// Addresses are checked after the host name is resolved, and the connection is made to the
+// checked address, so that DNS can't be changed between the check and the connection.
+//
+// The allow list contains exceptions for legitimately internal APIs, as host names, or host
+// name patterns (see WithAllowedHosts), IP addresses, or CIDR prefixes, e.g. "10.1.0.0/16".
+// Requests that would be sent through a proxy, e.g. one set by the HTTPS_PROXY environment
+// variable, fail, since only the connection to the proxy could be checked. Use NO_PROXY to
+// exclude the hosts that the client connects to.
+//
+// The Opt returns an error if the underlying Doer is not an *http.Client with an *http.Transport,
+// and requests fail if a later option, such as WithClient, replaces the client.
+func WithBlockPrivateNetworks(allow ...string) Opt {
+	var hosts []string
+	var prefixes []netip.Prefix
+	for _, a := range allow {
+		if prefix, err := netip.ParsePrefix(a); err == nil {
+			prefixes = append(prefixes, prefix)
+			continue
+		}
+		if addr, err := netip.ParseAddr(a); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		hosts = append(hosts, a)
+	}
+	allowed := func(addr netip.Addr) bool {
+		if !isPrivateAddr(addr) {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+	guard := transportGuard{
+		name: "WithBlockPrivateNetworks",
+		check: func(req *http.Request, t *http.Transport) error {
+			return refuseProxy(t.Proxy, req)
+		},
+	}
+	return withTransportGuard(guard, func(t *http.Transport) error {
+		// Redirects are sent by the transport without calling the guard's check.
+		if proxy := t.Proxy; proxy != nil {
+			t.Proxy = func(req *http.Request) (*url.URL, error) {
+				if err := refuseProxy(proxy, req); err != nil {
+					return nil, err
+				}
+				return nil, nil
+			}
+		}
+		dial := t.DialContext
+		if dial == nil {
+			dial = dialContext
+		}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			if matchesHost(hosts, host) {
+				return dial(ctx, network, addr)
+			}
+			ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+			if err != nil {
+				return nil, err
+			}
+			for i, ip := range ips {
+				ips[i] = ip.Unmap()
+				if !allowed(ips[i]) {
+					return nil, ForbiddenHostError{Host: host, IP: ips[i].String()}
+				}
+			}
+			if len(ips) == 0 {
+				return nil, fmt.Errorf("no addresses found for host %q", host)
+			}
+			for _, ip := range ips {
+				var conn net.Conn
+				if conn, err = dial(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+					return conn, nil
+				}
+			}
+			return nil, err
+		}
+		return nil
+	})
+}
+
+// privatePrefixes are the address ranges blocked by WithBlockPrivateNetworks.
+var privatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network.
+	netip.MustParsePrefix("10.0.0.0/8"),      // Private.
+	netip.MustParsePrefix("100.64.0.0/10"),   // Shared address space, e.g. Alibaba Cloud's metadata service.
+	netip.MustParsePrefix("127.0.0.0/8"),     // Loopback.
+	netip.MustParsePrefix("169.254.0.0/16"),  // Link-local, e.g. AWS, Azure and GCP metadata services.
+	netip.MustParsePrefix("172.16.0.0/12"),   // Private.
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments.
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation.
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast.
+	netip.MustParsePrefix("192.168.0.0/16"),  // Private.
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking.
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation.
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation.
+	netip.MustParsePrefix("224.0.0.0/4"),     // Multicast.
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, and broadcast.
+	netip.MustParsePrefix("::/96"),           // Unspecified, loopback, and IPv4-compatible.
+	netip.MustParsePrefix("::ffff:0:0/96"),   // IPv4-mapped.
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64.
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64.
+	netip.MustParsePrefix("100::/64"),        // Discard-only.
+	netip.MustParsePrefix("2001::/32"),       // Teredo, which embeds an IPv4 address.
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation.
+	netip.MustParsePrefix("2002::/16"),       // 6to4, which embeds an IPv4 address.
+	netip.MustParsePrefix("fc00::/7"),        // Unique local.
+	netip.MustParsePrefix("fe80::/10"),       // Link-local.
+	netip.MustParsePrefix("fec0::/10"),       // Site-local.
+	netip.MustParsePrefix("ff00::/8"),        // Multicast.
+}
+
+// isPrivateAddr returns true if the address is in one of the privatePrefixes. IPv4-mapped IPv6
+// addresses are checked as IPv4 addresses.
+func isPrivateAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range privatePrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrProxyNotAllowed is returned when WithBlockPrivateNetworks is used, and a request would be
+// sent through a proxy.
+var ErrProxyNotAllowed = errors.New("requests can't be sent through a proxy when private networks are blocked, since only the connection to the proxy can be checked")
+
+func refuseProxy(proxy func(*http.Request) (*url.URL, error), req *http.Request) error {
+	if proxy == nil {
+		return nil
+	}
+	u, err := proxy(req)
+	if err != nil {
+		return err
+	}
+	if u != nil {
+		return fmt.Errorf("%w: %s", ErrProxyNotAllowed, u.Redacted())
+	}
+	return nil
+}
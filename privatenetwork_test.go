@@ -0,0 +1,133 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithBlockPrivateNetworks(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}))
+	defer s.Close()
+	_, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+
+	tests := []struct {
+		name    string
+		url     string
+		allow   []string
+		allowed bool
+	}{
+		{name: "loopback addresses are blocked", url: s.URL},
+		{name: "host names that resolve to loopback addresses are blocked", url: "http://localhost:" + port},
+		{name: "allowed addresses are not blocked", url: s.URL, allow: []string{"127.0.0.1"}, allowed: true},
+		{name: "allowed prefixes are not blocked", url: s.URL, allow: []string{"127.0.0.0/8"}, allowed: true},
+		{name: "allowed host names are not blocked", url: "http://localhost:" + port, allow: []string{"localhost"}, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), tt.url,
+				jsonapi.WithClient(&http.Client{}), jsonapi.WithBlockPrivateNetworks(tt.allow...))
+			var fhe jsonapi.ForbiddenHostError
+			if isForbidden := errors.As(err, &fhe); isForbidden == tt.allowed {
+				t.Errorf("expected allowed=%v, got error %v", tt.allowed, err)
+			}
+			if tt.allowed && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+	t.Run("non-public addresses are blocked", func(t *testing.T) {
+		for _, host := range []string{
+			"100.100.100.200",
+			"0.0.0.1",
+			"169.254.169.254",
+			"[::ffff:169.254.169.254]",
+			"[::ffff:a9fe:a9fe]",
+			"[64:ff9b::a9fe:a9fe]",
+			"[2002:a9fe:a9fe::1]",
+			"[fd00::1]",
+		} {
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://"+host+"/",
+				jsonapi.WithClient(&http.Client{Transport: &http.Transport{}}), jsonapi.WithBlockPrivateNetworks())
+			var fhe jsonapi.ForbiddenHostError
+			if !errors.As(err, &fhe) {
+				t.Errorf("%s: expected ForbiddenHostError, got %v", host, err)
+			}
+		}
+	})
+	t.Run("redirect targets are checked", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler(s.URL, http.StatusFound))
+		defer redirect.Close()
+		_, redirectPort, _ := net.SplitHostPort(redirect.Listener.Addr().String())
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://localhost:"+redirectPort,
+			jsonapi.WithClient(&http.Client{}), jsonapi.WithBlockPrivateNetworks("localhost"))
+		var fhe jsonapi.ForbiddenHostError
+		if !errors.As(err, &fhe) || fhe.IP != "127.0.0.1" {
+			t.Errorf("expected ForbiddenHostError for 127.0.0.1, got %v", err)
+		}
+	})
+	t.Run("the Opt fails if the client isn't an *http.Client", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://example.com",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithBlockPrivateNetworks())
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("requests fail if the client is replaced by a later option", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithBlockPrivateNetworks(), jsonapi.WithClient(&http.Client{}))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("requests through a proxy are refused", func(t *testing.T) {
+		var proxied bool
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = true
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		}))
+		defer proxy.Close()
+		proxyURL, _ := url.Parse(proxy.URL)
+		tests := []struct {
+			name string
+			opts []jsonapi.Opt
+		}{
+			{
+				name: "proxy set on the client",
+				opts: []jsonapi.Opt{
+					jsonapi.WithClient(&http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}),
+					jsonapi.WithBlockPrivateNetworks(),
+				},
+			},
+			{
+				name: "proxy set by a later option",
+				opts: []jsonapi.Opt{
+					jsonapi.WithClient(&http.Client{}),
+					jsonapi.WithBlockPrivateNetworks(),
+					jsonapi.WithProxy(proxyURL),
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				proxied = false
+				_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://api.example.com/items", tt.opts...)
+				if !errors.Is(err, jsonapi.ErrProxyNotAllowed) {
+					t.Errorf("expected ErrProxyNotAllowed, got %v", err)
+				}
+				if proxied {
+					t.Error("expected the request not to be sent to the proxy")
+				}
+			})
+		}
+	})
+}
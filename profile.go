@@ -0,0 +1,170 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Profiles are named client settings, e.g. for each environment, read from a JSON config file.
+//
+//	{
+//	  "production": {
+//	    "baseURL": "https://api.example.com/v1",
+//	    "timeout": "10s",
+//	    "bearerTokenEnv": "ORDERS_API_TOKEN",
+//	    "retry": {"attempts": 3, "backoff": "200ms"}
+//	  }
+//	}
+type Profiles map[string]Profile
+
+// Profile is a set of client settings. Credentials are not stored in the profile, instead
+// the profile names the environment variables that hold them.
+type Profile struct {
+	// BaseURL is the URL that relative request URLs are resolved against, as set by the
+	// BASE_URL environment variable of FromEnv, see WithBaseURL.
+	BaseURL string `json:"baseURL,omitempty"`
+	// Timeout is the overall request timeout, e.g. "30s".
+	Timeout string `json:"timeout,omitempty"`
+	// Proxy is the URL of a proxy server.
+	Proxy string `json:"proxy,omitempty"`
+	// CABundle is the path to a PEM file of CA certificates used to verify servers.
+	CABundle string `json:"caBundle,omitempty"`
+	// Headers are set on every request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// AuthorizationEnv is the name of the environment variable that holds the Authorization header value.
+	AuthorizationEnv string `json:"authorizationEnv,omitempty"`
+	// BearerTokenEnv is the name of the environment variable that holds a bearer token.
+	BearerTokenEnv string        `json:"bearerTokenEnv,omitempty"`
+	Retry          *ProfileRetry `json:"retry,omitempty"`
+}
+
+// ProfileRetry is the retry policy of a Profile, see WithRetry.
+type ProfileRetry struct {
+	Attempts   int    `json:"attempts"`
+	Backoff    string `json:"backoff,omitempty"`
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+// ReadProfiles reads profiles from JSON.
+func ReadProfiles(r io.Reader) (profiles Profiles, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// LoadProfiles reads profiles from a JSON file.
+func LoadProfiles(fileName string) (profiles Profiles, err error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profiles: %w", err)
+	}
+	defer f.Close()
+	return ReadProfiles(f)
+}
+
+// Opts returns the options of the named profile.
+func (p Profiles) Opts(name string) (opts []Opt, err error) {
+	profile, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	opts, err = profile.Opts()
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+	return opts, nil
+}
+
+// Client creates a Client that sends requests to the base URL of the named profile, using
+// the profile's options, which are applied after opts.
+func (p Profiles) Client(name string, opts ...Opt) (*Client, error) {
+	profileOpts, err := p.Opts(name)
+	if err != nil {
+		return nil, err
+	}
+	if p[name].BaseURL == "" {
+		return nil, fmt.Errorf("profile %q: baseURL is not set", name)
+	}
+	return NewClient(p[name].BaseURL, append(opts[:len(opts):len(opts)], profileOpts...)...)
+}
+
+// Opts returns the options of the profile. Since the timeout, proxy and CA bundle modify
+// the HTTP client, the returned options must be applied after WithClient.
+func (p Profile) Opts() (opts []Opt, err error) {
+	if p.BaseURL != "" {
+		opts = append(opts, WithBaseURL(p.BaseURL))
+	}
+	if p.Timeout != "" {
+		timeout, err := time.ParseDuration(p.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timeout: %w", err)
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+	if p.Proxy != "" {
+		proxyURL, err := url.Parse(p.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy: %w", err)
+		}
+		opts = append(opts, WithProxy(proxyURL))
+	}
+	if p.CABundle != "" {
+		opts = append(opts, WithCABundle(p.CABundle))
+	}
+	for k, v := range p.Headers {
+		opts = append(opts, WithRequestHeader(k, v))
+	}
+	if p.AuthorizationEnv != "" && p.BearerTokenEnv != "" {
+		return nil, fmt.Errorf("only one of authorizationEnv and bearerTokenEnv can be set")
+	}
+	if p.AuthorizationEnv != "" {
+		authorization := os.Getenv(p.AuthorizationEnv)
+		if authorization == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", p.AuthorizationEnv)
+		}
+		opts = append(opts, WithAuthorization(authorization))
+	}
+	if p.BearerTokenEnv != "" {
+		token := os.Getenv(p.BearerTokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", p.BearerTokenEnv)
+		}
+		opts = append(opts, WithAuthorization("Bearer "+token))
+	}
+	if p.Retry != nil {
+		retryOpts, err := p.Retry.opts()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, retryOpts...)
+	}
+	return opts, nil
+}
+
+func (r ProfileRetry) opts() (opts []Opt, err error) {
+	backoff := 100 * time.Millisecond
+	if r.Backoff != "" {
+		if backoff, err = time.ParseDuration(r.Backoff); err != nil {
+			return nil, fmt.Errorf("failed to parse retry backoff: %w", err)
+		}
+	}
+	opts = append(opts, WithRetry(r.Attempts, backoff))
+	if r.MaxBackoff != "" {
+		maxBackoff, err := time.ParseDuration(r.MaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse retry max backoff: %w", err)
+		}
+		opts = append(opts, func(c *Config) error {
+			c.Retry.MaxBackoff = maxBackoff
+			return nil
+		})
+	}
+	return opts, nil
+}
@@ -0,0 +1,88 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+const testProfiles = `{
+	"dev": {
+		"headers": {"X-Environment": "dev"}
+	},
+	"production": {
+		"baseURL": "https://api.example.com/v1",
+		"timeout": "10s",
+		"headers": {"X-Environment": "production"},
+		"bearerTokenEnv": "TEST_PROFILE_TOKEN",
+		"retry": {"attempts": 3, "backoff": "1ms"}
+	}
+}`
+
+func TestProfiles(t *testing.T) {
+	profiles, err := jsonapi.ReadProfiles(strings.NewReader(testProfiles))
+	if err != nil {
+		t.Fatalf("failed to read profiles: %v", err)
+	}
+	t.Run("missing credentials return an error", func(t *testing.T) {
+		if _, err := profiles.Opts("production"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("unknown profiles return an error", func(t *testing.T) {
+		if _, err := profiles.Opts("staging"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("the profile settings are applied", func(t *testing.T) {
+		t.Setenv("TEST_PROFILE_TOKEN", "abc")
+		opts, err := profiles.Opts("production")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		h, calls := failingHandler(1, http.StatusServiceUnavailable)
+		var environment, authorization, url string
+		opts = append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			environment, authorization, url = r.Header.Get("X-Environment"), r.Header.Get("Authorization"), r.URL.String()
+			h.ServeHTTP(w, r)
+		})})}, opts...)
+		if _, _, err = jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if *calls != 2 {
+			t.Errorf("expected 2 calls, got %d", *calls)
+		}
+		if environment != "production" {
+			t.Errorf("expected production header, got %q", environment)
+		}
+		if authorization != "Bearer abc" {
+			t.Errorf("expected bearer token, got %q", authorization)
+		}
+		if url != "https://api.example.com/v1/items" {
+			t.Errorf("expected the URL to be resolved against the base URL, got %q", url)
+		}
+	})
+	t.Run("clients are created from profiles with a base URL", func(t *testing.T) {
+		t.Setenv("TEST_PROFILE_TOKEN", "abc")
+		var url string
+		client, err := profiles.Client("production", jsonapi.WithClient(testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			url = r.URL.String()
+			w.WriteHeader(http.StatusNoContent)
+		})}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err = client.Delete(context.Background(), "/items/1", nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if url != "https://api.example.com/v1/items/1" {
+			t.Errorf("unexpected URL %q", url)
+		}
+		if _, err = profiles.Client("dev"); err == nil {
+			t.Error("expected an error for a profile without a base URL")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithQuery sets a query parameter on the request URL, replacing any existing values.
+func WithQuery(key string, values ...string) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, &queryMiddleware{key: key, values: values})
+		return nil
+	}
+}
+
+type queryMiddleware struct {
+	key    string
+	values []string
+}
+
+func (m *queryMiddleware) Request(req *http.Request) error {
+	q := req.URL.Query()
+	q[m.key] = m.values
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func (m *queryMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+// WithFields requests a JSON:API sparse fieldset for a resource type,
+// e.g. WithFields("user", "name", "email") adds fields[user]=name,email.
+func WithFields(resourceType string, fields ...string) Opt {
+	return WithQuery("fields["+resourceType+"]", strings.Join(fields, ","))
+}
+
+// WithInclude requests JSON:API related resources, e.g. WithInclude("author", "orders.items")
+// adds include=author,orders.items.
+func WithInclude(paths ...string) Opt {
+	return WithQuery("include", strings.Join(paths, ","))
+}
+
+// WithSelect requests OData properties, e.g. WithSelect("name", "email") adds $select=name,email.
+func WithSelect(properties ...string) Opt {
+	return WithQuery("$select", strings.Join(properties, ","))
+}
+
+// WithExpand requests OData related entities, e.g. WithExpand("orders") adds $expand=orders.
+func WithExpand(properties ...string) Opt {
+	return WithQuery("$expand", strings.Join(properties, ","))
+}
@@ -0,0 +1,57 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func queryCapturingClient(query *string) testClient {
+	return testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*query = r.URL.RawQuery
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})}
+}
+
+func TestQueryOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		opts     []jsonapi.Opt
+		expected string
+	}{
+		{
+			name:     "sparse fieldsets",
+			url:      "/users",
+			opts:     []jsonapi.Opt{jsonapi.WithFields("user", "name", "email"), jsonapi.WithFields("org", "name")},
+			expected: "fields%5Borg%5D=name&fields%5Buser%5D=name%2Cemail",
+		},
+		{
+			name:     "include",
+			url:      "/users?page=2",
+			opts:     []jsonapi.Opt{jsonapi.WithInclude("orders.items", "author")},
+			expected: "include=orders.items%2Cauthor&page=2",
+		},
+		{
+			name:     "OData select and expand",
+			url:      "/users",
+			opts:     []jsonapi.Opt{jsonapi.WithSelect("name"), jsonapi.WithExpand("orders")},
+			expected: "%24expand=orders&%24select=name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			opts := append([]jsonapi.Opt{jsonapi.WithClient(queryCapturingClient(&query))}, tt.opts...)
+			if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), tt.url, opts...); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("expected query %q, got %q", tt.expected, query)
+			}
+		})
+	}
+}
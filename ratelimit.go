@@ -0,0 +1,155 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the request budget reported by a host.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the window, or -1 if unknown.
+	Limit int `json:"limit"`
+	// Remaining is the number of requests remaining in the window.
+	Remaining int `json:"remaining"`
+	// Reset is the time that the budget resets.
+	Reset time.Time `json:"reset"`
+}
+
+// NewRateLimits creates a RateLimits tracker.
+func NewRateLimits() *RateLimits {
+	return &RateLimits{
+		hosts: map[string]RateLimit{},
+		m:     &sync.Mutex{},
+	}
+}
+
+// RateLimits tracks the rate limit budget of each host from response headers.
+// The X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers, the
+// IETF RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and the
+// IETF RateLimit header are supported.
+type RateLimits struct {
+	// Pace delays requests to a host that has no remaining budget until the budget resets.
+	Pace  bool
+	hosts map[string]RateLimit
+	m     *sync.Mutex
+}
+
+// WithRateLimits tracks rate limit headers using the tracker, using the config's Clock for
+// reset times and pacing. Create the tracker once and share it between calls.
+func WithRateLimits(rl *RateLimits) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return rl.intercept(next, c.clock())
+		})
+		return nil
+	}
+}
+
+// RateLimit returns the most recent budget of the host. Returns ok=false if the host
+// hasn't returned rate limit headers.
+func (rl *RateLimits) RateLimit(host string) (limit RateLimit, ok bool) {
+	rl.m.Lock()
+	defer rl.m.Unlock()
+	limit, ok = rl.hosts[host]
+	return limit, ok
+}
+
+// reserve returns how long to wait before sending a request to the host, and consumes
+// one request from the budget.
+func (rl *RateLimits) reserve(host string, now time.Time) (wait time.Duration) {
+	rl.m.Lock()
+	defer rl.m.Unlock()
+	limit, ok := rl.hosts[host]
+	if !ok {
+		return 0
+	}
+	if !limit.Reset.After(now) {
+		return 0
+	}
+	if limit.Remaining > 0 {
+		limit.Remaining--
+		rl.hosts[host] = limit
+		return 0
+	}
+	return limit.Reset.Sub(now)
+}
+
+func (rl *RateLimits) observe(host string, h http.Header, now time.Time) {
+	limit, ok := parseRateLimit(h, now)
+	if !ok {
+		return
+	}
+	rl.m.Lock()
+	defer rl.m.Unlock()
+	rl.hosts[host] = limit
+}
+
+func (rl *RateLimits) intercept(next Doer, clock Clock) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if rl.Pace {
+			if err := clock.Sleep(req.Context(), rl.reserve(req.URL.Host, clock.Now())); err != nil {
+				return nil, err
+			}
+		}
+		res, err := next.Do(req)
+		if err != nil {
+			return res, err
+		}
+		rl.observe(req.URL.Host, res.Header, clock.Now())
+		return res, nil
+	})
+}
+
+func parseRateLimit(h http.Header, now time.Time) (limit RateLimit, ok bool) {
+	if v := h.Get("RateLimit"); v != "" {
+		return parseRateLimitField(v, now)
+	}
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(h.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+		limit = RateLimit{Limit: -1, Remaining: remaining}
+		if l, err := strconv.Atoi(h.Get(prefix + "Limit")); err == nil {
+			limit.Limit = l
+		}
+		if reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64); err == nil {
+			limit.Reset = parseRateLimitReset(reset, now)
+		}
+		return limit, true
+	}
+	return limit, false
+}
+
+// parseRateLimitReset parses a reset value that is either a Unix timestamp, as used by
+// GitHub's X-RateLimit-Reset header, or a number of seconds, as used by the IETF headers.
+func parseRateLimitReset(reset int64, now time.Time) time.Time {
+	if reset > 1_000_000_000 {
+		return time.Unix(reset, 0)
+	}
+	return now.Add(time.Duration(reset) * time.Second)
+}
+
+// parseRateLimitField parses the IETF RateLimit header, e.g. `"default";r=50;t=30`.
+// Only the first limit is used.
+func parseRateLimitField(v string, now time.Time) (limit RateLimit, ok bool) {
+	item, _, _ := strings.Cut(v, ",")
+	limit.Limit = -1
+	for _, param := range strings.Split(item, ";")[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "r":
+			limit.Remaining, ok = int(n), true
+		case "t":
+			limit.Reset = now.Add(time.Duration(n) * time.Second)
+		}
+	}
+	return limit, ok
+}
@@ -0,0 +1,134 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithRateLimits(t *testing.T) {
+	tests := []struct {
+		name              string
+		header            http.Header
+		expectedLimit     int
+		expectedRemaining int
+		expectedReset     time.Duration
+	}{
+		{
+			name: "X-RateLimit headers with a Unix timestamp",
+			header: http.Header{
+				"X-Ratelimit-Limit":     []string{"5000"},
+				"X-Ratelimit-Remaining": []string{"4999"},
+				"X-Ratelimit-Reset":     []string{"4102444800"},
+			},
+			expectedLimit:     5000,
+			expectedRemaining: 4999,
+		},
+		{
+			name: "IETF RateLimit headers",
+			header: http.Header{
+				"Ratelimit-Limit":     []string{"100"},
+				"Ratelimit-Remaining": []string{"10"},
+				"Ratelimit-Reset":     []string{"60"},
+			},
+			expectedLimit:     100,
+			expectedRemaining: 10,
+			expectedReset:     time.Minute,
+		},
+		{
+			name: "IETF RateLimit field",
+			header: http.Header{
+				"Ratelimit": []string{`"default";r=50;t=30`},
+			},
+			expectedLimit:     -1,
+			expectedRemaining: 50,
+			expectedReset:     30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+			})
+			rl := jsonapi.NewRateLimits()
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "https://api.example.com/items",
+				jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithRateLimits(rl))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			limit, ok := rl.RateLimit("api.example.com")
+			if !ok {
+				t.Fatal("expected a rate limit")
+			}
+			if limit.Limit != tt.expectedLimit || limit.Remaining != tt.expectedRemaining {
+				t.Errorf("unexpected rate limit %+v", limit)
+			}
+			if tt.expectedReset > 0 {
+				if d := time.Until(limit.Reset); d > tt.expectedReset || d < tt.expectedReset-time.Minute {
+					t.Errorf("expected reset in %v, got %v", tt.expectedReset, d)
+				}
+			}
+		})
+	}
+}
+
+func TestWithRateLimitsPace(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "60")
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	rl := jsonapi.NewRateLimits()
+	rl.Pace = true
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithRateLimits(rl),
+	}
+	if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "https://api.example.com/items", opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err := jsonapi.Get[itemsGetResponse](ctx, "https://api.example.com/items", opts...)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to wait for the budget to reset, got %v", err)
+	}
+}
+
+func TestWithRateLimitsClock(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "60")
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	})
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl := jsonapi.NewRateLimits()
+	rl.Pace = true
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithRateLimits(rl),
+		jsonapi.WithClock(clock),
+	}
+	for range 2 {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "https://api.example.com/items", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if diff := cmp.Diff([]time.Duration{0, time.Minute}, clock.sleeps); diff != "" {
+		t.Error(diff)
+	}
+	limit, _ := rl.RateLimit("api.example.com")
+	if expected := clock.now.Add(time.Minute); !limit.Reset.Equal(expected) {
+		t.Errorf("expected the reset to be relative to the clock, got %v, want %v", limit.Reset, expected)
+	}
+}
@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetRaw makes a GET request and returns the response body without decoding it, e.g. for
+// pass-through proxies or signature verification. Middleware, retries and other options are
+// applied as for Get. If the response status is not 2xx, the body, status and headers are
+// returned along with an InvalidStatusError, or a more specific error such as NotModifiedError.
+func GetRaw(ctx context.Context, url string, opts ...Opt) (body []byte, status int, header http.Header, err error) {
+	return doRaw(ctx, http.MethodGet, url, nil, opts)
+}
+
+// PostRaw posts the body as-is, and returns the response body without decoding it.
+// The Content-Type defaults to application/json and can be changed with WithContentType.
+// Errors are returned as for GetRaw.
+func PostRaw(ctx context.Context, url string, request []byte, opts ...Opt) (body []byte, status int, header http.Header, err error) {
+	return doRaw(ctx, http.MethodPost, url, request, opts)
+}
+
+func doRaw(ctx context.Context, method, url string, request []byte, opts []Opt) (body []byte, status int, header http.Header, err error) {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	var r io.Reader
+	if request != nil {
+		r = bytes.NewReader(request)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer res.Body.Close()
+	body, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, res.Header, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err = config.trailer(res); err != nil {
+		return body, res.StatusCode, res.Header, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return body, res.StatusCode, res.Header, config.newStatusError(res.StatusCode, res.Header, config.errorBody(body))
+	}
+	return body, res.StatusCode, res.Header, nil
+}
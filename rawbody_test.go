@@ -0,0 +1,54 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestGetRaw(t *testing.T) {
+	client := jsonapi.WithClient(testClient{Handler: createTestRoutes()})
+
+	t.Run("the body is returned without decoding", func(t *testing.T) {
+		body, status, header, err := jsonapi.GetRaw(context.Background(), "/items/get/ok", client)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if string(body) != `{"items":["item1","item2"]}`+"\n" {
+			t.Errorf("unexpected body %q", body)
+		}
+		if status != http.StatusOK {
+			t.Errorf("expected status 200, got %d", status)
+		}
+		if header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected Content-Type %q", header.Get("Content-Type"))
+		}
+	})
+	t.Run("non-success responses return a status error and the body", func(t *testing.T) {
+		body, status, _, err := jsonapi.GetRaw(context.Background(), "/items/get/404", client)
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) || ise.Status != http.StatusNotFound {
+			t.Fatalf("expected a 404 InvalidStatusError, got %v", err)
+		}
+		if status != http.StatusNotFound || len(body) == 0 {
+			t.Errorf("expected the status and body, got %d, %q", status, body)
+		}
+	})
+}
+
+func TestPostRaw(t *testing.T) {
+	client := jsonapi.WithClient(testClient{Handler: createTestRoutes()})
+	body, status, _, err := jsonapi.PostRaw(context.Background(), "/items/post/ok", []byte(`{"key":"value"}`), client)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", status)
+	}
+	if string(body) != `{"key":"value"}`+"\n" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
@@ -0,0 +1,187 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// WithRedactor redacts secrets from the response bodies included in errors, such as
+// InvalidStatusError, and from the headers of dead letters.
+func WithRedactor(r *Redactor) Opt {
+	return func(c *Config) error {
+		c.Redactor = r
+		return nil
+	}
+}
+
+// NewRedactor creates a Redactor that redacts common credential headers, and JSON fields
+// with names that contain "password", "secret", "token" or "api key".
+func NewRedactor() *Redactor {
+	return &Redactor{
+		Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		Fields:  []*regexp.Regexp{regexp.MustCompile(`(?i)password|secret|token|api[-_]?key`)},
+	}
+}
+
+// Redactor replaces the values of sensitive headers and JSON fields, so that they
+// don't end up in logs and error reports.
+type Redactor struct {
+	// Headers are the names of headers to redact.
+	Headers []string
+	// Fields are matched against the keys of JSON objects. The values of matching keys are redacted.
+	Fields []*regexp.Regexp
+	// Pointers are JSON pointers to values that are redacted, e.g. "/customer/email".
+	// A "*" token matches every key of an object or element of an array, e.g. "/users/*/phone".
+	Pointers []string
+	// Replacement is the value used in place of redacted values. Defaults to "[REDACTED]".
+	Replacement string
+}
+
+func (r *Redactor) replacement() string {
+	if r.Replacement == "" {
+		return "[REDACTED]"
+	}
+	return r.Replacement
+}
+
+// Header returns a copy of the header with sensitive values replaced.
+func (r *Redactor) Header(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	h = h.Clone()
+	for _, name := range r.Headers {
+		values := h.Values(name)
+		for i := range values {
+			values[i] = r.replacement()
+		}
+	}
+	return h
+}
+
+// URL returns the URL as a string, with any password, and the values of query parameters
+// with names that match Fields, replaced.
+func (r *Redactor) URL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	q := u.Query()
+	var redacted bool
+	for k, values := range q {
+		if !r.isSensitiveField(k) {
+			continue
+		}
+		for i := range values {
+			values[i] = r.replacement()
+		}
+		redacted = true
+	}
+	if !redacted {
+		return u.Redacted()
+	}
+	uu := *u
+	uu.RawQuery = q.Encode()
+	return uu.Redacted()
+}
+
+// Body returns a copy of the JSON body with sensitive fields replaced.
+// Bodies that are not valid JSON are returned unchanged.
+func (r *Redactor) Body(body []byte) []byte {
+	if len(r.Fields) == 0 && len(r.Pointers) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	redacted := r.redactValue(v)
+	for _, p := range r.Pointers {
+		tokens := jsonPointerTokens(p)
+		if len(tokens) == 0 {
+			return []byte(strconv.Quote(r.replacement()))
+		}
+		redacted = r.redactPointer(v, tokens) || redacted
+	}
+	if !redacted {
+		return body
+	}
+	output, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return output
+}
+
+// redactValue redacts fields of v in place, and returns true if any were redacted.
+func (r *Redactor) redactValue(v any) (redacted bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if r.isSensitiveField(k) {
+				v[k] = r.replacement()
+				redacted = true
+				continue
+			}
+			redacted = r.redactValue(value) || redacted
+		}
+	case []any:
+		for _, value := range v {
+			redacted = r.redactValue(value) || redacted
+		}
+	}
+	return redacted
+}
+
+// redactPointer redacts the values within v that match the JSON pointer tokens.
+func (r *Redactor) redactPointer(v any, tokens []string) (redacted bool) {
+	token, last := tokens[0], len(tokens) == 1
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if token != "*" && token != k {
+				continue
+			}
+			if last {
+				v[k] = r.replacement()
+				redacted = true
+				continue
+			}
+			redacted = r.redactPointer(value, tokens[1:]) || redacted
+		}
+	case []any:
+		for i, value := range v {
+			if token != "*" && token != strconv.Itoa(i) {
+				continue
+			}
+			if last {
+				v[i] = r.replacement()
+				redacted = true
+				continue
+			}
+			redacted = r.redactPointer(value, tokens[1:]) || redacted
+		}
+	}
+	return redacted
+}
+
+func (r *Redactor) isSensitiveField(name string) bool {
+	for _, re := range r.Fields {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (config *Config) redactBody(body []byte) string {
+	if config.Redactor == nil {
+		return string(body)
+	}
+	return string(config.Redactor.Body(body))
+}
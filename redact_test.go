@@ -0,0 +1,70 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedactor(t *testing.T) {
+	r := jsonapi.NewRedactor()
+	t.Run("headers", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Authorization", "Bearer abc")
+		h.Set("Accept", "application/json")
+		redacted := r.Header(h)
+		expected := http.Header{
+			"Authorization": []string{"[REDACTED]"},
+			"Accept":        []string{"application/json"},
+		}
+		if diff := cmp.Diff(expected, redacted); diff != "" {
+			t.Error(diff)
+		}
+		if h.Get("Authorization") != "Bearer abc" {
+			t.Error("expected the original header to be unmodified")
+		}
+	})
+	t.Run("JSON fields", func(t *testing.T) {
+		actual := string(r.Body([]byte(`{"user":{"name":"a","password":"b"},"tokens":[{"access_token":"c"}],"count":1.50}`)))
+		expected := `{"count":1.50,"tokens":"[REDACTED]","user":{"name":"a","password":"[REDACTED]"}}`
+		if actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	})
+	t.Run("non-JSON bodies are unchanged", func(t *testing.T) {
+		if actual := string(r.Body([]byte("Internal server error"))); actual != "Internal server error" {
+			t.Errorf("unexpected body %q", actual)
+		}
+	})
+}
+
+func TestWithRedactor(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid","apiKey":"abc"}`))
+	})
+	_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithRedactor(jsonapi.NewRedactor()))
+	ise, ok := err.(jsonapi.InvalidStatusError)
+	if !ok {
+		t.Fatalf("expected InvalidStatusError, got %v", err)
+	}
+	if expected := `{"apiKey":"[REDACTED]","error":"invalid"}`; ise.Body != expected {
+		t.Errorf("expected %s, got %s", expected, ise.Body)
+	}
+}
+
+func TestRedactorPointers(t *testing.T) {
+	r := &jsonapi.Redactor{
+		Pointers: []string{"/customer/email", "/users/*/phone", "/missing/field"},
+	}
+	actual := string(r.Body([]byte(`{"customer":{"email":"a@example.com","id":1},"users":[{"phone":"1"},{"phone":"2","name":"b"}]}`)))
+	expected := `{"customer":{"email":"[REDACTED]","id":1},"users":[{"phone":"[REDACTED]"},{"name":"b","phone":"[REDACTED]"}]}`
+	if actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
@@ -0,0 +1,242 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// Requests are retried if they fail with a network error, or receive a 429, 502, 503 or 504 status.
+// Network errors are classified by TransportErrorRetry, so that, for example, certificate errors
+// are not retried.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times a request is sent. Values less than 2 disable retries.
+	Attempts int
+	// Backoff is the delay before the first retry. The delay doubles for each subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries, including delays requested by a Retry-After header.
+	MaxBackoff time.Duration
+	// RetryIf marks additional responses or errors as retryable. The response body can be
+	// read by RetryIf, it is buffered and restored before the response is returned.
+	RetryIf func(res *http.Response, err error) bool
+	// DeadLetter is called when all attempts have failed.
+	DeadLetter func(ctx context.Context, dl DeadLetter)
+	// TransportErrorRetry decides whether requests that fail without a response are retried.
+	// Return RetryDefault to use DefaultTransportErrorRetry.
+	TransportErrorRetry func(kind TransportError, err error) RetryDecision
+	// Budget, if set, limits the number of retries across all requests that share it.
+	Budget *RetryBudget
+	// Methods, if set, limits retries to requests with one of the HTTP methods, see WithRetryMethods.
+	Methods []string
+}
+
+// DeadLetter is a request that failed after all retry attempts were exhausted.
+type DeadLetter struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	// Attempts is the number of times the request was sent.
+	Attempts int `json:"attempts"`
+	// Status is the status code of the final response, or 0 if no response was received.
+	Status int   `json:"status"`
+	Err    error `json:"-"`
+}
+
+// WithRetry retries failed requests up to attempts times in total, with exponential backoff.
+// All HTTP methods are retried, so mutating endpoints should support idempotency keys.
+// Requests with a body that can't be rewound, because req.GetBody is nil, such as those sent
+// by PostStream, are sent once, since buffering the body would defeat streaming.
+func WithRetry(attempts int, backoff time.Duration) Opt {
+	return func(c *Config) error {
+		c.Retry.Attempts = attempts
+		c.Retry.Backoff = backoff
+		if c.Retry.MaxBackoff == 0 {
+			c.Retry.MaxBackoff = 30 * time.Second
+		}
+		return nil
+	}
+}
+
+// WithRetryMethods limits retries to requests with one of the HTTP methods, e.g. GET and HEAD,
+// so that requests to mutating endpoints that don't support idempotency keys are sent once.
+// It has no effect unless retries are enabled with WithRetry.
+func WithRetryMethods(methods ...string) Opt {
+	return func(c *Config) error {
+		c.Retry.Methods = methods
+		return nil
+	}
+}
+
+// WithRetryIf retries requests where f returns true, in addition to the default retryable
+// network errors and statuses. For example, to retry a 409 with a lock contention error body.
+// It has no effect unless retries are enabled with WithRetry.
+func WithRetryIf(f func(res *http.Response, err error) bool) Opt {
+	return func(c *Config) error {
+		c.Retry.RetryIf = f
+		return nil
+	}
+}
+
+// WithDeadLetter calls f with the serialized request and final error when retries are exhausted,
+// so that failed requests can be parked for manual replay. The Authorization header is removed.
+func WithDeadLetter(f func(ctx context.Context, dl DeadLetter)) Opt {
+	return func(c *Config) error {
+		c.Retry.DeadLetter = f
+		return nil
+	}
+}
+
+func (config *Config) retry(next Doer) Doer {
+	p := config.Retry
+	return DoerFunc(func(req *http.Request) (res *http.Response, err error) {
+		if isStreamingBody(req) {
+			return next.Do(req)
+		}
+		if len(p.Methods) > 0 && !slices.Contains(p.Methods, req.Method) {
+			return next.Do(req)
+		}
+		if p.Budget != nil {
+			p.Budget.recordRequest(config.clock().Now())
+		}
+		var attempt int
+		for attempt = 1; ; attempt++ {
+			if attempt > 1 && req.GetBody != nil {
+				req.Body, err = req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+			}
+			res, err = next.Do(req)
+			retry, immediate, rerr := p.shouldRetry(res, err)
+			if rerr != nil {
+				return res, rerr
+			}
+			if !retry {
+				return res, err
+			}
+			if attempt >= p.Attempts {
+				break
+			}
+			if p.Budget != nil && !p.Budget.withdraw(config.clock().Now()) {
+				break
+			}
+			var delay time.Duration
+			if !immediate {
+				delay = p.delay(attempt, res, config.clock().Now())
+			}
+			if res != nil {
+				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+			}
+			if err := config.clock().Sleep(req.Context(), delay); err != nil {
+				return nil, err
+			}
+		}
+		if p.DeadLetter != nil {
+			body, berr := readRequestBody(req)
+			if berr != nil {
+				return res, berr
+			}
+			dl := DeadLetter{
+				Method:   req.Method,
+				URL:      req.URL.String(),
+				Header:   req.Header.Clone(),
+				Body:     body,
+				Attempts: attempt,
+				Err:      err,
+			}
+			dl.Header.Del("Authorization")
+			if config.Redactor != nil {
+				dl.Header = config.Redactor.Header(dl.Header)
+			}
+			if res != nil {
+				dl.Status = res.StatusCode
+				dl.Err = config.newStatusError(res.StatusCode, res.Header, "")
+			}
+			if derr := safely(func() error { p.DeadLetter(req.Context(), dl); return nil }); derr != nil {
+				return res, derr
+			}
+		}
+		return res, err
+	})
+}
+
+// shouldRetry returns true if the request should be retried, and whether the retry should
+// be made without a delay.
+func (p RetryPolicy) shouldRetry(res *http.Response, err error) (retry, immediate bool, rerr error) {
+	if err != nil {
+		decision, derr := p.transportErrorRetry(err)
+		if derr != nil {
+			return false, false, derr
+		}
+		if decision != RetryNever {
+			return true, decision == RetryImmediately, nil
+		}
+	}
+	if isRetryable(res, err) {
+		return true, false, nil
+	}
+	if p.RetryIf == nil {
+		return false, false, nil
+	}
+	retryIf := func() error {
+		retry = p.RetryIf(res, err)
+		return nil
+	}
+	if res == nil {
+		return retry, false, safely(retryIf)
+	}
+	body, rerr := io.ReadAll(res.Body)
+	res.Body.Close()
+	if rerr != nil {
+		return false, false, fmt.Errorf("failed to read response body: %w", rerr)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	rerr = safely(retryIf)
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return retry, false, rerr
+}
+
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return false
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) delay(attempt int, res *http.Response, now time.Time) (d time.Duration) {
+	d = p.Backoff << (attempt - 1)
+	if res != nil {
+		// Retry-After is either a number of seconds, or a HTTP date.
+		if v := res.Header.Get("Retry-After"); v != "" {
+			if ra := parseRetryAfter(v, now); ra > 0 || v == "0" {
+				d = ra
+			}
+		}
+	}
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d < 0) {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,155 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+	"github.com/google/go-cmp/cmp"
+)
+
+func failingHandler(failures int, status int) (http.Handler, *int) {
+	var calls int
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			respond.WithError(w, "Unavailable", status)
+			return
+		}
+		respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+	}), &calls
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	t.Run("retryable statuses are retried", func(t *testing.T) {
+		h, calls := failingHandler(2, http.StatusServiceUnavailable)
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if *calls != 3 {
+			t.Errorf("expected 3 calls, got %d", *calls)
+		}
+	})
+	t.Run("other statuses are not retried", func(t *testing.T) {
+		h, calls := failingHandler(2, http.StatusInternalServerError)
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if *calls != 1 {
+			t.Errorf("expected 1 call, got %d", *calls)
+		}
+	})
+	t.Run("retries can be limited to methods", func(t *testing.T) {
+		for _, tt := range []struct {
+			method        string
+			expectedCalls int
+		}{{http.MethodGet, 3}, {http.MethodPost, 1}} {
+			h, calls := failingHandler(2, http.StatusServiceUnavailable)
+			_, _ = jsonapi.Do[any, itemsGetResponse](ctx, tt.method, "/items", nil, jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithRetry(3, time.Millisecond), jsonapi.WithRetryMethods(http.MethodGet, http.MethodHead))
+			if *calls != tt.expectedCalls {
+				t.Errorf("%s: expected %d calls, got %d", tt.method, tt.expectedCalls, *calls)
+			}
+		}
+	})
+	t.Run("custom retry predicates can inspect the response body", func(t *testing.T) {
+		var calls int
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				respond.WithJSON(w, map[string]string{"error": "lock_contention"}, http.StatusConflict)
+				return
+			}
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		})
+		retryIf := func(res *http.Response, err error) bool {
+			if res == nil || res.StatusCode != http.StatusConflict {
+				return false
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			json.NewDecoder(res.Body).Decode(&body)
+			return body.Error == "lock_contention"
+		}
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond), jsonapi.WithRetryIf(retryIf))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected 2 calls, got %d", calls)
+		}
+	})
+	t.Run("bodies that can't be rewound are sent once", func(t *testing.T) {
+		h, calls := failingHandler(2, http.StatusServiceUnavailable)
+		req, _ := http.NewRequest(http.MethodPost, "/items", io.NopCloser(strings.NewReader(`{"key":"value"}`)))
+		res, err := jsonapi.Raw(req, jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, res.StatusCode)
+		}
+		if *calls != 1 {
+			t.Errorf("expected 1 call, got %d", *calls)
+		}
+	})
+	t.Run("Retry-After dates are used as the delay", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+		var calls int
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.Header().Set("Retry-After", clock.Now().Add(5*time.Second).Format(http.TimeFormat))
+				respond.WithError(w, "Unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		})
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond), jsonapi.WithClock(clock))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]time.Duration{5 * time.Second}, clock.sleeps); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the dead letter handler is called when retries are exhausted", func(t *testing.T) {
+		h, calls := failingHandler(5, http.StatusServiceUnavailable)
+		var dead []jsonapi.DeadLetter
+		_, err := jsonapi.Post[map[string]any, map[string]any](ctx, "/items", map[string]any{"key": "value"},
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithAuthorization("Bearer abc"),
+			jsonapi.WithRetry(3, time.Millisecond),
+			jsonapi.WithDeadLetter(func(ctx context.Context, dl jsonapi.DeadLetter) {
+				dead = append(dead, dl)
+			}))
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) {
+			t.Fatalf("expected InvalidStatusError, got %v", err)
+		}
+		if *calls != 3 {
+			t.Errorf("expected 3 calls, got %d", *calls)
+		}
+		if len(dead) != 1 {
+			t.Fatalf("expected 1 dead letter, got %d", len(dead))
+		}
+		if dead[0].Attempts != 3 || dead[0].Status != http.StatusServiceUnavailable || string(dead[0].Body) != `{"key":"value"}` {
+			t.Errorf("unexpected dead letter: %#v", dead[0])
+		}
+		if dead[0].Header.Get("Authorization") != "" {
+			t.Error("expected the Authorization header to be removed")
+		}
+	})
+}
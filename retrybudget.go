@@ -0,0 +1,93 @@
+package jsonapi
+
+import (
+	"sync"
+	"time"
+)
+
+// NewRetryBudget creates a RetryBudget that allows retries of up to ratio of the requests
+// made in the last 10 seconds, e.g. 0.1 for 10%, plus minRetries retries in that time, so
+// that clients that make few requests can still retry.
+func NewRetryBudget(ratio float64, minRetries int) *RetryBudget {
+	return &RetryBudget{
+		Ratio:      ratio,
+		MinRetries: minRetries,
+		Window:     10 * time.Second,
+	}
+}
+
+// RetryBudget limits the number of retries relative to the number of requests, so that when
+// an upstream service fails, retries don't multiply the load on it and prolong the outage.
+//
+// A RetryBudget is safe for concurrent use, and is intended to be shared by all of the clients
+// in a process, see WithRetryBudget.
+type RetryBudget struct {
+	// Ratio is the maximum number of retries per request, e.g. 0.1 for 10%.
+	Ratio float64
+	// MinRetries is the number of retries allowed in each window, regardless of the ratio.
+	MinRetries int
+	// Window is the period over which requests and retries are counted. If zero or less, 10
+	// seconds is used. The window is split into 10 buckets, so it is at least 10ns.
+	Window time.Duration
+
+	m       sync.Mutex
+	buckets [10]retryBudgetBucket
+}
+
+type retryBudgetBucket struct {
+	index    int64
+	requests int
+	retries  int
+}
+
+// bucket returns the bucket for the time, resetting it if it was last used in an earlier window.
+func (b *RetryBudget) bucket(now time.Time) *retryBudgetBucket {
+	index := now.UnixNano() / int64(b.window()/time.Duration(len(b.buckets)))
+	bucket := &b.buckets[index%int64(len(b.buckets))]
+	if bucket.index != index {
+		*bucket = retryBudgetBucket{index: index}
+	}
+	return bucket
+}
+
+func (b *RetryBudget) window() time.Duration {
+	if b.Window <= 0 {
+		return 10 * time.Second
+	}
+	return max(b.Window, time.Duration(len(b.buckets)))
+}
+
+func (b *RetryBudget) recordRequest(now time.Time) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.bucket(now).requests++
+}
+
+// withdraw records a retry and returns true if the budget allows it.
+func (b *RetryBudget) withdraw(now time.Time) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+	current := b.bucket(now)
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if current.index-bucket.index < int64(len(b.buckets)) {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	if float64(retries) >= b.Ratio*float64(requests)+float64(b.MinRetries) {
+		return false
+	}
+	current.retries++
+	return true
+}
+
+// WithRetryBudget limits retries to the budget, which is typically shared by all clients in a
+// process. When the budget is exhausted, failed requests are returned without being retried.
+// It has no effect unless retries are enabled with WithRetry.
+func WithRetryBudget(budget *RetryBudget) Opt {
+	return func(c *Config) error {
+		c.Retry.Budget = budget
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithRetryBudget(t *testing.T) {
+	t.Run("retries are limited to the ratio of requests", func(t *testing.T) {
+		h, calls := failingHandler(1000, http.StatusServiceUnavailable)
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := jsonapi.NewRetryBudget(0.5, 0)
+		for range 10 {
+			jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+				jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(3, time.Millisecond),
+				jsonapi.WithRetryBudget(budget), jsonapi.WithClock(clock))
+		}
+		if *calls != 15 {
+			t.Errorf("expected 10 requests and 5 retries, got %d calls", *calls)
+		}
+	})
+	t.Run("the minimum retries are allowed regardless of the ratio", func(t *testing.T) {
+		h, calls := failingHandler(1000, http.StatusServiceUnavailable)
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := jsonapi.NewRetryBudget(0, 2)
+		jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(5, time.Millisecond),
+			jsonapi.WithRetryBudget(budget), jsonapi.WithClock(clock))
+		if *calls != 3 {
+			t.Errorf("expected 1 request and 2 retries, got %d calls", *calls)
+		}
+	})
+	t.Run("the budget is restored after the window", func(t *testing.T) {
+		h, calls := failingHandler(1000, http.StatusServiceUnavailable)
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := jsonapi.NewRetryBudget(0, 1)
+		opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(2, time.Millisecond),
+			jsonapi.WithRetryBudget(budget), jsonapi.WithClock(clock)}
+		jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		clock.Sleep(context.Background(), 11*time.Second)
+		jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		if *calls != 5 {
+			t.Errorf("expected 3 requests and 2 retries, got %d calls", *calls)
+		}
+	})
+	t.Run("windows shorter than the number of buckets are clamped", func(t *testing.T) {
+		h, _ := failingHandler(1000, http.StatusServiceUnavailable)
+		budget := jsonapi.NewRetryBudget(0, 1)
+		budget.Window = time.Nanosecond
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRetry(2, time.Millisecond),
+			jsonapi.WithRetryBudget(budget), jsonapi.WithClock(&fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
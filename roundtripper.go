@@ -0,0 +1,61 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// NewTransport returns a http.RoundTripper that sends requests using the options, so that
+// third-party SDKs that accept an *http.Client can use the same auth, retry and tracing
+// middleware as this package, e.g. &http.Client{Transport: jsonapi.NewTransport(opts...)}.
+//
+// Responses are returned as-is, non-2xx statuses are not converted to errors, and the
+// Content-Type header of the request is not changed. The options must not use a client
+// whose transport is the returned RoundTripper.
+//
+// The options are applied once, so state such as the tokens cached by WithAuthMiddleware is
+// shared by all requests. If the options return an error, each request returns it.
+func NewTransport(opts ...Opt) http.RoundTripper {
+	// The default Content-Type header isn't set, since the caller provides the request body.
+	config, err := configure(&Config{Client: defaultClient}, opts...)
+	if err != nil {
+		return roundTripper{err: fmt.Errorf("failed to create config: %w", err)}
+	}
+	return roundTripper{config: config}
+}
+
+// Transport returns a http.RoundTripper that sends requests using the caller's options.
+// The options are applied once, when Transport is called. See NewTransport.
+func (c *HTTPCaller) Transport() http.RoundTripper {
+	return NewTransport(c.Opts...)
+}
+
+type roundTripper struct {
+	config *Config
+	err    error
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.err != nil {
+		closeRequestBody(req)
+		return nil, rt.err
+	}
+	// A RoundTripper must not modify the request, but middleware sets headers.
+	res, err := rt.config.raw(req.Clone(req.Context()))
+	if err != nil {
+		// The body must be closed, even if the request wasn't sent, and a RoundTripper
+		// must not return a response with an error.
+		closeRequestBody(req)
+		if res != nil {
+			res.Body.Close()
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,158 @@
+package jsonapi_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestNewTransport(t *testing.T) {
+	var received *http.Request
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.WriteHeader(http.StatusTeapot)
+	})
+	caller := jsonapi.NewCaller(jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithAuthorization("Bearer abc"))
+	client := &http.Client{Transport: caller.Transport()}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/upload", strings.NewReader("a,b,c"))
+	req.RequestURI = ""
+	req.Header.Set("Content-Type", "text/csv")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusTeapot {
+		t.Errorf("expected the response to be returned as-is, got status %d", res.StatusCode)
+	}
+	if actual := received.Header.Get("Authorization"); actual != "Bearer abc" {
+		t.Errorf("expected the middleware to set the Authorization header, got %q", actual)
+	}
+	if actual := received.Header.Get("Content-Type"); actual != "text/csv" {
+		t.Errorf("expected the Content-Type to be unchanged, got %q", actual)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("expected the original request not to be modified")
+	}
+}
+
+type failingMiddleware struct {
+	request, response error
+}
+
+func (m failingMiddleware) Request(req *http.Request) error {
+	return m.request
+}
+
+func (m failingMiddleware) Response(res *http.Response) error {
+	return m.response
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	middlewareErr := errors.New("middleware failed")
+
+	t.Run("the request body is closed if the request isn't sent", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("a,b,c")}
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com/upload", body)
+		rt := jsonapi.NewTransport(jsonapi.WithClient(testClient{Handler: createTestRoutes()}), jsonapi.WithMiddleware(failingMiddleware{request: middlewareErr}))
+		res, err := rt.RoundTrip(req)
+		if !errors.Is(err, middlewareErr) {
+			t.Errorf("expected the middleware error, got %v", err)
+		}
+		if res != nil {
+			t.Error("expected a nil response")
+		}
+		if !body.closed {
+			t.Error("expected the request body to be closed")
+		}
+	})
+	t.Run("no response is returned with an error", func(t *testing.T) {
+		resBody := &closeRecorder{Reader: strings.NewReader("{}")}
+		client := jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: resBody}, nil
+		})
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/items", nil)
+		rt := jsonapi.NewTransport(jsonapi.WithClient(client), jsonapi.WithMiddleware(failingMiddleware{response: middlewareErr}))
+		res, err := rt.RoundTrip(req)
+		if !errors.Is(err, middlewareErr) {
+			t.Errorf("expected the middleware error, got %v", err)
+		}
+		if res != nil {
+			t.Error("expected a nil response")
+		}
+		if !resBody.closed {
+			t.Error("expected the response body to be closed")
+		}
+	})
+	t.Run("the default Content-Type is not set", func(t *testing.T) {
+		var received *http.Request
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+		})
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/items", nil)
+		rt := jsonapi.NewTransport(jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRequestHeader("X-Tenant", "a"))
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		res.Body.Close()
+		if actual := received.Header.Get("Content-Type"); actual != "" {
+			t.Errorf("expected no Content-Type, got %q", actual)
+		}
+		if actual := received.Header.Get("X-Tenant"); actual != "a" {
+			t.Errorf("expected the options' middleware to run, got X-Tenant %q", actual)
+		}
+	})
+}
+
+func TestNewTransportSharesState(t *testing.T) {
+	claims, _ := json.Marshal(map[string]any{"exp": time.Now().Add(time.Hour).Unix()})
+	var fetches int
+	rt := jsonapi.NewTransport(jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAuthMiddleware(func() (string, error) {
+			fetches++
+			return "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature", nil
+		}))
+	for range 3 {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/items/get/ok", nil)
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		res.Body.Close()
+	}
+	if fetches != 1 {
+		t.Errorf("expected 1 token fetch, got %d", fetches)
+	}
+}
+
+func TestNewTransportOptionErrors(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("a,b,c")}
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/upload", body)
+	rt := jsonapi.NewTransport(jsonapi.WithMaxInFlight(0))
+	if _, err := rt.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "failed to create config") {
+		t.Errorf("expected the option error, got %v", err)
+	}
+	if !body.closed {
+		t.Error("expected the request body to be closed")
+	}
+}
@@ -0,0 +1,69 @@
+package jsonapi
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// WithRoundTripperMiddleware wraps the transport of the client with wrap, so that existing
+// http.RoundTripper middleware, such as otelhttp.NewTransport, can be used with this package
+// without being rewritten as Middleware.
+//
+// If the underlying Doer is an *http.Client, its transport is wrapped, so that redirects also
+// pass through the wrapper. Other Doers are adapted to a http.RoundTripper and wrapped.
+// The wrapped transport is reused each time the Opt is applied to a client with the same
+// transport, so that stateful wrappers, such as circuit breakers, are shared between calls.
+//
+// Options that customize the *http.Transport, such as WithProxy, have no effect once the
+// transport is wrapped, so apply WithRoundTripperMiddleware after them.
+func WithRoundTripperMiddleware(wrap func(http.RoundTripper) http.RoundTripper) Opt {
+	var m sync.Mutex
+	wrapped := map[any]http.RoundTripper{}
+	// get returns the wrapped transport. Only pointers are cached, since other values may
+	// not be comparable, e.g. structs containing a func.
+	get := func(key any, rt http.RoundTripper) http.RoundTripper {
+		if reflect.TypeOf(key).Kind() != reflect.Pointer {
+			return wrap(rt)
+		}
+		m.Lock()
+		defer m.Unlock()
+		w, ok := wrapped[key]
+		if !ok {
+			w = wrap(rt)
+			wrapped[key] = w
+		}
+		return w
+	}
+	return func(c *Config) error {
+		if c.Client == nil {
+			c.Client = defaultClient
+		}
+		httpc, ok := c.Client.(*http.Client)
+		if !ok {
+			c.Client = DoerFunc(get(c.Client, doerRoundTripper{c.Client}).RoundTrip)
+			return nil
+		}
+		rt := httpc.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		clone := *httpc
+		clone.Transport = get(rt, rt)
+		c.Client = &clone
+		// The wrapper sends requests through the guarded transport, see checkTransport.
+		if rt == c.transport {
+			c.transport = clone.Transport
+		}
+		return nil
+	}
+}
+
+// doerRoundTripper adapts a Doer to a http.RoundTripper.
+type doerRoundTripper struct {
+	Doer
+}
+
+func (d doerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return d.Do(req)
+}
@@ -0,0 +1,62 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type headerRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Wrapped", "true")
+	return rt.next.RoundTrip(req)
+}
+
+func TestWithRoundTripperMiddleware(t *testing.T) {
+	var wraps int
+	wrap := func(next http.RoundTripper) http.RoundTripper {
+		wraps++
+		return headerRoundTripper{next: next}
+	}
+	routes := createTestRoutes()
+	var received []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Wrapped"))
+		routes.ServeHTTP(w, r)
+	})
+	t.Run("the transport of a http.Client is wrapped", func(t *testing.T) {
+		s := httptest.NewServer(h)
+		defer s.Close()
+		received, wraps = nil, 0
+		opts := []jsonapi.Opt{jsonapi.WithClient(&http.Client{Transport: &http.Transport{}}), jsonapi.WithRoundTripperMiddleware(wrap)}
+		for range 2 {
+			if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL+"/items/get/ok", opts...); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if len(received) != 2 || received[0] != "true" || received[1] != "true" {
+			t.Errorf("expected the requests to be wrapped, got %v", received)
+		}
+		if wraps != 1 {
+			t.Errorf("expected the transport to be wrapped once, got %d", wraps)
+		}
+	})
+	t.Run("other Doers are wrapped", func(t *testing.T) {
+		received, wraps = nil, 0
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithRoundTripperMiddleware(wrap))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(received) != 1 || received[0] != "true" {
+			t.Errorf("expected the request to be wrapped, got %v", received)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package jsonapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignURL returns the URL with "expires" and "signature" query parameters added, so that it can
+// be handed to a browser as a time-limited link. The signature is an HMAC-SHA256 of the path and
+// query, so neither can be modified. Use VerifySignedURL on the server to check the signature.
+func SignURL(rawURL string, key []byte, expires time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	q := u.Query()
+	q.Del("signature")
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", signURL(u.EscapedPath(), q, key))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// VerifySignedURL checks that the URL was signed by SignURL with the key, and has not expired.
+// It returns an InvalidSignatureError if not. Expiry is checked using the Clock set by WithClock.
+func VerifySignedURL(u *url.URL, key []byte, opts ...Opt) error {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	q := u.Query()
+	signature := q.Get("signature")
+	if signature == "" {
+		return InvalidSignatureError{Reason: "missing signature parameter"}
+	}
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil {
+		return InvalidSignatureError{Reason: "missing or invalid expires parameter"}
+	}
+	if !hmac.Equal([]byte(signature), []byte(signURL(u.EscapedPath(), q, key))) {
+		return InvalidSignatureError{Reason: "signature does not match"}
+	}
+	if config.clock().Now().After(time.Unix(expires, 0)) {
+		return InvalidSignatureError{Reason: "URL has expired"}
+	}
+	return nil
+}
+
+// signURL returns the signature of the path and query, excluding any signature parameter.
+func signURL(path string, q url.Values, key []byte) string {
+	unsigned := url.Values{}
+	for k, v := range q {
+		if k != "signature" {
+			unsigned[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path + "?" + unsigned.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
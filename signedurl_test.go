@@ -0,0 +1,72 @@
+package jsonapi_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestSignURL(t *testing.T) {
+	key := []byte("secret")
+	signed, err := jsonapi.SignURL("https://example.com/files/report.pdf?download=true", key, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to sign URL: %v", err)
+	}
+	expired, err := jsonapi.SignURL("https://example.com/files/report.pdf", key, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to sign URL: %v", err)
+	}
+	tests := []struct {
+		name        string
+		url         string
+		key         []byte
+		expectValid bool
+	}{
+		{name: "valid", url: signed, key: key, expectValid: true},
+		{name: "wrong key", url: signed, key: []byte("other"), expectValid: false},
+		{name: "modified path", url: strings.Replace(signed, "report.pdf", "other.pdf", 1), key: key, expectValid: false},
+		{name: "modified query", url: strings.Replace(signed, "download=true", "download=false", 1), key: key, expectValid: false},
+		{name: "expired", url: expired, key: key, expectValid: false},
+		{name: "unsigned", url: "https://example.com/files/report.pdf", key: key, expectValid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("failed to parse URL: %v", err)
+			}
+			err = jsonapi.VerifySignedURL(u, tt.key)
+			if tt.expectValid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			var ise jsonapi.InvalidSignatureError
+			if !tt.expectValid && !errors.As(err, &ise) {
+				t.Errorf("expected InvalidSignatureError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySignedURLClock(t *testing.T) {
+	key := []byte("secret")
+	expires := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	signed, err := jsonapi.SignURL("https://example.com/files/report.pdf", key, expires)
+	if err != nil {
+		t.Fatalf("failed to sign URL: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+	if err := jsonapi.VerifySignedURL(u, key, jsonapi.WithClock(&fakeClock{now: expires.Add(-time.Minute)})); err != nil {
+		t.Errorf("expected no error before expiry, got %v", err)
+	}
+	var ise jsonapi.InvalidSignatureError
+	if err := jsonapi.VerifySignedURL(u, key, jsonapi.WithClock(&fakeClock{now: expires.Add(time.Minute)})); !errors.As(err, &ise) {
+		t.Errorf("expected InvalidSignatureError after expiry, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package jsonapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a server-sent event.
+type Event struct {
+	ID    string
+	Event string
+	Data  []byte
+}
+
+// EventMetrics are the counters collected by an EventRouter for each event name.
+type EventMetrics struct {
+	Count    int
+	Errors   int
+	Panics   int
+	Duration time.Duration
+}
+
+// EventRouter dispatches server-sent events to typed handlers by event name.
+// Use On to register handlers.
+type EventRouter struct {
+	handlers map[string]func(ctx context.Context, data []byte) error
+	// Unhandled is called for events with no registered handler. If nil, the events are ignored.
+	Unhandled func(ctx context.Context, e Event) error
+	metrics   map[string]EventMetrics
+	m         *sync.Mutex
+}
+
+func NewEventRouter() *EventRouter {
+	return &EventRouter{
+		handlers: map[string]func(ctx context.Context, data []byte) error{},
+		metrics:  map[string]EventMetrics{},
+		m:        &sync.Mutex{},
+	}
+}
+
+// On registers a handler for the named event. The event data is decoded from JSON into T.
+func On[T any](r *EventRouter, event string, handler func(ctx context.Context, v T) error) {
+	r.handlers[event] = func(ctx context.Context, data []byte) error {
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("failed to unmarshal %q event: %w", event, err)
+		}
+		return handler(ctx, v)
+	}
+}
+
+// Metrics returns a snapshot of the metrics collected for each event name.
+func (r *EventRouter) Metrics() map[string]EventMetrics {
+	r.m.Lock()
+	defer r.m.Unlock()
+	snapshot := make(map[string]EventMetrics, len(r.metrics))
+	for k, v := range r.metrics {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+// Handle dispatches a single event. Panics in handlers are recovered and returned as errors.
+func (r *EventRouter) Handle(ctx context.Context, e Event) (err error) {
+	start := time.Now()
+	defer func() {
+		var pe PanicError
+		panicked := errors.As(err, &pe)
+		if panicked {
+			err = fmt.Errorf("handler for %q event failed: %w", e.Event, err)
+		}
+		r.m.Lock()
+		defer r.m.Unlock()
+		m := r.metrics[e.Event]
+		m.Count++
+		m.Duration += time.Since(start)
+		if err != nil {
+			m.Errors++
+		}
+		if panicked {
+			m.Panics++
+		}
+		r.metrics[e.Event] = m
+	}()
+	handler, ok := r.handlers[e.Event]
+	if !ok {
+		if r.Unhandled != nil {
+			return safely(func() error { return r.Unhandled(ctx, e) })
+		}
+		return nil
+	}
+	return safely(func() error { return handler(ctx, e.Data) })
+}
+
+// Serve reads server-sent events from the reader and dispatches them until the reader is exhausted,
+// the context is cancelled, or a handler returns an error.
+func (r *EventRouter) Serve(ctx context.Context, body io.Reader) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var e Event
+	var data []string
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				if e.Event == "" {
+					e.Event = "message"
+				}
+				e.Data = []byte(strings.Join(data, "\n"))
+				if err := r.Handle(ctx, e); err != nil {
+					return err
+				}
+			}
+			e, data = Event{ID: e.ID}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			e.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			e.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return nil
+}
+
+// Stream connects to a server-sent event stream at the given URL and dispatches events to the router.
+func Stream(ctx context.Context, url string, router *EventRouter, opts ...Opt) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body := config.readErrorBody(res.Body)
+		return config.newStatusError(res.StatusCode, res.Header, body)
+	}
+	return router.Serve(ctx, res.Body)
+}
@@ -0,0 +1,97 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type orderUpdated struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestEventRouter(t *testing.T) {
+	ctx := context.Background()
+	stream := strings.Join([]string{
+		": comment",
+		"event: order.updated",
+		`data: {"id":"1",`,
+		`data: "status":"shipped"}`,
+		"",
+		"event: order.deleted",
+		`data: {"id":"1"}`,
+		"",
+		"event: order.panic",
+		"data: {}",
+		"",
+		"",
+	}, "\n")
+
+	t.Run("events are routed to typed handlers", func(t *testing.T) {
+		var updates []orderUpdated
+		r := jsonapi.NewEventRouter()
+		jsonapi.On(r, "order.updated", func(ctx context.Context, v orderUpdated) error {
+			updates = append(updates, v)
+			return nil
+		})
+		if err := r.Serve(ctx, strings.NewReader(strings.Join(strings.Split(stream, "\n")[:6], "\n"))); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(updates) != 1 || updates[0].Status != "shipped" {
+			t.Errorf("unexpected updates: %#v", updates)
+		}
+		if m := r.Metrics()["order.updated"]; m.Count != 1 {
+			t.Errorf("expected count of 1, got %d", m.Count)
+		}
+	})
+	t.Run("panics are recovered and counted", func(t *testing.T) {
+		r := jsonapi.NewEventRouter()
+		jsonapi.On(r, "order.panic", func(ctx context.Context, v struct{}) error {
+			panic("oops")
+		})
+		err := r.Serve(ctx, strings.NewReader(stream))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if m := r.Metrics()["order.panic"]; m.Panics != 1 || m.Errors != 1 {
+			t.Errorf("expected a panic to be recorded, got %#v", m)
+		}
+	})
+	t.Run("handler errors stop the stream", func(t *testing.T) {
+		expected := errors.New("failed")
+		r := jsonapi.NewEventRouter()
+		jsonapi.On(r, "order.updated", func(ctx context.Context, v orderUpdated) error {
+			return expected
+		})
+		if err := r.Serve(ctx, strings.NewReader(stream)); !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+	t.Run("Stream reads events from a URL", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "text/event-stream" {
+				http.Error(w, "unexpected accept header", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, stream)
+		})
+		var count int
+		r := jsonapi.NewEventRouter()
+		jsonapi.On(r, "order.deleted", func(ctx context.Context, v orderUpdated) error {
+			count++
+			return nil
+		})
+		if err := jsonapi.Stream(ctx, "/events", r, jsonapi.WithClient(testClient{Handler: h})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count != 1 {
+			t.Errorf("expected 1 event, got %d", count)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package jsonapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// UnauthorizedError is returned when the server responds with 401 Unauthorized.
+type UnauthorizedError struct {
+	InvalidStatusError
+}
+
+func (e UnauthorizedError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// ForbiddenError is returned when the server responds with 403 Forbidden.
+type ForbiddenError struct {
+	InvalidStatusError
+}
+
+func (e ForbiddenError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// ConflictError is returned when the server responds with 409 Conflict.
+type ConflictError struct {
+	InvalidStatusError
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// BadRequestError is returned when the server responds with 400 Bad Request, and the body
+// contains field errors. Other 400 responses are returned as an InvalidStatusError.
+type BadRequestError struct {
+	InvalidStatusError
+	// FieldErrors are the validation errors in the response body, if any.
+	FieldErrors FieldErrors `json:"fieldErrors,omitempty"`
+}
+
+func (e BadRequestError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// UnprocessableEntityError is returned when the server responds with 422 Unprocessable Entity.
+type UnprocessableEntityError struct {
+	InvalidStatusError
+	// FieldErrors are the validation errors in the response body, if any.
+	FieldErrors FieldErrors `json:"fieldErrors,omitempty"`
+}
+
+func (e UnprocessableEntityError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// TooManyRequestsError is returned when the server responds with 429 Too Many Requests.
+type TooManyRequestsError struct {
+	InvalidStatusError
+	// RetryAfter is the delay requested by the Retry-After header, or zero if there wasn't one.
+	RetryAfter time.Duration `json:"retryAfter,omitempty"`
+}
+
+func (e TooManyRequestsError) Unwrap() error {
+	return e.InvalidStatusError
+}
+
+// WithStatusErrorDecoder replaces the error returned for a non-success status with the result
+// of f, e.g. to decode an API's error envelope into its own error type. f is passed the error
+// that would otherwise be returned, such as an InvalidStatusError or UnauthorizedError, and
+// should return an error that wraps it, so that it can still be found with errors.As.
+func WithStatusErrorDecoder(f func(err error) error) Opt {
+	return func(c *Config) error {
+		c.StatusErrorDecoder = f
+		return nil
+	}
+}
+
+// newStatusError returns the error for a non-success response status. The header may be nil.
+// Retry-After dates are relative to the config's Clock.
+func (config *Config) newStatusError(status int, header http.Header, body string) (err error) {
+	err = config.typedStatusError(status, header, body)
+	if config.StatusErrorDecoder == nil {
+		return err
+	}
+	decoded := err
+	if perr := safely(func() error { decoded = config.StatusErrorDecoder(err); return nil }); perr != nil {
+		return perr
+	}
+	return decoded
+}
+
+func (config *Config) typedStatusError(status int, header http.Header, body string) error {
+	err := InvalidStatusError{
+		Status: status,
+		Body:   body,
+	}
+	switch status {
+	case http.StatusNotModified:
+		return NotModifiedError{InvalidStatusError: err}
+	case http.StatusBadRequest:
+		if fe := parseFieldErrors(body); fe != nil {
+			return BadRequestError{InvalidStatusError: err, FieldErrors: fe}
+		}
+	case http.StatusUnauthorized:
+		return UnauthorizedError{InvalidStatusError: err}
+	case http.StatusForbidden:
+		return ForbiddenError{InvalidStatusError: err}
+	case http.StatusConflict:
+		return ConflictError{InvalidStatusError: err}
+	case http.StatusPreconditionFailed:
+		return PreconditionFailedError{InvalidStatusError: err}
+	case http.StatusUnprocessableEntity:
+		return UnprocessableEntityError{InvalidStatusError: err, FieldErrors: parseFieldErrors(body)}
+	case http.StatusTooManyRequests:
+		return TooManyRequestsError{InvalidStatusError: err, RetryAfter: parseRetryAfter(header.Get("Retry-After"), config.clock().Now())}
+	}
+	return err
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds, or an
+// HTTP date. It returns zero if the header is missing or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
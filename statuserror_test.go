@@ -0,0 +1,102 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestStatusErrors(t *testing.T) {
+	handler := func(status int, header http.Header) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			http.Error(w, "error", status)
+		})
+	}
+	get := func(status int, header http.Header, opts ...jsonapi.Opt) error {
+		opts = append([]jsonapi.Opt{jsonapi.WithClient(testClient{Handler: handler(status, header)})}, opts...)
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items", opts...)
+		return err
+	}
+	tests := []struct {
+		status   int
+		expected any
+	}{
+		{status: http.StatusUnauthorized, expected: &jsonapi.UnauthorizedError{}},
+		{status: http.StatusForbidden, expected: &jsonapi.ForbiddenError{}},
+		{status: http.StatusConflict, expected: &jsonapi.ConflictError{}},
+		{status: http.StatusUnprocessableEntity, expected: &jsonapi.UnprocessableEntityError{}},
+		{status: http.StatusTooManyRequests, expected: &jsonapi.TooManyRequestsError{}},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := get(tt.status, nil)
+			if !errors.As(err, tt.expected) {
+				t.Fatalf("expected %T, got %T: %v", tt.expected, err, err)
+			}
+			var ise jsonapi.InvalidStatusError
+			if !errors.As(err, &ise) || ise.Status != tt.status {
+				t.Errorf("expected the error to wrap an InvalidStatusError with status %d, got %v", tt.status, err)
+			}
+		})
+	}
+	t.Run("other statuses are returned as InvalidStatusError", func(t *testing.T) {
+		if _, ok := get(http.StatusBadGateway, nil).(jsonapi.InvalidStatusError); !ok {
+			t.Error("expected InvalidStatusError")
+		}
+	})
+	t.Run("Retry-After seconds are parsed", func(t *testing.T) {
+		var tmre jsonapi.TooManyRequestsError
+		if err := get(http.StatusTooManyRequests, http.Header{"Retry-After": {"120"}}); !errors.As(err, &tmre) || tmre.RetryAfter != 2*time.Minute {
+			t.Errorf("expected a 2m RetryAfter, got %v", tmre.RetryAfter)
+		}
+	})
+	t.Run("Retry-After dates are parsed", func(t *testing.T) {
+		retryAfter := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+		var tmre jsonapi.TooManyRequestsError
+		if err := get(http.StatusTooManyRequests, http.Header{"Retry-After": {retryAfter}}); !errors.As(err, &tmre) || tmre.RetryAfter < 59*time.Minute || tmre.RetryAfter > time.Hour {
+			t.Errorf("expected a RetryAfter of about 1h, got %v", tmre.RetryAfter)
+		}
+	})
+	t.Run("Retry-After dates are relative to the configured clock", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+		retryAfter := clock.now.Add(time.Hour).Format(http.TimeFormat)
+		var tmre jsonapi.TooManyRequestsError
+		if err := get(http.StatusTooManyRequests, http.Header{"Retry-After": {retryAfter}}, jsonapi.WithClock(clock)); !errors.As(err, &tmre) || tmre.RetryAfter != time.Hour {
+			t.Errorf("expected a 1h RetryAfter, got %v", tmre.RetryAfter)
+		}
+	})
+	t.Run("streams return typed errors", func(t *testing.T) {
+		err := jsonapi.Stream(context.Background(), "/events", jsonapi.NewEventRouter(),
+			jsonapi.WithClient(testClient{Handler: handler(http.StatusUnauthorized, nil)}))
+		var ue jsonapi.UnauthorizedError
+		if !errors.As(err, &ue) {
+			t.Errorf("expected UnauthorizedError, got %v", err)
+		}
+	})
+	t.Run("the status error decoder replaces the error", func(t *testing.T) {
+		decoder := jsonapi.WithStatusErrorDecoder(func(err error) error { return fmt.Errorf("api: %w", err) })
+		err := get(http.StatusUnauthorized, nil, decoder)
+		if err == nil || !strings.HasPrefix(err.Error(), "api: ") {
+			t.Fatalf("expected the decoded error, got %v", err)
+		}
+		var ue jsonapi.UnauthorizedError
+		if !errors.As(err, &ue) {
+			t.Errorf("expected the decoded error to wrap UnauthorizedError, got %v", err)
+		}
+	})
+	t.Run("panics in the status error decoder are returned as errors", func(t *testing.T) {
+		decoder := jsonapi.WithStatusErrorDecoder(func(err error) error { panic("boom") })
+		if err := get(http.StatusUnauthorized, nil, decoder); err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Errorf("expected the panic to be returned, got %v", err)
+		}
+	})
+}
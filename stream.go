@@ -0,0 +1,46 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// PostStream posts the items to the given URL as newline delimited JSON.
+// Items are written with chunked transfer encoding as they're produced, so the
+// sequence is never held in memory. Because the body can't be replayed, the request isn't
+// retried or queued by WithOutbox.
+func PostStream[TItem, TResp any](ctx context.Context, url string, items iter.Seq[TItem], opts ...Opt) (response TResp, err error) {
+	opts = append(opts[:len(opts):len(opts)], WithContentType("application/x-ndjson"))
+	config, err := newConfig(opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to create config: %w", err)
+	}
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		for item := range items {
+			buf, err := config.marshal(item)
+			if err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to marshal item: %w", err))
+				return
+			}
+			if _, err = pw.Write(append(buf, '\n')); err != nil {
+				return
+			}
+		}
+		pw.Close()
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
+	if err != nil {
+		return response, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = -1
+	res, err := config.raw(req)
+	if err != nil {
+		return response, err
+	}
+	return decodeResponse[TResp](config, res)
+}
@@ -0,0 +1,50 @@
+package jsonapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+type ingestResponse struct {
+	Count int `json:"count"`
+}
+
+func TestPostStream(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			respond.WithError(w, "Expected application/x-ndjson content type", http.StatusBadRequest)
+			return
+		}
+		var count int
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var item map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+				respond.WithError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			count++
+		}
+		respond.WithJSON(w, ingestResponse{Count: count}, http.StatusOK)
+	})
+	items := func(yield func(map[string]any) bool) {
+		for i := 0; i < 100; i++ {
+			if !yield(map[string]any{"id": i}) {
+				return
+			}
+		}
+	}
+	resp, err := jsonapi.PostStream[map[string]any, ingestResponse](context.Background(), "/ingest", items, jsonapi.WithClient(testClient{Handler: h}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Count != 100 {
+		t.Errorf("expected 100 items, got %d", resp.Count)
+	}
+}
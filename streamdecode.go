@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// WithStreamingDecode decodes JSON array and NDJSON responses into a slice item by item as
+// the body is read, rather than reading the whole body into memory first. Since the body isn't
+// buffered, the Body field of an InvalidJSONError is empty. It has no effect with WithUnwrap
+// or WithUnwrapMeta, or when the response type is not a slice.
+func WithStreamingDecode() Opt {
+	return func(c *Config) error {
+		c.StreamingDecode = true
+		return nil
+	}
+}
+
+// WithEachItem decodes JSON array and NDJSON responses item by item, and calls f with each item
+// instead of appending it to the response, so that memory use doesn't grow with the size of the
+// response. T must be the element type of the response slice. Returning an error from f stops
+// decoding and returns the error.
+func WithEachItem[T any](f func(item T) error) Opt {
+	return func(c *Config) error {
+		c.StreamingDecode = true
+		c.onItem = func(config *Config, data json.RawMessage) error {
+			var item T
+			if err := config.unmarshalJSON(data, &item); err != nil {
+				return err
+			}
+			return safely(func() error { return f(item) })
+		}
+		return nil
+	}
+}
+
+func (config *Config) isStreamingDecode(v any) bool {
+	return config.StreamingDecode && config.Unwrap == "" && len(config.UnwrapMeta) == 0 && isSlicePointer(v)
+}
+
+func (config *Config) decodeStream(res *http.Response, contentType string, v any) error {
+	slice := reflect.ValueOf(v).Elem()
+	var index int
+	var itemErr error
+	// index is the zero-based index of the item being decoded, so it's only incremented once
+	// the item has been processed successfully.
+	err := decodeItems(res.Body, contentType, func(data json.RawMessage) error {
+		if config.onItem != nil {
+			if itemErr = config.onItem(config, data); itemErr != nil {
+				return itemErr
+			}
+			index++
+			return nil
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err := config.unmarshalJSON(data, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+		index++
+		return nil
+	})
+	if itemErr != nil {
+		return fmt.Errorf("failed to process item %d: %w", index, itemErr)
+	}
+	if err != nil {
+		return InvalidJSONError{
+			Status: res.StatusCode,
+			Err:    fmt.Errorf("failed to decode item %d: %w", index, err),
+		}
+	}
+	return config.trailer(res)
+}
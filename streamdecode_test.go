@@ -0,0 +1,92 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithStreamingDecode(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1},{"id":2},{"id":3}]`))
+	})
+	t.Run("items are appended to the response", func(t *testing.T) {
+		resp, _, err := jsonapi.Get[[]chanItem](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithStreamingDecode())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff([]chanItem{{ID: 1}, {ID: 2}, {ID: 3}}, resp); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("items are passed to the callback", func(t *testing.T) {
+		var items []chanItem
+		resp, _, err := jsonapi.Get[[]chanItem](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithEachItem(func(item chanItem) error {
+				items = append(items, item)
+				return nil
+			}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(resp) != 0 {
+			t.Errorf("expected an empty response, got %v", resp)
+		}
+		if diff := cmp.Diff([]chanItem{{ID: 1}, {ID: 2}, {ID: 3}}, items); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("callback errors stop decoding", func(t *testing.T) {
+		stop := errors.New("stop")
+		var count int
+		_, _, err := jsonapi.Get[[]chanItem](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithEachItem(func(item chanItem) error {
+				count++
+				return stop
+			}))
+		if !errors.Is(err, stop) {
+			t.Errorf("expected the callback error, got %v", err)
+		}
+		if err == nil || !strings.Contains(err.Error(), "item 0:") {
+			t.Errorf("expected the error to report item 0, got %v", err)
+		}
+		if count != 1 {
+			t.Errorf("expected 1 call, got %d", count)
+		}
+	})
+	t.Run("invalid items return InvalidJSONError", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"id":1},{"id":"a"}]`))
+		})
+		_, _, err := jsonapi.Get[[]chanItem](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithStreamingDecode())
+		if _, ok := err.(jsonapi.InvalidJSONError); !ok {
+			t.Errorf("expected InvalidJSONError, got %v", err)
+		}
+		if err == nil || !strings.Contains(err.Error(), "failed to decode item 1:") {
+			t.Errorf("expected the error to report item 1, got %v", err)
+		}
+	})
+	t.Run("malformed items report their index", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[1,"x",3]`))
+		})
+		_, _, err := jsonapi.Get[[]int](context.Background(), "/items",
+			jsonapi.WithClient(testClient{Handler: h}),
+			jsonapi.WithStreamingDecode())
+		if err == nil || !strings.Contains(err.Error(), "failed to decode item 1:") {
+			t.Errorf("expected the error to report item 1, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package jsonapi
+
+import (
+	"io"
+	"net/http"
+)
+
+// WithResponseTee copies response bodies to w as they're read, e.g. to archive payloads.
+// Only the bytes read by the client are copied, so bodies that are discarded without being
+// read, such as the body of a 404 returned by Get, may not be written in full. An error writing
+// to w is returned as an error reading the response body.
+//
+// w must be safe for concurrent use if the Opt is shared between concurrent requests.
+func WithResponseTee(w io.Writer) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				res, err := next.Do(req)
+				if err != nil {
+					return res, err
+				}
+				res.Body = teeReadCloser{Reader: io.TeeReader(res.Body, w), Closer: res.Body}
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,40 @@
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWithResponseTee(t *testing.T) {
+	client := jsonapi.WithClient(testClient{Handler: createTestRoutes()})
+
+	t.Run("the response body is copied", func(t *testing.T) {
+		var buf bytes.Buffer
+		resp, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithResponseTee(&buf))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(resp.Items) != 2 {
+			t.Errorf("expected the response to be decoded, got %+v", resp)
+		}
+		if buf.String() != `{"items":["item1","item2"]}`+"\n" {
+			t.Errorf("unexpected copy %q", buf.String())
+		}
+	})
+	t.Run("write errors are returned", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", client, jsonapi.WithResponseTee(failingWriter{}))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package jsonapi
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewTenantClients creates a cache of up to maxSize clients, each built by build the first
+// time its tenant is requested. Clients are rebuilt once they're older than ttl.
+// A ttl of zero means that clients don't expire.
+func NewTenantClients[T any](maxSize int, ttl time.Duration, build func(ctx context.Context, tenant string) (T, error)) *TenantClients[T] {
+	return &TenantClients[T]{
+		build:   build,
+		maxSize: maxSize,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		now:     time.Now,
+		m:       &sync.Mutex{},
+	}
+}
+
+// TenantClients lazily builds and caches a client per tenant, e.g. an HTTPCaller with the
+// tenant's credentials. When the cache is full, the least recently used client is evicted.
+type TenantClients[T any] struct {
+	// OnEvict is called when a client is evicted or expires, e.g. to release its resources.
+	OnEvict func(tenant string, client T)
+	build   func(ctx context.Context, tenant string) (T, error)
+	maxSize int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+	m       *sync.Mutex
+}
+
+type tenantEntry[T any] struct {
+	tenant  string
+	client  T
+	err     error
+	expires time.Time
+	ready   chan struct{}
+}
+
+// Get returns the client for the tenant, building it if required. Concurrent calls for the
+// same tenant share a single build. Failed builds are not cached. The build is not cancelled
+// if ctx is done, so that other callers for the tenant can still use the result.
+func (tc *TenantClients[T]) Get(ctx context.Context, tenant string) (client T, err error) {
+	tc.m.Lock()
+	e, expired, ok := tc.get(tenant)
+	if !ok {
+		e = &tenantEntry[T]{tenant: tenant, ready: make(chan struct{})}
+		tc.entries[tenant] = tc.lru.PushFront(e)
+		evicted := append(expired, tc.evict()...)
+		tc.m.Unlock()
+		tc.notify(evicted)
+		go tc.buildEntry(context.WithoutCancel(ctx), e)
+	} else {
+		tc.m.Unlock()
+	}
+	select {
+	case <-e.ready:
+		return e.client, e.err
+	case <-ctx.Done():
+		return client, ctx.Err()
+	}
+}
+
+// get returns the cached entry for the tenant. If the entry has expired, it is removed and returned as expired.
+func (tc *TenantClients[T]) get(tenant string) (e *tenantEntry[T], expired []*tenantEntry[T], ok bool) {
+	elem, ok := tc.entries[tenant]
+	if !ok {
+		return nil, nil, false
+	}
+	e = elem.Value.(*tenantEntry[T])
+	select {
+	case <-e.ready:
+		if e.err == nil && tc.ttl > 0 && !tc.now().Before(e.expires) {
+			tc.remove(elem)
+			return nil, []*tenantEntry[T]{e}, false
+		}
+	default:
+	}
+	tc.lru.MoveToFront(elem)
+	return e, nil, true
+}
+
+func (tc *TenantClients[T]) buildEntry(ctx context.Context, e *tenantEntry[T]) {
+	defer close(e.ready)
+	e.err = safely(func() (err error) {
+		e.client, err = tc.build(ctx, e.tenant)
+		return err
+	})
+	tc.m.Lock()
+	defer tc.m.Unlock()
+	if e.err != nil {
+		if elem, ok := tc.entries[e.tenant]; ok && elem.Value == e {
+			tc.remove(elem)
+		}
+		return
+	}
+	e.expires = tc.now().Add(tc.ttl)
+}
+
+// evict removes the least recently used entries until the cache is within its maximum size.
+func (tc *TenantClients[T]) evict() (evicted []*tenantEntry[T]) {
+	for tc.maxSize > 0 && tc.lru.Len() > tc.maxSize {
+		elem := tc.lru.Back()
+		tc.remove(elem)
+		evicted = append(evicted, elem.Value.(*tenantEntry[T]))
+	}
+	return evicted
+}
+
+func (tc *TenantClients[T]) remove(elem *list.Element) {
+	tc.lru.Remove(elem)
+	delete(tc.entries, elem.Value.(*tenantEntry[T]).tenant)
+}
+
+// notify calls OnEvict for each entry that was built successfully. Entries that are still
+// being built are notified once the build completes.
+func (tc *TenantClients[T]) notify(entries []*tenantEntry[T]) {
+	if tc.OnEvict == nil {
+		return
+	}
+	onEvict := func(e *tenantEntry[T]) {
+		<-e.ready
+		if e.err == nil {
+			safely(func() error { tc.OnEvict(e.tenant, e.client); return nil })
+		}
+	}
+	for _, e := range entries {
+		select {
+		case <-e.ready:
+			onEvict(e)
+		default:
+			go onEvict(e)
+		}
+	}
+}
+
+// Remove evicts the tenant's client, e.g. after its credentials have changed.
+func (tc *TenantClients[T]) Remove(tenant string) {
+	tc.m.Lock()
+	elem, ok := tc.entries[tenant]
+	if ok {
+		tc.remove(elem)
+	}
+	tc.m.Unlock()
+	if ok {
+		tc.notify([]*tenantEntry[T]{elem.Value.(*tenantEntry[T])})
+	}
+}
+
+// Len returns the number of cached clients.
+func (tc *TenantClients[T]) Len() int {
+	tc.m.Lock()
+	defer tc.m.Unlock()
+	return tc.lru.Len()
+}
@@ -0,0 +1,99 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTenantClients(t *testing.T) {
+	var builds atomic.Int64
+	build := func(ctx context.Context, tenant string) (*jsonapi.HTTPCaller, error) {
+		builds.Add(1)
+		if tenant == "invalid" {
+			return nil, errors.New("unknown tenant")
+		}
+		return jsonapi.NewCaller(jsonapi.WithRequestHeader("X-Tenant-ID", tenant)), nil
+	}
+
+	t.Run("clients are built once per tenant", func(t *testing.T) {
+		builds.Store(0)
+		tc := jsonapi.NewTenantClients(10, 0, build)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := tc.Get(context.Background(), "a"); err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		if builds.Load() != 1 {
+			t.Errorf("expected 1 build, got %d", builds.Load())
+		}
+	})
+	t.Run("the least recently used client is evicted", func(t *testing.T) {
+		tc := jsonapi.NewTenantClients(2, 0, build)
+		var evicted []string
+		tc.OnEvict = func(tenant string, client *jsonapi.HTTPCaller) {
+			evicted = append(evicted, tenant)
+		}
+		for _, tenant := range []string{"a", "b", "a", "c"} {
+			if _, err := tc.Get(context.Background(), tenant); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if diff := cmp.Diff([]string{"b"}, evicted); diff != "" {
+			t.Error(diff)
+		}
+		if tc.Len() != 2 {
+			t.Errorf("expected 2 clients, got %d", tc.Len())
+		}
+	})
+	t.Run("expired clients are rebuilt", func(t *testing.T) {
+		builds.Store(0)
+		tc := jsonapi.NewTenantClients(2, time.Millisecond, build)
+		tc.Get(context.Background(), "a")
+		time.Sleep(5 * time.Millisecond)
+		tc.Get(context.Background(), "a")
+		if builds.Load() != 2 {
+			t.Errorf("expected 2 builds, got %d", builds.Load())
+		}
+	})
+	t.Run("builds are not cancelled with the first caller", func(t *testing.T) {
+		release := make(chan struct{})
+		tc := jsonapi.NewTenantClients(2, 0, func(ctx context.Context, tenant string) (*jsonapi.HTTPCaller, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return jsonapi.NewCaller(), nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := tc.Get(ctx, "a"); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		close(release)
+		if _, err := tc.Get(context.Background(), "a"); err != nil {
+			t.Errorf("expected the shared build to succeed, got %v", err)
+		}
+	})
+	t.Run("failed builds are not cached", func(t *testing.T) {
+		tc := jsonapi.NewTenantClients(2, 0, build)
+		if _, err := tc.Get(context.Background(), "invalid"); err == nil {
+			t.Error("expected an error")
+		}
+		if tc.Len() != 0 {
+			t.Errorf("expected no clients, got %d", tc.Len())
+		}
+	})
+}
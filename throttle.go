@@ -0,0 +1,107 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NewAdaptiveThrottle creates a per-host throttle that starts at max requests per second.
+func NewAdaptiveThrottle(min, max float64) *AdaptiveThrottle {
+	return &AdaptiveThrottle{
+		Min:      min,
+		Max:      max,
+		Increase: 1,
+		Decrease: 0.5,
+		hosts:    map[string]*hostThrottle{},
+		m:        &sync.Mutex{},
+	}
+}
+
+// AdaptiveThrottle paces requests to each host using additive-increase, multiplicative-decrease.
+// When a host responds with 429 or 503, its rate is multiplied by Decrease. Each successful
+// response adds Increase to the rate, up to Max.
+type AdaptiveThrottle struct {
+	// Min and Max are the bounds of the rate, in requests per second.
+	Min, Max float64
+	// Increase is added to the rate after each successful response.
+	Increase float64
+	// Decrease multiplies the rate after each 429 or 503 response.
+	Decrease float64
+	hosts    map[string]*hostThrottle
+	m        *sync.Mutex
+}
+
+type hostThrottle struct {
+	rate float64
+	next time.Time
+}
+
+// WithAdaptiveThrottle paces requests using the throttle, waiting using the config's Clock.
+// Create the throttle once and share it between calls. The Opt returns an error if Min is not
+// greater than zero, or Max is less than Min.
+func WithAdaptiveThrottle(t *AdaptiveThrottle) Opt {
+	return func(c *Config) error {
+		if t.Min <= 0 || t.Max < t.Min {
+			return fmt.Errorf("throttle rate must be greater than zero, with a max of at least the min, got min %v and max %v", t.Min, t.Max)
+		}
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return t.intercept(next, c.clock())
+		})
+		return nil
+	}
+}
+
+// Rate returns the current rate for the host, in requests per second.
+func (t *AdaptiveThrottle) Rate(host string) float64 {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.host(host).rate
+}
+
+func (t *AdaptiveThrottle) host(host string) *hostThrottle {
+	h, ok := t.hosts[host]
+	if !ok {
+		h = &hostThrottle{rate: t.Max}
+		t.hosts[host] = h
+	}
+	return h
+}
+
+func (t *AdaptiveThrottle) reserve(host string, now time.Time) (wait time.Duration) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	h := t.host(host)
+	if h.next.Before(now) {
+		h.next = now
+	}
+	wait = h.next.Sub(now)
+	h.next = h.next.Add(time.Duration(float64(time.Second) / h.rate))
+	return wait
+}
+
+func (t *AdaptiveThrottle) observe(host string, status int) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	h := t.host(host)
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		h.rate = max(t.Min, h.rate*t.Decrease)
+		return
+	}
+	h.rate = min(t.Max, h.rate+t.Increase)
+}
+
+func (t *AdaptiveThrottle) intercept(next Doer, clock Clock) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := clock.Sleep(req.Context(), t.reserve(req.URL.Host, clock.Now())); err != nil {
+			return nil, err
+		}
+		res, err := next.Do(req)
+		if err != nil {
+			return res, err
+		}
+		t.observe(req.URL.Host, res.StatusCode)
+		return res, nil
+	})
+}
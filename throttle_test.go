@@ -0,0 +1,67 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAdaptiveThrottle(t *testing.T) {
+	h, _ := failingHandler(2, http.StatusTooManyRequests)
+	throttle := jsonapi.NewAdaptiveThrottle(1, 1000)
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: h}),
+		jsonapi.WithAdaptiveThrottle(throttle),
+	}
+	for i := 0; i < 2; i++ {
+		_, _, _ = jsonapi.Get[itemsGetResponse](context.Background(), "http://example.com/items", opts...)
+	}
+	if rate := throttle.Rate("example.com"); rate != 250 {
+		t.Errorf("expected the rate to be halved twice to 250, got %v", rate)
+	}
+	if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://example.com/items", opts...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rate := throttle.Rate("example.com"); rate != 251 {
+		t.Errorf("expected the rate to increase to 251, got %v", rate)
+	}
+	if rate := throttle.Rate("other.example.com"); rate != 1000 {
+		t.Errorf("expected other hosts to be unaffected, got %v", rate)
+	}
+}
+
+func TestAdaptiveThrottleClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	opts := []jsonapi.Opt{
+		jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+		jsonapi.WithAdaptiveThrottle(jsonapi.NewAdaptiveThrottle(2, 2)),
+		jsonapi.WithClock(clock),
+	}
+	for i := 0; i < 3; i++ {
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://example.com/items/get/ok", opts...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if diff := cmp.Diff([]time.Duration{0, 500 * time.Millisecond, 500 * time.Millisecond}, clock.sleeps); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestAdaptiveThrottleInvalid(t *testing.T) {
+	for _, throttle := range []*jsonapi.AdaptiveThrottle{
+		jsonapi.NewAdaptiveThrottle(0, 0),
+		jsonapi.NewAdaptiveThrottle(1, 0),
+		jsonapi.NewAdaptiveThrottle(-1, 10),
+	} {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "http://example.com/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+			jsonapi.WithAdaptiveThrottle(throttle))
+		if err == nil {
+			t.Errorf("min %v, max %v: expected an error", throttle.Min, throttle.Max)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	// TimeFormatUnix is a time format for Unix timestamps in seconds, encoded as JSON numbers.
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli is a time format for Unix timestamps in milliseconds, encoded as JSON numbers.
+	TimeFormatUnixMilli = "unixmilli"
+	// TimeFormatDate is a date-only time format, e.g. 2006-01-02.
+	TimeFormatDate = time.DateOnly
+)
+
+// WithTimeFormat sets the formats used to encode and decode time.Time values, so that fields
+// don't need wrapper types for APIs that don't use RFC 3339. The first format is used when
+// encoding. When decoding, each format is tried in turn, followed by RFC 3339.
+// Formats are time layouts, such as time.RFC1123, or TimeFormatUnix and TimeFormatUnixMilli.
+func WithTimeFormat(formats ...string) Opt {
+	return func(c *Config) error {
+		if len(formats) == 0 {
+			return fmt.Errorf("at least one time format is required")
+		}
+		c.TypeHooks = append(c.TypeHooks, TypeHook{
+			Type: reflect.TypeFor[time.Time](),
+			Decode: func(v any) (any, error) {
+				t, err := parseTime(v, formats)
+				if err != nil {
+					return nil, err
+				}
+				return t.Format(time.RFC3339Nano), nil
+			},
+			Encode: func(v any) (any, error) {
+				s, ok := v.(string)
+				if !ok {
+					return v, nil
+				}
+				t, err := time.Parse(time.RFC3339Nano, s)
+				if err != nil {
+					return nil, err
+				}
+				return formatTime(t, formats[0]), nil
+			},
+		})
+		return nil
+	}
+}
+
+func parseTime(v any, formats []string) (t time.Time, err error) {
+	for _, format := range formats {
+		switch value := v.(type) {
+		case json.Number:
+			n, err := value.Int64()
+			if err != nil {
+				continue
+			}
+			switch format {
+			case TimeFormatUnix:
+				return time.Unix(n, 0).UTC(), nil
+			case TimeFormatUnixMilli:
+				return time.UnixMilli(n).UTC(), nil
+			}
+		case string:
+			switch format {
+			case TimeFormatUnix, TimeFormatUnixMilli:
+				continue
+			}
+			if t, err = time.Parse(format, value); err == nil {
+				return t, nil
+			}
+		}
+	}
+	if s, ok := v.(string); ok {
+		if t, err = time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("time %v does not match any of the formats %q", v, formats)
+}
+
+func formatTime(t time.Time, format string) any {
+	switch format {
+	case TimeFormatUnix:
+		return json.Number(strconv.FormatInt(t.Unix(), 10))
+	case TimeFormatUnixMilli:
+		return json.Number(strconv.FormatInt(t.UnixMilli(), 10))
+	}
+	return t.Format(format)
+}
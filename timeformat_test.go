@@ -0,0 +1,82 @@
+package jsonapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+type timestamps struct {
+	Created time.Time
+}
+
+type event struct {
+	timestamps
+	Updated *time.Time  `json:"updated"`
+	History []time.Time `json:"history"`
+}
+
+func echoClient(received *string) testClient {
+	return testClient{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})}
+}
+
+func TestWithTimeFormat(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	e := event{
+		timestamps: timestamps{Created: ts},
+		Updated:    &ts,
+		History:    []time.Time{ts},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   jsonapi.TimeFormatUnixMilli,
+			expected: `{"Created":1704164645000,"history":[1704164645000],"updated":1704164645000}`,
+		},
+		{
+			format:   time.RFC1123,
+			expected: `{"Created":"Tue, 02 Jan 2024 03:04:05 UTC","history":["Tue, 02 Jan 2024 03:04:05 UTC"],"updated":"Tue, 02 Jan 2024 03:04:05 UTC"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var received string
+			resp, err := jsonapi.Post[event, event](ctx, "/events", e, jsonapi.WithClient(echoClient(&received)), jsonapi.WithTimeFormat(tt.format))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if received != tt.expected {
+				t.Errorf("expected request %s, got %s", tt.expected, received)
+			}
+			if diff := cmp.Diff(e, resp, cmp.AllowUnexported(event{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("date-only values can be decoded", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"Created":"2024-01-02"}`))
+		})
+		resp, _, err := jsonapi.Get[event](ctx, "/events/1", jsonapi.WithClient(testClient{Handler: h}), jsonapi.WithTimeFormat(jsonapi.TimeFormatDate))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !resp.Created.Equal(time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected time %v", resp.Created)
+		}
+	})
+}
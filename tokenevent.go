@@ -0,0 +1,47 @@
+package jsonapi
+
+import (
+	"fmt"
+	"time"
+)
+
+// TokenEventType is the type of a TokenEvent.
+type TokenEventType string
+
+const (
+	// TokenFetched is a token fetched for the first time.
+	TokenFetched TokenEventType = "fetched"
+	// TokenRefreshed is a token fetched to replace an expiring token.
+	TokenRefreshed TokenEventType = "refreshed"
+	// TokenFetchFailed is a token that could not be fetched, validated, or parsed.
+	TokenFetchFailed TokenEventType = "fetch_failed"
+)
+
+// TokenEvent reports a change in the state of the token used by AuthMiddleware.
+type TokenEvent struct {
+	Type TokenEventType `json:"type"`
+	// Latency is the time taken to fetch and validate the token.
+	Latency time.Duration `json:"latency"`
+	// Expires is the expiry time of the new token, or zero if the fetch failed.
+	Expires time.Time `json:"expires,omitempty"`
+	Err     error     `json:"-"`
+}
+
+// WithTokenEvents calls f when the auth middleware added by WithAuthMiddleware fetches or
+// refreshes a token, or fails to, so that token health can be exported as metrics and
+// identity provider issues can be alerted on. It must be applied after WithAuthMiddleware.
+func WithTokenEvents(f func(e TokenEvent)) Opt {
+	return func(c *Config) error {
+		var found bool
+		for _, m := range c.Middleware {
+			if am, ok := m.(*AuthMiddleware); ok {
+				am.OnTokenEvent = f
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("failed to set token event handler: no auth middleware, use WithAuthMiddleware first")
+		}
+		return nil
+	}
+}
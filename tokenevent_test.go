@@ -0,0 +1,51 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithTokenEvents(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := "header." + base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expires.Unix()))) + ".signature"
+
+	t.Run("fetched tokens are reported with their expiry", func(t *testing.T) {
+		var events []jsonapi.TokenEvent
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+			jsonapi.WithAuthMiddleware(func() (string, error) { return token, nil }),
+			jsonapi.WithTokenEvents(func(e jsonapi.TokenEvent) { events = append(events, e) }))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(events) != 1 || events[0].Type != jsonapi.TokenFetched || !events[0].Expires.Equal(expires) || events[0].Err != nil {
+			t.Errorf("unexpected events %+v", events)
+		}
+	})
+	t.Run("failures are reported", func(t *testing.T) {
+		var events []jsonapi.TokenEvent
+		idpErr := errors.New("identity provider unavailable")
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok",
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}),
+			jsonapi.WithAuthMiddleware(func() (string, error) { return "", idpErr }),
+			jsonapi.WithTokenEvents(func(e jsonapi.TokenEvent) { events = append(events, e) }))
+		if !errors.Is(err, idpErr) {
+			t.Fatalf("expected the fetch error, got %v", err)
+		}
+		if len(events) != 1 || events[0].Type != jsonapi.TokenFetchFailed || !errors.Is(events[0].Err, idpErr) {
+			t.Errorf("unexpected events %+v", events)
+		}
+	})
+	t.Run("auth middleware is required", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items/get/ok", jsonapi.WithTokenEvents(func(e jsonapi.TokenEvent) {}))
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
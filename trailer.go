@@ -0,0 +1,50 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// TrailerMiddleware is an optional interface that Middleware can implement to inspect
+// HTTP trailers. Trailers are only populated once the response body has been read,
+// so Trailer is called after the body has been consumed, and before it is decoded.
+type TrailerMiddleware interface {
+	Trailer(res *http.Response) error
+}
+
+// WithTrailer calls f with the response trailers once the response body has been read.
+// It is not called by Raw, since Raw does not consume the body. Raw callers can read
+// res.Trailer directly after reading the body.
+func WithTrailer(f func(trailer http.Header) error) Opt {
+	return func(c *Config) error {
+		c.Middleware = append(c.Middleware, trailerMiddleware(f))
+		return nil
+	}
+}
+
+type trailerMiddleware func(trailer http.Header) error
+
+func (m trailerMiddleware) Request(req *http.Request) error {
+	return nil
+}
+
+func (m trailerMiddleware) Response(res *http.Response) error {
+	return nil
+}
+
+func (m trailerMiddleware) Trailer(res *http.Response) error {
+	return m(res.Trailer)
+}
+
+func (config *Config) trailer(res *http.Response) error {
+	for _, m := range config.middleware() {
+		tm, ok := m.(TrailerMiddleware)
+		if !ok {
+			continue
+		}
+		if err := safely(func() error { return tm.Trailer(res) }); err != nil {
+			return fmt.Errorf("middleware failed to process trailer: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWithTrailer(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Checksum")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":["item1","item2"]}`))
+		w.Header().Set("Checksum", "abc")
+	}))
+	defer s.Close()
+
+	t.Run("trailers are available after the body is read", func(t *testing.T) {
+		var checksum string
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithTrailer(func(trailer http.Header) error {
+			checksum = trailer.Get("Checksum")
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if checksum != "abc" {
+			t.Errorf("expected checksum 'abc', got %q", checksum)
+		}
+	})
+	t.Run("trailer errors are returned", func(t *testing.T) {
+		expected := errors.New("checksum mismatch")
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL, jsonapi.WithTrailer(func(trailer http.Header) error {
+			return expected
+		}))
+		if !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+}
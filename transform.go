@@ -0,0 +1,92 @@
+package jsonapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// Transformer rewrites bodies between the wire format of a profile and the format expected by
+// Go types, e.g. to rename legacy fields, so that compatibility shims are kept out of business code.
+type Transformer struct {
+	// Request rewrites a request body into the wire format. If nil, request bodies are unchanged.
+	Request func(body []byte) ([]byte, error)
+	// Response rewrites a response body before it's decoded. If nil, response bodies are unchanged.
+	Response func(body []byte) ([]byte, error)
+}
+
+// WithTransformers applies the Transformer registered for the profile of each request and
+// response body. The profile is the value of the header, if header is not empty and the header
+// is present, otherwise it's the profile parameter of the Content-Type, e.g.
+// "application/json; profile=legacy".
+//
+// The request profile can be set using WithContentType or WithRequestHeader.
+func WithTransformers(header string, transformers map[string]Transformer) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if t, ok := transformers[bodyProfile(req.Header, header)]; ok && t.Request != nil {
+					if err := transformRequest(req, t.Request); err != nil {
+						return nil, err
+					}
+				}
+				res, err := next.Do(req)
+				if err != nil {
+					return res, err
+				}
+				if t, ok := transformers[bodyProfile(res.Header, header)]; ok && t.Response != nil {
+					if err = transformResponse(res, t.Response); err != nil {
+						return nil, err
+					}
+				}
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
+
+func bodyProfile(h http.Header, header string) string {
+	if header != "" {
+		if profile := h.Get(header); profile != "" {
+			return profile
+		}
+	}
+	_, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return params["profile"]
+}
+
+func transformRequest(req *http.Request, f func(body []byte) ([]byte, error)) error {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return err
+	}
+	if err = safely(func() (err error) { body, err = f(body); return err }); err != nil {
+		return fmt.Errorf("failed to transform request body: %w", err)
+	}
+	setRequestBody(req, body)
+	return nil
+}
+
+func transformResponse(res *http.Response, f func(body []byte) ([]byte, error)) error {
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err = safely(func() (err error) { body, err = f(body); return err }); err != nil {
+		return fmt.Errorf("failed to transform response body: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	if res.Header.Get("Content-Length") != "" {
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return nil
+}
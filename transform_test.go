@@ -0,0 +1,80 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type customer struct {
+	FullName string `json:"fullName"`
+}
+
+// rename returns a transform that renames a top-level JSON field.
+func rename(from, to string) func(body []byte) ([]byte, error) {
+	return func(body []byte) ([]byte, error) {
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			return nil, err
+		}
+		m[to] = m[from]
+		delete(m, from)
+		return json.Marshal(m)
+	}
+}
+
+func TestWithTransformers(t *testing.T) {
+	var received string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json; profile=legacy")
+		w.Write([]byte(`{"name":"Alice"}`))
+	})
+	transformers := map[string]jsonapi.Transformer{
+		"legacy": {
+			Request:  rename("fullName", "name"),
+			Response: rename("name", "fullName"),
+		},
+		"broken": {
+			Request: func(body []byte) ([]byte, error) { return nil, errors.New("broken") },
+		},
+	}
+	client := jsonapi.WithClient(testClient{Handler: h})
+
+	t.Run("bodies are transformed using the Content-Type profile", func(t *testing.T) {
+		resp, err := jsonapi.Post[customer, customer](context.Background(), "/customers", customer{FullName: "Bob"},
+			client, jsonapi.WithContentType("application/json; profile=legacy"), jsonapi.WithTransformers("", transformers))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if received != `{"name":"Bob"}` {
+			t.Errorf("expected the request to be transformed, got %s", received)
+		}
+		if resp.FullName != "Alice" {
+			t.Errorf("expected the response to be transformed, got %+v", resp)
+		}
+	})
+	t.Run("the profile header takes precedence", func(t *testing.T) {
+		_, err := jsonapi.Post[customer, customer](context.Background(), "/customers", customer{FullName: "Bob"},
+			client, jsonapi.WithRequestHeader("X-Profile", "none"), jsonapi.WithTransformers("X-Profile", transformers))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if received != `{"fullName":"Bob"}` {
+			t.Errorf("expected the request not to be transformed, got %s", received)
+		}
+	})
+	t.Run("transformer errors are returned", func(t *testing.T) {
+		_, err := jsonapi.Post[customer, customer](context.Background(), "/customers", customer{},
+			client, jsonapi.WithRequestHeader("X-Profile", "broken"), jsonapi.WithTransformers("X-Profile", transformers))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package jsonapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// WithProxy sends requests through the proxy at proxyURL.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithProxy(proxyURL *url.URL) Opt {
+	return withTransport(func(t *http.Transport) error {
+		t.Proxy = http.ProxyURL(proxyURL)
+		return nil
+	})
+}
+
+// WithRootCAs verifies server certificates using the given pool, instead of the system roots.
+// It is a no-op if the underlying Doer is not an *http.Client with an *http.Transport.
+func WithRootCAs(pool *x509.CertPool) Opt {
+	return withTransport(func(t *http.Transport) error {
+		t.TLSClientConfig.RootCAs = pool
+		return nil
+	})
+}
+
+// WithCABundle verifies server certificates using the PEM encoded certificates in the file.
+// The file is read once, when the Opt is first applied.
+func WithCABundle(fileName string) Opt {
+	var opt Opt
+	var err error
+	var once sync.Once
+	return func(c *Config) error {
+		once.Do(func() {
+			var pem []byte
+			pem, err = os.ReadFile(fileName)
+			if err != nil {
+				err = fmt.Errorf("failed to read CA bundle: %w", err)
+				return
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				err = fmt.Errorf("failed to read CA bundle: no certificates found in %q", fileName)
+				return
+			}
+			opt = WithRootCAs(pool)
+		})
+		if err != nil {
+			return err
+		}
+		return opt(c)
+	}
+}
+
+// withTransport copies the *http.Client and its *http.Transport, and calls f to modify
+// the copy, so that the client passed to WithClient, and the default client, are not modified.
+//
+// The modified transport is reused each time the Opt is applied to a client with the same
+// transport, so that calls sharing the Opt share a connection pool.
+func withTransport(f func(t *http.Transport) error) Opt {
+	return configureTransport(nil, f)
+}
+
+// transportGuard is a security option that must not be silently skipped, see withTransportGuard.
+type transportGuard struct {
+	// name is the name of the Opt, used in errors.
+	name string
+	// check, if set, is called before each request with the transport that sends it.
+	check func(req *http.Request, t *http.Transport) error
+}
+
+// withTransportGuard is like withTransport, but the Opt returns an error if the transport can't
+// be modified, and requests fail if a later option replaces the modified transport, e.g. WithClient.
+func withTransportGuard(guard transportGuard, f func(t *http.Transport) error) Opt {
+	return configureTransport(&guard, f)
+}
+
+func configureTransport(guard *transportGuard, f func(t *http.Transport) error) Opt {
+	var m sync.Mutex
+	transports := map[*http.Transport]*http.Transport{}
+	return func(c *Config) error {
+		if c.Client == nil {
+			c.Client = defaultClient
+		}
+		httpc, ok := c.Client.(*http.Client)
+		if !ok {
+			if guard != nil {
+				return fmt.Errorf("%s requires the client to be an *http.Client, got %T", guard.name, c.Client)
+			}
+			return nil
+		}
+		rt := httpc.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		base, ok := rt.(*http.Transport)
+		if !ok {
+			if guard != nil {
+				return fmt.Errorf("%s requires the client's transport to be an *http.Transport, got %T, apply it before WithRoundTripperMiddleware", guard.name, rt)
+			}
+			return nil
+		}
+		m.Lock()
+		defer m.Unlock()
+		t, ok := transports[base]
+		if !ok {
+			t = base.Clone()
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			}
+			if err := f(t); err != nil {
+				return err
+			}
+			transports[base] = t
+		}
+		clone := *httpc
+		clone.Transport = t
+		c.Client = &clone
+		// Once a guard is installed, only transports derived from the guarded transport are
+		// recorded, so that checkTransport fails if the guarded transport was replaced.
+		if len(c.transportGuards) == 0 || rt == c.transport {
+			c.transport = t
+			c.guardedTransport = t
+		}
+		if guard != nil {
+			c.transportGuards = append(c.transportGuards, *guard)
+		}
+		return nil
+	}
+}
+
+// checkTransport returns an error if a transport guard was installed, but the client no longer
+// uses the guarded transport, or if a guard rejects the request.
+func (config *Config) checkTransport(req *http.Request) error {
+	if len(config.transportGuards) == 0 {
+		return nil
+	}
+	httpc, ok := config.Client.(*http.Client)
+	if !ok || httpc.Transport != config.transport {
+		names := make([]string, len(config.transportGuards))
+		for i, g := range config.transportGuards {
+			names[i] = g.name
+		}
+		return fmt.Errorf("%s can't be applied, because the client or its transport was replaced by a later option, such as WithClient", strings.Join(names, ", "))
+	}
+	for _, g := range config.transportGuards {
+		if g.check == nil {
+			continue
+		}
+		if err := g.check(req, config.guardedTransport); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package jsonapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// TransportError is a classification of an error that prevented a response from being received.
+type TransportError int
+
+const (
+	TransportErrorUnknown TransportError = iota
+	// TransportErrorConnectionRefused is a refused connection, e.g. the server isn't listening.
+	TransportErrorConnectionRefused
+	// TransportErrorConnectionReset is a connection that was reset or closed by the server.
+	TransportErrorConnectionReset
+	// TransportErrorDNS is a failure to resolve the host name.
+	TransportErrorDNS
+	// TransportErrorTLS is a TLS handshake or protocol failure.
+	TransportErrorTLS
+	// TransportErrorCertificate is a server certificate that failed verification or pinning.
+	TransportErrorCertificate
+	// TransportErrorTimeout is a dial, handshake or response header timeout.
+	TransportErrorTimeout
+	// TransportErrorPolicy is a request rejected by the client, e.g. by WithAllowedHosts or
+	// WithBlockPrivateNetworks, or because the client was closed. It is never retried.
+	TransportErrorPolicy
+)
+
+func (e TransportError) String() string {
+	switch e {
+	case TransportErrorConnectionRefused:
+		return "connection_refused"
+	case TransportErrorConnectionReset:
+		return "connection_reset"
+	case TransportErrorDNS:
+		return "dns"
+	case TransportErrorTLS:
+		return "tls"
+	case TransportErrorCertificate:
+		return "certificate"
+	case TransportErrorTimeout:
+		return "timeout"
+	case TransportErrorPolicy:
+		return "policy"
+	}
+	return "unknown"
+}
+
+// ClassifyTransportError returns the classification of an error returned by a Doer.
+func ClassifyTransportError(err error) TransportError {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return TransportErrorUnknown
+	}
+	var certificateErrors = []any{
+		new(*tls.CertificateVerificationError),
+		new(x509.UnknownAuthorityError),
+		new(x509.HostnameError),
+		new(x509.CertificateInvalidError),
+		new(PinnedCertificateError),
+	}
+	for _, target := range certificateErrors {
+		if errors.As(err, target) {
+			return TransportErrorCertificate
+		}
+	}
+	if isPolicyError(err) {
+		return TransportErrorPolicy
+	}
+	var tlsErrors = []any{
+		new(tls.RecordHeaderError),
+		new(tls.AlertError),
+	}
+	for _, target := range tlsErrors {
+		if errors.As(err, target) {
+			return TransportErrorTLS
+		}
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return TransportErrorDNS
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return TransportErrorConnectionRefused
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return TransportErrorConnectionReset
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return TransportErrorTimeout
+	}
+	return TransportErrorUnknown
+}
+
+// RetryDecision is the decision to retry a request that failed with a transport error.
+type RetryDecision int
+
+const (
+	// RetryDefault uses the decision of DefaultTransportErrorRetry.
+	RetryDefault RetryDecision = iota
+	// RetryNever doesn't retry the request.
+	RetryNever
+	// RetryWithBackoff retries the request after the policy's backoff delay.
+	RetryWithBackoff
+	// RetryImmediately retries the request without a delay.
+	RetryImmediately
+)
+
+// WithTransportErrorRetry decides whether requests that fail with a transport error are retried,
+// see RetryPolicy.TransportErrorRetry. Use with WithRetry. Requests rejected by policy, see
+// TransportErrorPolicy, are never retried, and f isn't called for them.
+func WithTransportErrorRetry(f func(kind TransportError, err error) RetryDecision) Opt {
+	return func(c *Config) error {
+		c.Retry.TransportErrorRetry = f
+		return nil
+	}
+}
+
+// DefaultTransportErrorRetry never retries certificate and TLS errors, requests rejected by
+// policy, or host names that don't exist, since repeating the request won't help. Reset connections are retried immediately,
+// since they're typically caused by the server closing an idle connection. Other network
+// errors are retried with backoff.
+func DefaultTransportErrorRetry(kind TransportError, err error) RetryDecision {
+	switch kind {
+	case TransportErrorCertificate, TransportErrorTLS, TransportErrorPolicy:
+		return RetryNever
+	case TransportErrorConnectionReset:
+		return RetryImmediately
+	case TransportErrorDNS:
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return RetryNever
+		}
+		return RetryWithBackoff
+	}
+	if isUnreachable(err) {
+		return RetryWithBackoff
+	}
+	return RetryNever
+}
+
+func (p RetryPolicy) transportErrorRetry(err error) (decision RetryDecision, perr error) {
+	kind := ClassifyTransportError(err)
+	if kind == TransportErrorPolicy {
+		return RetryNever, nil
+	}
+	if p.TransportErrorRetry != nil {
+		perr = safely(func() error { decision = p.TransportErrorRetry(kind, err); return nil })
+		if perr != nil {
+			return RetryNever, perr
+		}
+	}
+	if decision == RetryDefault {
+		decision = DefaultTransportErrorRetry(kind, err)
+	}
+	return decision, nil
+}
@@ -0,0 +1,128 @@
+package jsonapi_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestClassifyTransportError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected jsonapi.TransportError
+	}{
+		{err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expected: jsonapi.TransportErrorConnectionRefused},
+		{err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, expected: jsonapi.TransportErrorConnectionReset},
+		{err: fmt.Errorf("failed: %w", io.EOF), expected: jsonapi.TransportErrorConnectionReset},
+		{err: &net.DNSError{Err: "no such host", IsNotFound: true}, expected: jsonapi.TransportErrorDNS},
+		{err: x509.UnknownAuthorityError{}, expected: jsonapi.TransportErrorCertificate},
+		{err: jsonapi.PinnedCertificateError{}, expected: jsonapi.TransportErrorCertificate},
+		{err: &url.Error{Op: "Post", URL: "/", Err: jsonapi.ForbiddenHostError{Host: "localhost", IP: "127.0.0.1"}}, expected: jsonapi.TransportErrorPolicy},
+		{err: context.DeadlineExceeded, expected: jsonapi.TransportErrorUnknown},
+		{err: errors.New("unknown"), expected: jsonapi.TransportErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected.String(), func(t *testing.T) {
+			if actual := jsonapi.ClassifyTransportError(tt.err); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTransportErrorRetry(t *testing.T) {
+	t.Run("certificate errors are not retried", func(t *testing.T) {
+		var calls int
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+		}))
+		defer s.Close()
+		var kinds []jsonapi.TransportError
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithRetry(3, 0),
+			jsonapi.WithTransportErrorRetry(func(kind jsonapi.TransportError, err error) jsonapi.RetryDecision {
+				kinds = append(kinds, kind)
+				return jsonapi.RetryDefault
+			}))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if len(kinds) != 1 || kinds[0] != jsonapi.TransportErrorCertificate {
+			t.Errorf("expected a single certificate error, got %v", kinds)
+		}
+	})
+	t.Run("the decision can be overridden", func(t *testing.T) {
+		var calls int
+		client := jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}
+		})
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+			jsonapi.WithClient(client),
+			jsonapi.WithRetry(3, 0),
+			jsonapi.WithTransportErrorRetry(func(kind jsonapi.TransportError, err error) jsonapi.RetryDecision {
+				if kind == jsonapi.TransportErrorConnectionRefused {
+					return jsonapi.RetryNever
+				}
+				return jsonapi.RetryDefault
+			}))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+	t.Run("requests rejected by policy are not retried", func(t *testing.T) {
+		var calls int
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer s.Close()
+		clock := &fakeClock{now: time.Now()}
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL,
+			jsonapi.WithClient(&http.Client{Transport: &http.Transport{}}),
+			jsonapi.WithBlockPrivateNetworks(),
+			jsonapi.WithRetry(4, time.Second),
+			jsonapi.WithClock(clock),
+			jsonapi.WithTransportErrorRetry(func(kind jsonapi.TransportError, err error) jsonapi.RetryDecision {
+				return jsonapi.RetryWithBackoff
+			}))
+		var fhe jsonapi.ForbiddenHostError
+		if !errors.As(err, &fhe) {
+			t.Fatalf("expected ForbiddenHostError, got %v", err)
+		}
+		if calls != 0 || len(clock.sleeps) != 0 {
+			t.Errorf("expected no calls or retries, got %d calls and sleeps %v", calls, clock.sleeps)
+		}
+	})
+	t.Run("reset connections are retried", func(t *testing.T) {
+		var calls int
+		client := jsonapi.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+		})
+		_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+			jsonapi.WithClient(client),
+			jsonapi.WithRetry(3, 0))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 calls, got %d", calls)
+		}
+	})
+}
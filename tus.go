@@ -0,0 +1,216 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const tusVersion = "1.0.0"
+
+// TusUpload uploads a file using the tus resumable upload protocol, see https://tus.io.
+// If an upload is interrupted, call Upload again with the same TusUpload to resume it from
+// the offset stored by the server.
+type TusUpload struct {
+	// Endpoint is the URL used to create uploads.
+	Endpoint string
+	// ChunkSize is the maximum size of each PATCH request. Defaults to 5MiB.
+	ChunkSize int64
+	// Parallel is the number of parts uploaded concurrently. Values greater than 1 require the
+	// server to support the tus concatenation extension.
+	Parallel int
+	// Metadata is sent in the Upload-Metadata header when the upload is created.
+	Metadata map[string]string
+	// URL is the URL of the upload, set once the upload has been created.
+	// Set URL to resume an upload created by another process.
+	URL string
+	// PartURLs are the URLs of the parts of a parallel upload, set once the parts have been created.
+	PartURLs []string
+	// OnProgress is called after each chunk is uploaded, with the total number of bytes uploaded.
+	OnProgress func(uploaded, size int64)
+	// Opts are applied to each request.
+	Opts []Opt
+}
+
+// Upload size bytes from r, resuming from the server's offset if the upload has already been created.
+func (u *TusUpload) Upload(ctx context.Context, r io.ReaderAt, size int64) (err error) {
+	config, err := newConfig(u.Opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	if u.Parallel <= 1 {
+		return u.uploadSingle(ctx, config, r, size)
+	}
+	return u.uploadParallel(ctx, config, r, size)
+}
+
+func (u *TusUpload) uploadSingle(ctx context.Context, config *Config, r io.ReaderAt, size int64) (err error) {
+	var offset int64
+	if u.URL == "" {
+		if u.URL, err = u.create(ctx, config, size, nil); err != nil {
+			return err
+		}
+	} else if offset, err = u.offset(ctx, config, u.URL); err != nil {
+		return err
+	}
+	var uploaded atomic.Int64
+	uploaded.Store(offset)
+	return u.uploadRange(ctx, config, u.URL, io.NewSectionReader(r, 0, size), offset, &uploaded, size)
+}
+
+func (u *TusUpload) uploadParallel(ctx context.Context, config *Config, r io.ReaderAt, size int64) (err error) {
+	if u.URL != "" {
+		return nil
+	}
+	parts := partRanges(size, u.Parallel)
+	if len(u.PartURLs) != len(parts) {
+		u.PartURLs = make([]string, len(parts))
+		for i, p := range parts {
+			if u.PartURLs[i], err = u.create(ctx, config, p[1]-p[0], map[string]string{"Upload-Concat": "partial"}); err != nil {
+				return err
+			}
+		}
+	}
+	var uploaded atomic.Int64
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	for i, p := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			offset, err := u.offset(ctx, config, u.PartURLs[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			uploaded.Add(offset)
+			errs[i] = u.uploadRange(ctx, config, u.PartURLs[i], io.NewSectionReader(r, p[0], p[1]-p[0]), offset, &uploaded, size)
+		}()
+	}
+	wg.Wait()
+	if err = errors.Join(errs...); err != nil {
+		return err
+	}
+	u.URL, err = u.create(ctx, config, -1, map[string]string{"Upload-Concat": "final;" + strings.Join(u.PartURLs, " ")})
+	return err
+}
+
+// partRanges splits size bytes into n ranges of [start, end).
+func partRanges(size int64, n int) (ranges [][2]int64) {
+	partSize := (size + int64(n) - 1) / int64(n)
+	for start := int64(0); start < size; start += partSize {
+		ranges = append(ranges, [2]int64{start, min(start+partSize, size)})
+	}
+	return ranges
+}
+
+func (u *TusUpload) uploadRange(ctx context.Context, config *Config, uploadURL string, r *io.SectionReader, offset int64, uploaded *atomic.Int64, size int64) error {
+	chunkSize := u.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 5 * 1024 * 1024
+	}
+	for offset < r.Size() {
+		n := min(chunkSize, r.Size()-offset)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPatch, uploadURL, io.NewSectionReader(r, offset, n))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.ContentLength = n
+		req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+		res, err := u.do(config, req, "application/offset+octet-stream", http.StatusNoContent)
+		if err != nil {
+			return err
+		}
+		next, err := strconv.ParseInt(res.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse Upload-Offset: %w", err)
+		}
+		uploaded.Add(next - offset)
+		offset = next
+		if u.OnProgress != nil {
+			if err = safely(func() error { u.OnProgress(uploaded.Load(), size); return nil }); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// create creates an upload and returns its URL. A size of -1 omits the Upload-Length header.
+func (u *TusUpload) create(ctx context.Context, config *Config, size int64, header map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.Endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if size >= 0 {
+		req.Header.Set("Upload-Length", strconv.FormatInt(size, 10))
+	}
+	if len(u.Metadata) > 0 && header["Upload-Concat"] != "partial" {
+		req.Header.Set("Upload-Metadata", tusMetadata(u.Metadata))
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	res, err := u.do(config, req, "", http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	location, err := req.URL.Parse(res.Header.Get("Location"))
+	if err != nil || res.Header.Get("Location") == "" {
+		return "", fmt.Errorf("failed to parse upload Location %q", res.Header.Get("Location"))
+	}
+	return location.String(), nil
+}
+
+// offset returns the number of bytes of the upload stored by the server.
+func (u *TusUpload) offset(ctx context.Context, config *Config, uploadURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uploadURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := u.do(config, req, "", http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(res.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse Upload-Offset: %w", err)
+	}
+	return offset, nil
+}
+
+func (u *TusUpload) do(config *Config, req *http.Request, contentType string, expectedStatus int) (*http.Response, error) {
+	req.Header.Set("Tus-Resumable", tusVersion)
+	c := *config
+	if contentType != "" {
+		c.Middleware = append(c.Middleware[:len(c.Middleware):len(c.Middleware)], &requestHeaderMiddleware{"Content-Type", contentType})
+	}
+	res, err := c.raw(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != expectedStatus && !(expectedStatus == http.StatusOK && res.StatusCode == http.StatusNoContent) {
+		return nil, c.newStatusError(res.StatusCode, res.Header, c.readErrorBody(res.Body))
+	}
+	io.Copy(io.Discard, res.Body)
+	return res, nil
+}
+
+// tusMetadata encodes the Upload-Metadata header.
+func tusMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		pairs = append(pairs, k+" "+base64.StdEncoding.EncodeToString([]byte(v)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
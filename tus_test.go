@@ -0,0 +1,140 @@
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+// tusServer is an in-memory tus server that supports the creation and concatenation extensions.
+type tusServer struct {
+	uploads map[string]*bytes.Buffer
+	lengths map[string]int64
+	// failAfter fails PATCH requests after the given number of successful requests.
+	failAfter int
+	patches   int
+	m         sync.Mutex
+}
+
+func newTusServer() *tusServer {
+	return &tusServer{uploads: map[string]*bytes.Buffer{}, lengths: map[string]int64{}, failAfter: -1}
+}
+
+func (s *tusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if r.Header.Get("Tus-Resumable") != "1.0.0" {
+		http.Error(w, "missing Tus-Resumable", http.StatusPreconditionFailed)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		id := fmt.Sprintf("/files/%d", len(s.uploads))
+		s.uploads[id] = &bytes.Buffer{}
+		if concat, ok := strings.CutPrefix(r.Header.Get("Upload-Concat"), "final;"); ok {
+			for _, part := range strings.Fields(concat) {
+				s.uploads[id].Write(s.uploads[strings.TrimPrefix(part, "http://example.com")].Bytes())
+			}
+		}
+		s.lengths[id], _ = strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		w.Header().Set("Location", id)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead:
+		w.Header().Set("Upload-Offset", strconv.Itoa(s.uploads[r.URL.Path].Len()))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+			http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		if s.failAfter >= 0 && s.patches >= s.failAfter {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		s.patches++
+		upload := s.uploads[r.URL.Path]
+		if r.Header.Get("Upload-Offset") != strconv.Itoa(upload.Len()) {
+			http.Error(w, "offset mismatch", http.StatusConflict)
+			return
+		}
+		io.Copy(upload, r.Body)
+		w.Header().Set("Upload-Offset", strconv.Itoa(upload.Len()))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestTusUpload(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 10))
+
+	t.Run("chunks are uploaded in order", func(t *testing.T) {
+		s := newTusServer()
+		var progress []int64
+		u := &jsonapi.TusUpload{
+			Endpoint:   "http://example.com/files",
+			ChunkSize:  30,
+			Metadata:   map[string]string{"filename": "data.txt"},
+			OnProgress: func(uploaded, size int64) { progress = append(progress, uploaded) },
+			Opts:       []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: s})},
+		}
+		if err := u.Upload(context.Background(), bytes.NewReader(data), int64(len(data))); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !bytes.Equal(s.uploads["/files/0"].Bytes(), data) {
+			t.Errorf("unexpected upload %q", s.uploads["/files/0"].String())
+		}
+		if fmt.Sprint(progress) != "[30 60 90 100]" {
+			t.Errorf("unexpected progress %v", progress)
+		}
+	})
+	t.Run("interrupted uploads are resumed", func(t *testing.T) {
+		s := newTusServer()
+		s.failAfter = 2
+		u := &jsonapi.TusUpload{
+			Endpoint:  "http://example.com/files",
+			ChunkSize: 30,
+			Opts:      []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: s})},
+		}
+		err := u.Upload(context.Background(), bytes.NewReader(data), int64(len(data)))
+		var ise jsonapi.InvalidStatusError
+		if !errors.As(err, &ise) {
+			t.Fatalf("expected InvalidStatusError, got %v", err)
+		}
+		s.failAfter = -1
+		if err = u.Upload(context.Background(), bytes.NewReader(data), int64(len(data))); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if s.patches != 4 {
+			t.Errorf("expected 4 PATCH requests, got %d", s.patches)
+		}
+		if !bytes.Equal(s.uploads["/files/0"].Bytes(), data) {
+			t.Errorf("unexpected upload %q", s.uploads["/files/0"].String())
+		}
+	})
+	t.Run("parts are uploaded in parallel and concatenated", func(t *testing.T) {
+		s := newTusServer()
+		u := &jsonapi.TusUpload{
+			Endpoint:  "http://example.com/files",
+			ChunkSize: 10,
+			Parallel:  3,
+			Opts:      []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: s})},
+		}
+		if err := u.Upload(context.Background(), bytes.NewReader(data), int64(len(data))); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(u.PartURLs) != 3 {
+			t.Errorf("expected 3 parts, got %v", u.PartURLs)
+		}
+		if !bytes.Equal(s.uploads["/files/3"].Bytes(), data) {
+			t.Errorf("unexpected upload %q", s.uploads["/files/3"].String())
+		}
+	})
+}
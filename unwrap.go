@@ -0,0 +1,80 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithUnwrap decodes the response from within an envelope, e.g. {"data": {...}, "meta": {...}}.
+// The selector is either the name of a top-level field, e.g. "data", or a JSON pointer, e.g. "/data/items".
+func WithUnwrap(selector string) Opt {
+	return func(c *Config) error {
+		c.Unwrap = selector
+		return nil
+	}
+}
+
+// WithUnwrapMeta decodes the value at the selector in the response envelope into meta,
+// which must be a pointer. Use it with WithUnwrap to access envelope fields such as paging metadata.
+func WithUnwrapMeta(selector string, meta any) Opt {
+	return func(c *Config) error {
+		if c.UnwrapMeta == nil {
+			c.UnwrapMeta = map[string]any{}
+		}
+		c.UnwrapMeta[selector] = meta
+		return nil
+	}
+}
+
+// selectJSON returns the value at the selector, which is a top-level field name or a JSON pointer.
+func selectJSON(body []byte, selector string) (value json.RawMessage, err error) {
+	tokens := []string{selector}
+	if strings.HasPrefix(selector, "/") {
+		tokens = jsonPointerTokens(selector)
+	}
+	value = body
+	for _, t := range tokens {
+		if value, err = selectJSONToken(value, t); err != nil {
+			return nil, fmt.Errorf("failed to select %q: %w", selector, err)
+		}
+	}
+	return value, nil
+}
+
+// jsonPointerTokens splits a JSON pointer, e.g. "/a~1b/0", into unescaped tokens.
+func jsonPointerTokens(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens
+}
+
+func selectJSONToken(value json.RawMessage, token string) (json.RawMessage, error) {
+	trimmed := strings.TrimSpace(string(value))
+	if strings.HasPrefix(trimmed, "[") {
+		var array []json.RawMessage
+		if err := json.Unmarshal(value, &array); err != nil {
+			return nil, err
+		}
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(array) {
+			return nil, fmt.Errorf("index %q not found", token)
+		}
+		return array[index], nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, err
+	}
+	v, ok := object[token]
+	if !ok {
+		return nil, fmt.Errorf("field %q not found", token)
+	}
+	return v, nil
+}
@@ -0,0 +1,52 @@
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithUnwrap(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"items":["item1","item2"]},"meta":{"total":2}}`))
+	})
+	opts := []jsonapi.Opt{jsonapi.WithClient(testClient{Handler: h})}
+	ctx := context.Background()
+
+	t.Run("top-level fields can be selected", func(t *testing.T) {
+		var meta struct {
+			Total int `json:"total"`
+		}
+		resp, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", append(opts, jsonapi.WithUnwrap("data"), jsonapi.WithUnwrapMeta("meta", &meta))...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diff := cmp.Diff(expectedItemsGetResponse, resp); diff != "" {
+			t.Error(diff)
+		}
+		if meta.Total != 2 {
+			t.Errorf("expected total of 2, got %d", meta.Total)
+		}
+	})
+	t.Run("JSON pointers can be used", func(t *testing.T) {
+		resp, _, err := jsonapi.Get[string](ctx, "/items", append(opts, jsonapi.WithUnwrap("/data/items/1"))...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp != "item2" {
+			t.Errorf("expected item2, got %q", resp)
+		}
+	})
+	t.Run("missing fields are errors", func(t *testing.T) {
+		_, _, err := jsonapi.Get[itemsGetResponse](ctx, "/items", append(opts, jsonapi.WithUnwrap("missing"))...)
+		var ije jsonapi.InvalidJSONError
+		if !errors.As(err, &ije) {
+			t.Errorf("expected InvalidJSONError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package jsonapi
+
+import (
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// VersionStrategy sets the requested API version on a request, and reads the version
+// reported by the server. See VersionHeader, VersionMediaType and VersionPathPrefix.
+type VersionStrategy struct {
+	// Set sets the version on the request.
+	Set func(req *http.Request, version string)
+	// Reported returns the version reported by the server, or an empty string if it isn't reported.
+	Reported func(res *http.Response) string
+}
+
+// VersionHeader sends the version in the named header, e.g. "X-API-Version: 2".
+// The server's version is read from the same response header.
+func VersionHeader(name string) VersionStrategy {
+	return VersionStrategy{
+		Set: func(req *http.Request, version string) {
+			req.Header.Set(name, version)
+		},
+		Reported: func(res *http.Response) string {
+			return res.Header.Get(name)
+		},
+	}
+}
+
+// VersionMediaType sends the version as a parameter of the media types in the Accept header,
+// e.g. "Accept: application/json; version=2". The server's version is read from the same
+// parameter of the Content-Type response header.
+func VersionMediaType(parameter string) VersionStrategy {
+	return VersionStrategy{
+		Set: func(req *http.Request, version string) {
+			accept := req.Header.Get("Accept")
+			if accept == "" {
+				accept = "application/json"
+			}
+			ranges := strings.Split(accept, ",")
+			for i, r := range ranges {
+				ranges[i] = strings.TrimSpace(r) + "; " + parameter + "=" + version
+			}
+			req.Header.Set("Accept", strings.Join(ranges, ", "))
+		},
+		Reported: func(res *http.Response) string {
+			_, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+			if err != nil {
+				return ""
+			}
+			return params[parameter]
+		},
+	}
+}
+
+// VersionPathPrefix prefixes the request path with the version, e.g. "/v2/items".
+// The server's version isn't reported.
+func VersionPathPrefix() VersionStrategy {
+	return VersionStrategy{
+		Set: func(req *http.Request, version string) {
+			req.URL.Path = "/" + version + req.URL.Path
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = "/" + version + req.URL.RawPath
+			}
+		},
+		Reported: func(res *http.Response) string {
+			return ""
+		},
+	}
+}
+
+// WithAPIVersion requests the version of the API using the strategy. If the server reports
+// a different version, a warning is logged using the default slog logger.
+func WithAPIVersion(version string, strategy VersionStrategy) Opt {
+	return func(c *Config) error {
+		c.Interceptors = append(c.Interceptors, func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				strategy.Set(req, version)
+				res, err := next.Do(req)
+				if err != nil {
+					return res, err
+				}
+				if reported := strategy.Reported(res); reported != "" && reported != version {
+					slog.Warn("jsonapi: API version mismatch",
+						slog.String("url", req.URL.String()),
+						slog.String("requested", version),
+						slog.String("reported", reported))
+				}
+				return res, nil
+			})
+		})
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+	"github.com/a-h/respond"
+)
+
+func TestWithAPIVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		strategy       jsonapi.VersionStrategy
+		reportedHeader http.Header
+		expectedHeader http.Header
+		expectedPath   string
+		expectWarning  bool
+	}{
+		{
+			name:           "header",
+			strategy:       jsonapi.VersionHeader("X-API-Version"),
+			reportedHeader: http.Header{"X-Api-Version": []string{"2"}},
+			expectedHeader: http.Header{"X-Api-Version": []string{"2"}},
+			expectedPath:   "/items",
+		},
+		{
+			name:           "header mismatch",
+			strategy:       jsonapi.VersionHeader("X-API-Version"),
+			reportedHeader: http.Header{"X-Api-Version": []string{"1"}},
+			expectedHeader: http.Header{"X-Api-Version": []string{"2"}},
+			expectedPath:   "/items",
+			expectWarning:  true,
+		},
+		{
+			name:           "media type",
+			strategy:       jsonapi.VersionMediaType("version"),
+			reportedHeader: http.Header{"Content-Type": []string{"application/json; version=1"}},
+			expectedHeader: http.Header{"Accept": []string{"application/json; version=2"}},
+			expectedPath:   "/items",
+			expectWarning:  true,
+		},
+		{
+			name:         "path prefix",
+			strategy:     jsonapi.VersionPathPrefix(),
+			expectedPath: "/2/items",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			defer slog.SetDefault(slog.Default())
+			slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+			var header http.Header
+			var path string
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header, path = r.Header, r.URL.Path
+				for k, v := range tt.reportedHeader {
+					w.Header()[k] = v
+				}
+				respond.WithJSON(w, expectedItemsGetResponse, http.StatusOK)
+			})
+			_, _, err := jsonapi.Get[itemsGetResponse](context.Background(), "/items",
+				jsonapi.WithClient(testClient{Handler: h}),
+				jsonapi.WithAPIVersion("2", tt.strategy))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for k := range tt.expectedHeader {
+				if header.Get(k) != tt.expectedHeader.Get(k) {
+					t.Errorf("expected %s header %q, got %q", k, tt.expectedHeader.Get(k), header.Get(k))
+				}
+			}
+			if path != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, path)
+			}
+			if warned := strings.Contains(logs.String(), "API version mismatch"); warned != tt.expectWarning {
+				t.Errorf("expected warning=%v, got logs %q", tt.expectWarning, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Warmup sends a HEAD request to each of the URLs, so that DNS is resolved, connections and
+// TLS sessions are established and kept in the connection pool, and tokens are fetched by
+// auth middleware, before the first real request is made, e.g. after a deployment.
+//
+// The opts should be the same as those used for subsequent requests, so that the same
+// connection pool is used. The response status is ignored, only failures to connect, or to
+// apply middleware, are returned.
+func Warmup(ctx context.Context, urls []string, opts ...Opt) error {
+	config, err := newConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	return config.warmup(ctx, urls)
+}
+
+func (config *Config) warmup(ctx context.Context, urls []string) error {
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := warmupURL(ctx, config, url); err != nil {
+				errs[i] = fmt.Errorf("failed to warm up %q: %w", url, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func warmupURL(ctx context.Context, config *Config, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := config.raw(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, res.Body)
+	return res.Body.Close()
+}
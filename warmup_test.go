@@ -0,0 +1,60 @@
+package jsonapi_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+func TestWarmup(t *testing.T) {
+	t.Run("connections are reused by subsequent requests", func(t *testing.T) {
+		var conns, heads atomic.Int32
+		routes := createTestRoutes()
+		s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				heads.Add(1)
+			}
+			routes.ServeHTTP(w, r)
+		}))
+		s.Config.ConnState = func(c net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		s.Start()
+		defer s.Close()
+		client := jsonapi.WithClient(&http.Client{Transport: &http.Transport{}})
+
+		if err := jsonapi.Warmup(context.Background(), []string{s.URL + "/items/get/ok"}, client); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, _, err := jsonapi.Get[itemsGetResponse](context.Background(), s.URL+"/items/get/ok", client); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if n := heads.Load(); n != 1 {
+			t.Errorf("expected 1 warmup request, got %d", n)
+		}
+		if n := conns.Load(); n != 1 {
+			t.Errorf("expected 1 connection, got %d", n)
+		}
+	})
+	t.Run("error statuses are ignored", func(t *testing.T) {
+		err := jsonapi.Warmup(context.Background(), []string{"/items/get/404", "/items/get/500"},
+			jsonapi.WithClient(testClient{Handler: createTestRoutes()}))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+	t.Run("connection failures are returned", func(t *testing.T) {
+		s := httptest.NewServer(createTestRoutes())
+		s.Close()
+		if err := jsonapi.Warmup(context.Background(), []string{s.URL}); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package jsonapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWebhookTolerance is the tolerance used by verifiers with a Tolerance of zero or less,
+// matching the five minutes recommended by Stripe and Slack.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// DefaultMaxWebhookBodyBytes is the body size limit used by verifiers with a MaxBodyBytes of
+// zero or less, matching GitHub's 25MB limit on webhook payloads.
+const DefaultMaxWebhookBodyBytes int64 = 25 << 20
+
+// WebhookVerifier verifies the signature of an inbound webhook request.
+type WebhookVerifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// InvalidSignatureError is returned when a signature is missing, malformed, expired or does not match.
+type InvalidSignatureError struct {
+	Reason string `json:"reason"`
+}
+
+func (e InvalidSignatureError) Error() string {
+	return fmt.Sprintf("invalid signature: %s", e.Reason)
+}
+
+// HMACWebhookVerifier verifies hex encoded HMAC-SHA256 signatures.
+type HMACWebhookVerifier struct {
+	Secret []byte
+	// SignatureHeader is the name of the header containing the signature, e.g. "X-Hub-Signature-256".
+	SignatureHeader string
+	// SignaturePrefix is stripped from the signature header value before comparison, e.g. "sha256=".
+	SignaturePrefix string
+	// TimestampHeader is the optional name of a header containing the Unix timestamp of the request.
+	// If set, the timestamp must be within Tolerance of the current time.
+	TimestampHeader string
+	// Tolerance is the maximum age of the timestamp. If zero or less, DefaultWebhookTolerance is used.
+	Tolerance time.Duration
+	// Payload constructs the signed payload from the timestamp and body.
+	// If nil, the body is signed as-is.
+	Payload func(timestamp string, body []byte) []byte
+	// MaxBodyBytes limits the size of the body read by DecodeWebhook. If zero or less,
+	// DefaultMaxWebhookBodyBytes is used.
+	MaxBodyBytes int64
+	now          func() time.Time
+}
+
+// NewGitHubWebhookVerifier verifies GitHub's X-Hub-Signature-256 header.
+func NewGitHubWebhookVerifier(secret string) *HMACWebhookVerifier {
+	return &HMACWebhookVerifier{
+		Secret:          []byte(secret),
+		SignatureHeader: "X-Hub-Signature-256",
+		SignaturePrefix: "sha256=",
+		now:             time.Now,
+	}
+}
+
+// NewSlackWebhookVerifier verifies Slack's X-Slack-Signature and X-Slack-Request-Timestamp headers.
+func NewSlackWebhookVerifier(secret string, tolerance time.Duration) *HMACWebhookVerifier {
+	return &HMACWebhookVerifier{
+		Secret:          []byte(secret),
+		SignatureHeader: "X-Slack-Signature",
+		SignaturePrefix: "v0=",
+		TimestampHeader: "X-Slack-Request-Timestamp",
+		Tolerance:       tolerance,
+		Payload: func(timestamp string, body []byte) []byte {
+			return []byte("v0:" + timestamp + ":" + string(body))
+		},
+		now: time.Now,
+	}
+}
+
+func (v *HMACWebhookVerifier) Verify(header http.Header, body []byte) error {
+	signature := header.Get(v.SignatureHeader)
+	if signature == "" {
+		return InvalidSignatureError{Reason: fmt.Sprintf("missing %s header", v.SignatureHeader)}
+	}
+	if !strings.HasPrefix(signature, v.SignaturePrefix) {
+		return InvalidSignatureError{Reason: fmt.Sprintf("expected %s header to start with %q", v.SignatureHeader, v.SignaturePrefix)}
+	}
+	signature = strings.TrimPrefix(signature, v.SignaturePrefix)
+	var timestamp string
+	if v.TimestampHeader != "" {
+		timestamp = header.Get(v.TimestampHeader)
+		if err := checkTimestamp(timestamp, v.Tolerance, v.now); err != nil {
+			return err
+		}
+	}
+	payload := body
+	if v.Payload != nil {
+		payload = v.Payload(timestamp, body)
+	}
+	return checkHMAC(v.Secret, payload, signature)
+}
+
+// StripeWebhookVerifier verifies Stripe's Stripe-Signature header, which contains
+// a timestamp and one or more v1 signatures, e.g. "t=1492774577,v1=5257a869...".
+type StripeWebhookVerifier struct {
+	Secret []byte
+	// Tolerance is the maximum age of the timestamp. If zero or less, DefaultWebhookTolerance is used.
+	Tolerance time.Duration
+	// MaxBodyBytes limits the size of the body read by DecodeWebhook. If zero or less,
+	// DefaultMaxWebhookBodyBytes is used.
+	MaxBodyBytes int64
+	now          func() time.Time
+}
+
+// NewStripeWebhookVerifier verifies Stripe's Stripe-Signature header.
+func NewStripeWebhookVerifier(secret string, tolerance time.Duration) *StripeWebhookVerifier {
+	return &StripeWebhookVerifier{
+		Secret:    []byte(secret),
+		Tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+func (v *StripeWebhookVerifier) Verify(header http.Header, body []byte) error {
+	value := header.Get("Stripe-Signature")
+	if value == "" {
+		return InvalidSignatureError{Reason: "missing Stripe-Signature header"}
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(value, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	if err := checkTimestamp(timestamp, v.Tolerance, v.now); err != nil {
+		return err
+	}
+	payload := []byte(timestamp + "." + string(body))
+	for _, signature := range signatures {
+		if checkHMAC(v.Secret, payload, signature) == nil {
+			return nil
+		}
+	}
+	return InvalidSignatureError{Reason: "no matching v1 signature"}
+}
+
+func checkTimestamp(timestamp string, tolerance time.Duration, now func() time.Time) error {
+	if timestamp == "" {
+		return InvalidSignatureError{Reason: "missing timestamp"}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return InvalidSignatureError{Reason: fmt.Sprintf("invalid timestamp %q", timestamp)}
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	if now == nil {
+		now = time.Now
+	}
+	age := now().Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return InvalidSignatureError{Reason: fmt.Sprintf("timestamp outside of tolerance of %v", tolerance)}
+	}
+	return nil
+}
+
+func checkHMAC(secret, payload []byte, signature string) error {
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return InvalidSignatureError{Reason: "signature is not valid hex"}
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), actual) {
+		return InvalidSignatureError{Reason: "signature mismatch"}
+	}
+	return nil
+}
+
+func (v *HMACWebhookVerifier) maxBodyBytes() int64 {
+	return v.MaxBodyBytes
+}
+
+func (v *StripeWebhookVerifier) maxBodyBytes() int64 {
+	return v.MaxBodyBytes
+}
+
+// DecodeWebhook reads the body of an inbound webhook request, verifies its signature
+// and decodes the JSON payload. Bodies larger than the verifier's MaxBodyBytes are rejected,
+// or DefaultMaxWebhookBodyBytes for other verifiers.
+func DecodeWebhook[T any](r *http.Request, verifier WebhookVerifier) (payload T, err error) {
+	defer r.Body.Close()
+	limit := DefaultMaxWebhookBodyBytes
+	if v, ok := verifier.(interface{ maxBodyBytes() int64 }); ok && v.maxBodyBytes() > 0 {
+		limit = v.maxBodyBytes()
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, limit))
+	if err != nil {
+		return payload, fmt.Errorf("failed to read webhook body: %w", err)
+	}
+	if err = verifier.Verify(r.Header, body); err != nil {
+		return payload, err
+	}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return payload, fmt.Errorf("failed to unmarshal webhook body: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,95 @@
+package jsonapi_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/jsonapi"
+)
+
+type webhookPayload struct {
+	Action string `json:"action"`
+}
+
+func sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestDecodeWebhook(t *testing.T) {
+	body := `{"action":"opened"}`
+	t.Run("GitHub signatures are verified", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		r.Header.Set("X-Hub-Signature-256", "sha256="+sign("secret", body))
+		payload, err := jsonapi.DecodeWebhook[webhookPayload](r, jsonapi.NewGitHubWebhookVerifier("secret"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if payload.Action != "opened" {
+			t.Errorf("expected action 'opened', got %q", payload.Action)
+		}
+	})
+	t.Run("incorrect signatures are rejected", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		r.Header.Set("X-Hub-Signature-256", "sha256="+sign("wrong", body))
+		_, err := jsonapi.DecodeWebhook[webhookPayload](r, jsonapi.NewGitHubWebhookVerifier("secret"))
+		var ise jsonapi.InvalidSignatureError
+		if !errors.As(err, &ise) {
+			t.Fatalf("expected InvalidSignatureError, got %v", err)
+		}
+	})
+	t.Run("Stripe signatures are verified", func(t *testing.T) {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		r.Header.Set("Stripe-Signature", "t="+ts+",v1=abcd,v1="+sign("secret", ts+"."+body))
+		_, err := jsonapi.DecodeWebhook[webhookPayload](r, jsonapi.NewStripeWebhookVerifier("secret", time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+	t.Run("Slack signatures outside of the tolerance are rejected", func(t *testing.T) {
+		ts := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		r.Header.Set("X-Slack-Request-Timestamp", ts)
+		r.Header.Set("X-Slack-Signature", "v0="+sign("secret", "v0:"+ts+":"+body))
+		_, err := jsonapi.DecodeWebhook[webhookPayload](r, jsonapi.NewSlackWebhookVerifier("secret", time.Minute))
+		var ise jsonapi.InvalidSignatureError
+		if !errors.As(err, &ise) {
+			t.Fatalf("expected InvalidSignatureError, got %v", err)
+		}
+	})
+	t.Run("a tolerance of zero uses the default tolerance", func(t *testing.T) {
+		for _, tt := range []struct {
+			age      time.Duration
+			expectOK bool
+		}{{age: time.Minute, expectOK: true}, {age: time.Hour, expectOK: false}} {
+			ts := strconv.FormatInt(time.Now().Add(-tt.age).Unix(), 10)
+			r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+			r.Header.Set("Stripe-Signature", "t="+ts+",v1="+sign("secret", ts+"."+body))
+			_, err := jsonapi.DecodeWebhook[webhookPayload](r, jsonapi.NewStripeWebhookVerifier("secret", 0))
+			if ok := err == nil; ok != tt.expectOK {
+				t.Errorf("%v old timestamp: expected ok=%v, got %v", tt.age, tt.expectOK, err)
+			}
+		}
+	})
+	t.Run("bodies larger than the limit are rejected", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		r.Header.Set("X-Hub-Signature-256", "sha256="+sign("secret", body))
+		verifier := jsonapi.NewGitHubWebhookVerifier("secret")
+		verifier.MaxBodyBytes = 8
+		_, err := jsonapi.DecodeWebhook[webhookPayload](r, verifier)
+		var mbe *http.MaxBytesError
+		if !errors.As(err, &mbe) {
+			t.Errorf("expected MaxBytesError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ZeroValues controls how zero value struct fields are encoded in request bodies.
+type ZeroValues int
+
+const (
+	// ZeroValuesDefault encodes zero values as specified by the json struct tags.
+	ZeroValuesDefault ZeroValues = iota
+	// ZeroValuesOmit omits zero value fields, as if every field was tagged with omitempty.
+	ZeroValuesOmit
+	// ZeroValuesNull encodes zero value fields as null.
+	ZeroValuesNull
+	// ZeroValuesInclude encodes zero value fields as their zero value, even if tagged with omitempty.
+	ZeroValuesInclude
+)
+
+// WithZeroValues sets how zero value struct fields are encoded in request bodies, overriding
+// omitempty tags. This is useful for PATCH requests, where null and absent fields differ.
+func WithZeroValues(z ZeroValues) Opt {
+	return func(c *Config) error {
+		c.ZeroValues = z
+		return nil
+	}
+}
+
+// applyZeroValues walks the Go value alongside the JSON it was marshaled to, and
+// updates the JSON for zero value struct fields.
+func applyZeroValues(rv reflect.Value, tree any, z ZeroValues) (any, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return tree, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		object, ok := tree.(map[string]any)
+		if !ok {
+			return tree, nil
+		}
+		for _, f := range jsonFields(rv.Type()) {
+			fv, err := rv.FieldByIndexErr(f.Index)
+			if err != nil {
+				continue
+			}
+			if !fv.IsZero() {
+				if object[f.Name], err = applyZeroValues(fv, object[f.Name], z); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			switch z {
+			case ZeroValuesOmit:
+				delete(object, f.Name)
+			case ZeroValuesNull:
+				object[f.Name] = nil
+			case ZeroValuesInclude:
+				if _, ok := object[f.Name]; ok {
+					continue
+				}
+				buf, err := json.Marshal(fv.Interface())
+				if err != nil {
+					return nil, err
+				}
+				if object[f.Name], err = decodeJSONTree(buf); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := tree.([]any)
+		if !ok || len(array) != rv.Len() {
+			return tree, nil
+		}
+		for i := range array {
+			var err error
+			if array[i], err = applyZeroValues(rv.Index(i), array[i], z); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		object, ok := tree.(map[string]any)
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			return tree, nil
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			var err error
+			if object[key], err = applyZeroValues(iter.Value(), object[key], z); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tree, nil
+}
@@ -0,0 +1,60 @@
+package jsonapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/jsonapi"
+)
+
+type patchUser struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email,omitempty"`
+	Age      int      `json:"age"`
+	Tags     []string `json:"tags,omitempty"`
+	Settings struct {
+		Theme string `json:"theme"`
+	} `json:"settings"`
+}
+
+func TestWithZeroValues(t *testing.T) {
+	user := patchUser{Name: "Alice"}
+	tests := []struct {
+		name     string
+		z        jsonapi.ZeroValues
+		expected string
+	}{
+		{
+			name:     "default",
+			z:        jsonapi.ZeroValuesDefault,
+			expected: `{"name":"Alice","age":0,"settings":{"theme":""}}`,
+		},
+		{
+			name:     "omit",
+			z:        jsonapi.ZeroValuesOmit,
+			expected: `{"name":"Alice"}`,
+		},
+		{
+			name:     "null",
+			z:        jsonapi.ZeroValuesNull,
+			expected: `{"age":null,"email":null,"name":"Alice","settings":null,"tags":null}`,
+		},
+		{
+			name:     "include",
+			z:        jsonapi.ZeroValuesInclude,
+			expected: `{"age":0,"email":"","name":"Alice","settings":{"theme":""},"tags":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			_, err := jsonapi.Put[patchUser, map[string]any](context.Background(), "/users/1", user, jsonapi.WithClient(echoClient(&received)), jsonapi.WithZeroValues(tt.z))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if received != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, received)
+			}
+		})
+	}
+}